@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/go-martini/martini"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// authAdmin allows requests carrying the configured admin bearer token.
+func (a *App) authAdmin(res http.ResponseWriter, r *http.Request) {
+	if !checkBearerToken(r, a.Config.AdminToken) {
+		res.WriteHeader(http.StatusUnauthorized)
+	}
+}
+
+// adminCategoryRequest is the body of the request changing a category, omitted fields are left intact.
+type adminCategoryRequest struct {
+	Emoji       *string `json:"emoji"`
+	Description *string `json:"description"`
+	Parent      *string `json:"parent"`
+}
+
+// adminTranslationRequest is the body of the request setting a translation of a category.
+type adminTranslationRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// handleAdminCategories lists the categories with their translations.
+func (a *App) handleAdminCategories(res http.ResponseWriter, r *http.Request) {
+	cats, err := a.CategoryModel.GetAll(r.Context())
+	if err != nil {
+		log.Printf("[admin] get categories: %v", err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(res, cats)
+}
+
+// handleAdminSetCategory changes the emoji, the default description and the parent of a category.
+func (a *App) handleAdminSetCategory(res http.ResponseWriter, r *http.Request, params martini.Params) {
+	var req adminCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cat, ok := a.adminGetCategory(res, r, params["id"])
+	if !ok {
+		return
+	}
+	if req.Emoji != nil {
+		cat.Emoji = strings.TrimSpace(*req.Emoji)
+	}
+	if req.Description != nil {
+		cat.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Parent != nil {
+		parentID := strings.TrimSpace(*req.Parent)
+		cats, err := a.CategoryModel.GetAll(r.Context())
+		if err != nil {
+			log.Printf("[admin] get categories: %v", err)
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if err := model.ValidateCategoryParent(cats, *cat, parentID); err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+		cat.ParentID = parentID
+	}
+	a.adminSaveCategory(res, r, cat)
+}
+
+// handleAdminSetCategoryTranslation sets the name and the description of a category in a language,
+// an empty translation is removed.
+func (a *App) handleAdminSetCategoryTranslation(res http.ResponseWriter, r *http.Request, params martini.Params) {
+	var req adminTranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lang := strings.ToLower(strings.TrimSpace(params["lang"]))
+	if len(lang) == 0 {
+		http.Error(res, "language is required", http.StatusBadRequest)
+		return
+	}
+	cat, ok := a.adminGetCategory(res, r, params["id"])
+	if !ok {
+		return
+	}
+	cat.SetTranslation(model.CategoryTranslation{
+		Lang:        lang,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+	})
+	a.adminSaveCategory(res, r, cat)
+}
+
+// adminGetCategory loads the category responding with an error if it fails.
+func (a *App) adminGetCategory(res http.ResponseWriter, r *http.Request, id string) (*model.Category, bool) {
+	cat, err := a.CategoryModel.Get(r.Context(), id)
+	if err == model.ErrNotFound {
+		res.WriteHeader(http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("[admin] get category %q: %v", id, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	return cat, true
+}
+
+// adminSaveCategory saves the category responding with it.
+func (a *App) adminSaveCategory(res http.ResponseWriter, r *http.Request, cat *model.Category) {
+	if err := a.CategoryModel.Save(r.Context(), cat); err != nil {
+		log.Printf("[admin] save category %q: %v", cat.ID, err)
+		res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeJSON(res, cat)
+}
+
+// writeJSON responds with the value encoded as JSON.
+func writeJSON(res http.ResponseWriter, v interface{}) {
+	res.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(res).Encode(v); err != nil {
+		log.Printf("[web] encode response: %v", err)
+	}
+}
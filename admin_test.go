@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// testCategoryModel keeps categories in memory.
+type testCategoryModel struct {
+	model.CategoryModel
+	cats map[string]*model.Category
+}
+
+func (m *testCategoryModel) Get(_ context.Context, id string) (*model.Category, error) {
+	cat, ok := m.cats[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	c := *cat
+	return &c, nil
+}
+
+func (m *testCategoryModel) GetAll(_ context.Context) ([]model.Category, error) {
+	cats := make([]model.Category, 0, len(m.cats))
+	for _, cat := range m.cats {
+		cats = append(cats, *cat)
+	}
+	return cats, nil
+}
+
+func (m *testCategoryModel) Save(_ context.Context, c *model.Category) error {
+	m.cats[c.ID] = c
+	return nil
+}
+
+func TestApp_authAdmin(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		headers    map[string]string
+		wantStatus int
+	}{
+		{
+			name:       "Unauthorized",
+			config:     Config{AdminToken: "secret"},
+			headers:    map[string]string{},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "CronToken",
+			config:     Config{AdminToken: "secret", CronToken: "cron"},
+			headers:    map[string]string{"Authorization": "Bearer cron"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "AppEngineCron",
+			config:     Config{AdminToken: "secret", AppEngine: true},
+			headers:    map[string]string{"X-Appengine-Cron": "true"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "NoTokenConfigured",
+			config:     Config{},
+			headers:    map[string]string{"Authorization": "Bearer "},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Authorized",
+			config:     Config{AdminToken: "secret"},
+			headers:    map[string]string{"Authorization": "Bearer secret"},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, testData := range tests {
+		t.Run(testData.name, func(t *testing.T) {
+			test := NewAppTestWithConfig(testData.config)
+			test.httpServer.AddRoute("GET", "/admin-endpoint", test.app.authAdmin, func() string {
+				return "ok"
+			})
+
+			gotStatus, _, err := test.Request("GET", "/admin-endpoint", nil, testData.headers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotStatus != testData.wantStatus {
+				t.Errorf("got response %d, want %d", gotStatus, testData.wantStatus)
+			}
+		})
+	}
+}
+
+func TestApp_handleAdminSetCategoryTranslation(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		body       string
+		wantStatus int
+		wantName   string
+	}{
+		{
+			name:       "NotFound",
+			url:        "/admin/categories/cat2/translations/ru",
+			body:       `{"name":"Новости"}`,
+			wantStatus: http.StatusNotFound,
+			wantName:   "Новости (RU)",
+		},
+		{
+			name:       "InvalidBody",
+			url:        "/admin/categories/cat1/translations/ru",
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+			wantName:   "Новости (RU)",
+		},
+		{
+			name:       "Set",
+			url:        "/admin/categories/cat1/translations/RU",
+			body:       `{"name":"Новости"}`,
+			wantStatus: http.StatusOK,
+			wantName:   "Новости",
+		},
+		{
+			name:       "Remove",
+			url:        "/admin/categories/cat1/translations/ru",
+			body:       `{}`,
+			wantStatus: http.StatusOK,
+			wantName:   "News",
+		},
+	}
+
+	for _, testData := range tests {
+		t.Run(testData.name, func(t *testing.T) {
+			test := NewAppTestWithConfig(Config{AdminToken: "secret"})
+			categories := &testCategoryModel{cats: map[string]*model.Category{
+				"cat1": {ID: "cat1", Name: "News", Translations: []model.CategoryTranslation{{Lang: "ru", Name: "Новости (RU)"}}},
+			}}
+			test.app.CategoryModel = categories
+
+			headers := map[string]string{"Authorization": "Bearer secret"}
+			gotStatus, _, err := test.Request("PUT", testData.url, strings.NewReader(testData.body), headers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotStatus != testData.wantStatus {
+				t.Errorf("got response %d, want %d", gotStatus, testData.wantStatus)
+			}
+			if got := categories.cats["cat1"].LocalName("ru"); got != testData.wantName {
+				t.Errorf("got name %q, want %q", got, testData.wantName)
+			}
+		})
+	}
+}
+
+func TestApp_handleAdminSetCategory(t *testing.T) {
+	tests := []struct {
+		name       string
+		parents    map[string]string
+		url        string
+		body       string
+		wantStatus int
+		wantParent string
+	}{
+		{
+			name:       "SetParent",
+			parents:    map[string]string{"world": "", "europe": ""},
+			url:        "/admin/categories/europe",
+			body:       `{"parent":"world"}`,
+			wantStatus: http.StatusOK,
+			wantParent: "world",
+		},
+		{
+			name:       "MoveToTopLevel",
+			parents:    map[string]string{"world": "", "europe": "world"},
+			url:        "/admin/categories/europe",
+			body:       `{"parent":""}`,
+			wantStatus: http.StatusOK,
+			wantParent: "",
+		},
+		{
+			name:       "Loop",
+			parents:    map[string]string{"world": "", "europe": "world"},
+			url:        "/admin/categories/world",
+			body:       `{"parent":"europe"}`,
+			wantStatus: http.StatusBadRequest,
+			wantParent: "",
+		},
+		{
+			name:       "UnknownParent",
+			parents:    map[string]string{"world": "", "europe": ""},
+			url:        "/admin/categories/europe",
+			body:       `{"parent":"nothing"}`,
+			wantStatus: http.StatusBadRequest,
+			wantParent: "",
+		},
+	}
+
+	for _, testData := range tests {
+		t.Run(testData.name, func(t *testing.T) {
+			test := NewAppTestWithConfig(Config{AdminToken: "secret"})
+			categories := &testCategoryModel{cats: map[string]*model.Category{}}
+			for id, parentID := range testData.parents {
+				categories.cats[id] = &model.Category{ID: id, Name: id, ParentID: parentID}
+			}
+			test.app.CategoryModel = categories
+
+			headers := map[string]string{"Authorization": "Bearer secret"}
+			gotStatus, _, err := test.Request("PUT", testData.url, strings.NewReader(testData.body), headers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotStatus != testData.wantStatus {
+				t.Errorf("got response %d, want %d", gotStatus, testData.wantStatus)
+			}
+			id := testData.url[strings.LastIndex(testData.url, "/")+1:]
+			if got := categories.cats[id].ParentID; got != testData.wantParent {
+				t.Errorf("got parent %q, want %q", got, testData.wantParent)
+			}
+		})
+	}
+}
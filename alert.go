@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"gopkg.in/tucnak/telebot.v2"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NotifyAlert sends the update matching the alerts of the subscriber highlighting the matched phrases.
+func (a *App) NotifyAlert(ctx context.Context, s *model.Subscriber, up model.Update, phrases []string) error {
+	if a.Bot == nil {
+		return errors.New("bot is not set up")
+	}
+	to, err := botRecipient(s)
+	if err != nil {
+		return err
+	}
+	text := fmt.Sprintf("🔔 <b>%s</b>\n%s\n%s",
+		html.EscapeString(strings.Join(phrases, ", ")),
+		highlightPhrases(up.Title, phrases),
+		html.EscapeString(up.URL),
+	)
+	if _, err := a.Bot.Send(to, text, &telebot.SendOptions{ParseMode: telebot.ModeHTML}); err != nil {
+		a.helperHandleSendError(ctx, s, err)
+		return err
+	}
+	return nil
+}
+
+// botRecipient resolves the Telegram chat of the subscriber.
+func botRecipient(s *model.Subscriber) (telebot.Recipient, error) {
+	if s == nil || !strings.HasPrefix(s.UserID, "telegram:") {
+		return nil, model.ErrInvalidSubscriber
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(s.UserID, "telegram:"), 10, 64)
+	if err != nil {
+		return nil, model.ErrInvalidSubscriber
+	}
+	return &telebot.Chat{ID: id}, nil
+}
+
+// highlightPhrases escapes the text for HTML markup making the phrases bold.
+func highlightPhrases(text string, phrases []string) string {
+	quoted := make([]string, 0, len(phrases))
+	for _, p := range phrases {
+		if p = strings.TrimSpace(p); p != "" {
+			quoted = append(quoted, regexp.QuoteMeta(p))
+		}
+	}
+	if len(quoted) == 0 {
+		return html.EscapeString(text)
+	}
+	re := regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString("<b>" + html.EscapeString(text[loc[0]:loc[1]]) + "</b>")
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
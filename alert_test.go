@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"gopkg.in/tucnak/telebot.v2"
+	"testing"
+)
+
+func TestHighlightPhrases(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Text    string
+		Phrases []string
+		Want    string
+	}{
+		{Name: "NoPhrases", Text: "Rust & Go", Phrases: nil, Want: "Rust &amp; Go"},
+		{Name: "IgnoreCase", Text: "New Go release", Phrases: []string{"go release"}, Want: "New <b>Go release</b>"},
+		{Name: "Multiple", Text: "Go <3 Rust", Phrases: []string{"rust", "go"}, Want: "<b>Go</b> &lt;3 <b>Rust</b>"},
+		{Name: "SpecialChars", Text: "C++ is back", Phrases: []string{"c++"}, Want: "<b>C++</b> is back"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := highlightPhrases(tt.Text, tt.Phrases); got != tt.Want {
+				t.Errorf("highlightPhrases(%q, %q) = %q; want %q", tt.Text, tt.Phrases, got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestBotRecipient(t *testing.T) {
+	r, err := botRecipient(model.NewSubscriber("telegram:42"))
+	if err != nil {
+		t.Fatalf("botRecipient(): %v", err)
+	}
+	if chat, ok := r.(*telebot.Chat); !ok || chat.ID != 42 {
+		t.Errorf("botRecipient() = %v; want chat 42", r)
+	}
+	if _, err := botRecipient(model.NewSubscriber("email:42")); err != model.ErrInvalidSubscriber {
+		t.Errorf("botRecipient(): got %v; want ErrInvalidSubscriber", err)
+	}
+}
@@ -3,8 +3,17 @@ package main
 import (
 	"github.com/d-ashesss/news-feed-bot/bot"
 	"github.com/d-ashesss/news-feed-bot/http"
+	"github.com/d-ashesss/news-feed-bot/pkg/alert"
+	"github.com/d-ashesss/news-feed-bot/pkg/autopost"
+	"github.com/d-ashesss/news-feed-bot/pkg/broadcast"
+	"github.com/d-ashesss/news-feed-bot/pkg/feed/coordinator"
+	"github.com/d-ashesss/news-feed-bot/pkg/feed/fetcher"
+	"github.com/d-ashesss/news-feed-bot/pkg/i18n"
 	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/d-ashesss/news-feed-bot/pkg/search"
+	"github.com/d-ashesss/news-feed-bot/scheduler"
 	"github.com/go-martini/martini"
+	"github.com/google/uuid"
 	"log"
 	"os"
 	"os/signal"
@@ -15,10 +24,24 @@ type App struct {
 	Config            Config
 	HttpServer        *http.Server
 	Bot               *bot.Bot
+	Scheduler         *scheduler.Scheduler
 	FeedModel         model.FeedModel
 	CategoryModel     model.CategoryModel
 	SubscriberModel   model.SubscriberModel
 	SubscriptionModel model.SubscriptionModel
+	LeaseModel        model.LeaseModel
+	AlertModel        model.AlertModel
+	BookmarkModel     model.BookmarkModel
+	HistoryModel      model.HistoryModel
+	SuggestionModel   model.SuggestionModel
+	BroadcastModel    model.BroadcastModel
+	Coordinator       *coordinator.Coordinator
+	Alerts            *alert.Dispatcher
+	Broadcasts        *broadcast.Dispatcher
+	AutoPosts         *autopost.Dispatcher
+	Search            *search.Index
+	InlineCache       *search.Cache
+	I18n              *i18n.Bundle
 }
 
 func (a *App) Run() {
@@ -46,8 +69,17 @@ func (a *App) Run() {
 		}()
 	}
 
+	if a.Scheduler != nil {
+		log.Printf("[app] Starting fetch scheduler every %s", a.Config.FetchInterval)
+		a.Scheduler.Start()
+	}
+
 	sig := <-signals
 	log.Printf("[app] Received signal %s", sig)
+	if a.Scheduler != nil {
+		log.Printf("[app] Stopping fetch scheduler")
+		a.Scheduler.Stop()
+	}
 	log.Printf("[app] Stopping HTTP server")
 	a.HttpServer.Shutdown()
 	log.Printf("[app] Gracefully exiting")
@@ -60,6 +92,12 @@ func NewApp(
 	categoryModel model.CategoryModel,
 	subscriberModel model.SubscriberModel,
 	subscriptionModel model.SubscriptionModel,
+	leaseModel model.LeaseModel,
+	alertModel model.AlertModel,
+	bookmarkModel model.BookmarkModel,
+	historyModel model.HistoryModel,
+	suggestionModel model.SuggestionModel,
+	broadcastModel model.BroadcastModel,
 ) *App {
 	app := &App{
 		Config:            config,
@@ -68,13 +106,61 @@ func NewApp(
 		CategoryModel:     categoryModel,
 		SubscriberModel:   subscriberModel,
 		SubscriptionModel: subscriptionModel,
+		LeaseModel:        leaseModel,
+		AlertModel:        alertModel,
+		BookmarkModel:     bookmarkModel,
+		HistoryModel:      historyModel,
+		SuggestionModel:   suggestionModel,
+		BroadcastModel:    broadcastModel,
+	}
+
+	holder := "app:" + uuid.NewString()
+	app.Coordinator = coordinator.New(feedModel, categoryModel, subscriptionModel, leaseModel, holder)
+	app.Coordinator.Bounds = config.FeedBounds
+	app.Coordinator.LeaseTTL = config.FetchLeaseTTL
+
+	var err error
+	if app.I18n, err = loadLocales(config.LocalesPath); err != nil {
+		log.Fatalf("[app] Failed to load message catalogs: %v", err)
+	}
+
+	app.Search = search.NewIndex()
+	if config.SearchMaxAge > 0 {
+		app.Search.MaxAge = config.SearchMaxAge
+	}
+	app.loadSearchIndex()
+	app.InlineCache = search.NewCache()
+	listeners := fetcher.Listeners{app.Search}
+	if alertModel != nil {
+		app.Alerts = alert.NewDispatcher(alertModel, app)
+		app.Alerts.RateCap = config.AlertRateCap
+		app.Alerts.RateWindow = config.AlertRateWindow
+		listeners = append(listeners, app.Alerts)
+	}
+	app.Coordinator.Listener = listeners
+	if broadcastModel != nil {
+		app.Broadcasts = broadcast.NewDispatcher(broadcastModel, subscriberModel, categoryModel, leaseModel, app, holder)
+		app.Broadcasts.Rate = config.BroadcastRate
+	}
+	app.AutoPosts = autopost.NewDispatcher(subscriberModel, subscriptionModel, leaseModel, app, holder)
+	app.AutoPosts.Limit = config.AutoPostLimit
+
+	if config.FetchInterval > 0 {
+		app.Scheduler = scheduler.New(config.FetchInterval, app.scheduledFetch)
 	}
 
 	app.HttpServer.Get("/", app.handleIndex)
 	app.HttpServer.Get("/_ah/warmup", app.handleWarmup)
 	app.HttpServer.Group("/cron", func(r martini.Router) {
 		r.Get("/fetch", app.handleCronFetch)
+		r.Get("/cleanup", app.handleCronCleanup)
+		r.Get("/broadcast", app.handleCronBroadcast)
 	}, app.authCron)
+	app.HttpServer.Group("/admin", func(r martini.Router) {
+		r.Get("/categories", app.handleAdminCategories)
+		r.Put("/categories/:id", app.handleAdminSetCategory)
+		r.Put("/categories/:id/translations/:lang", app.handleAdminSetCategoryTranslation)
+	}, app.authAdmin)
 
 	return app
 }
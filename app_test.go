@@ -43,7 +43,10 @@ func (t *AppTest) Request(method string, url string, body io.Reader, headers map
 }
 
 func NewAppTest() *AppTest {
-	config := Config{}
+	return NewAppTestWithConfig(Config{})
+}
+
+func NewAppTestWithConfig(config Config) *AppTest {
 	handler := martini.Classic()
 	testServer := httptest.NewUnstartedServer(handler)
 	buffer := bytes.NewBufferString("")
@@ -56,6 +59,6 @@ func NewAppTest() *AppTest {
 		httpServer:     httpServer,
 		logger:         logger,
 		logBuffer:      buffer,
-		app:            NewApp(config, httpServer, nil, nil, nil),
+		app:            NewApp(config, httpServer, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil),
 	}
 }
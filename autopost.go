@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/autopost"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"gopkg.in/tucnak/telebot.v2"
+	"log"
+)
+
+// PostUpdate posts the update to the channel formatted with the template of the channel parsed by the caller.
+//
+//	The default format is used if there is no template, it fails to render or Telegram rejects its markup.
+func (a *App) PostUpdate(ctx context.Context, s *model.Subscriber, up model.Update, t *autopost.Template) error {
+	if a.Bot == nil {
+		return errors.New("bot is not set up")
+	}
+	to, err := botRecipient(s)
+	if err != nil {
+		return err
+	}
+	text := formatStoryHTML(up)
+	if t != nil {
+		if text, err = t.Render(up, s.GetLocale()); err != nil {
+			log.Printf("[bot] PostUpdate(): render template of %q: %v", s.UserID, err)
+			text = formatStoryHTML(up)
+		}
+	}
+	options := &telebot.SendOptions{ParseMode: telebot.ModeHTML}
+	_, err = a.Bot.Send(to, text, options)
+	if err != nil && t != nil && classifyBotSendError(err) == BotSendErrorOther {
+		log.Printf("[bot] PostUpdate(): post with template to %q: %v", s.UserID, err)
+		_, err = a.Bot.Send(to, formatStoryHTML(up), options)
+	}
+	if err == nil {
+		return nil
+	}
+	a.helperHandleSendError(ctx, s, err)
+	if kind := classifyBotSendError(err); kind.unreachable() || kind == BotSendErrorRateLimited {
+		return fmt.Errorf("%w: %v", autopost.ErrPostponed, err)
+	}
+	return err
+}
@@ -6,17 +6,28 @@ import (
 	"errors"
 	"fmt"
 	"github.com/d-ashesss/news-feed-bot/bot"
+	"github.com/d-ashesss/news-feed-bot/pkg/i18n"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
 	"github.com/google/uuid"
 	"gopkg.in/tucnak/telebot.v2"
 	"log"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 const (
 	BotCtxUser = "user"
 )
 
+// Kinds of text input the user can be asked for, stored in model.Subscriber Input as "<kind>:<data>".
+const (
+	BotInputFilterInclude = "filterInclude"
+	BotInputFilterExclude = "filterExclude"
+	BotInputAlert         = "alert"
+	BotInputCustomFeed    = "customFeed"
+)
+
 func (a *App) SetBot(bot *bot.Bot) error {
 	if bot == nil {
 		return errors.New("invalid bot instance")
@@ -45,23 +56,115 @@ func (a *App) SetBot(bot *bot.Bot) error {
 	a.Bot.Handle(telebot.OnText, a.botHandleMessage(botCtx, a.botHandleTextMessage))
 	a.Bot.Handle("/start", a.botHandleMessage(botCtx, a.botHandleStartCmd))
 	a.Bot.Handle("/menu", a.botHandleMessage(botCtx, a.botHandleMenuCmd))
-	a.Bot.Handle("/delete", a.botHandleMessage(botCtx, a.botHandleDeleteCmd))
+	a.Bot.Handle("/delete", a.botHandleChatAdminMessage(botCtx, a.botHandleDeleteCmd))
+	a.Bot.Handle("/export", a.botHandleChatAdminMessage(botCtx, a.botHandleExportCmd))
+	a.Bot.Handle("/alert", a.botHandleChatAdminMessage(botCtx, a.botHandleAlertCmd))
+	a.Bot.Handle("/feeds", a.botHandleChatAdminMessage(botCtx, a.botHandleCustomFeedsCmd))
+	a.Bot.Handle("/suggest", a.botHandleMessage(botCtx, a.botHandleSuggestCmd))
+	a.Bot.Handle("/admin", a.botHandleAdminMessage(botCtx, a.botHandleAdminCmd))
+	a.Bot.Handle("/search", a.botHandleMessage(botCtx, a.botHandleSearchCmd))
+	a.Bot.Handle("/saved", a.botHandleMessage(botCtx, a.botHandleSavedCmd))
+	a.Bot.Handle("/history", a.botHandleMessage(botCtx, a.botHandleHistoryCmd))
+	a.Bot.Handle("/language", a.botHandleChatAdminMessage(botCtx, a.botHandleLanguageCmd))
+	a.Bot.Handle(telebot.OnQuery, a.botHandleQuery(botCtx, a.botHandleInlineQuery))
+	a.Bot.Handle(telebot.OnChannelPost, a.botHandleChannelPost(botCtx, a.botHandleChannelCmd))
+	a.Bot.Handle(telebot.OnMigration, func(from, to int64) {
+		a.botHandleMigration(botCtx, from, to)
+	})
 
 	a.Bot.Handle(&telebot.Btn{Unique: BotBtnBackToMainMenuID}, a.botHandleCallback(botCtx, a.botHandleBackToMainMenuCallback))
 
-	menuMain := NewBotMenuMain()
-	a.Bot.Handle(&menuMain.BtnCheckUpdates, a.botHandleCallback(botCtx, a.botHandleCheckUpdatesCallback))
-	a.Bot.Handle(&menuMain.BtnSelectCategories, a.botHandleCallback(botCtx, a.botHandleSelectCategoriesCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuMainBtnCheckUpdatesID}, a.botHandleCallback(botCtx, a.botHandleCheckUpdatesCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuMainBtnSelectCategoriesID}, a.botHandleCallback(botCtx, a.botHandleSelectCategoriesCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuMainBtnFiltersID}, a.botHandleCallback(botCtx, a.botHandleFiltersCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuMainBtnAlertsID}, a.botHandleCallback(botCtx, a.botHandleAlertsCallback))
 
-	a.Bot.Handle(&telebot.Btn{Unique: BotMenuSelectCategoriesBtnToggleCategoryID}, a.botHandleCallback(botCtx, a.botHandleToggleCategoryCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuSelectCategoriesBtnToggleCategoryID}, a.botHandleChatAdminCallback(botCtx, a.botHandleToggleCategoryCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuSelectCategoriesBtnFeedsID}, a.botHandleCallback(botCtx, a.botHandleCategoryFeedsCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuCategoryFeedsBtnToggleFeedID}, a.botHandleChatAdminCallback(botCtx, a.botHandleToggleFeedCallback))
 	a.Bot.Handle(&telebot.Btn{Unique: BotMenuCategoryUpdatesBtnCategoryUpdatesID}, a.botHandleCallback(botCtx, a.botHandleCategoryUpdatesCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuCategoryUpdatesBtnListID}, a.botHandleCallback(botCtx, a.botHandleCategoryListCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuUpdatesPageBtnOpenID}, a.botHandleCallback(botCtx, a.botHandleUpdatesPageOpenCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuUpdatesPageBtnMarkReadID}, a.botHandleCallback(botCtx, a.botHandleUpdatesPageMarkReadCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuUpdatesPageBtnSizeID}, a.botHandleCallback(botCtx, a.botHandleUpdatesPageSizeCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuCategoryUpdatesBtnMarkReadID}, a.botHandleCallback(botCtx, a.botHandleMarkCategoryReadCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuCategoryUpdatesBtnMarkAllReadID}, a.botHandleCallback(botCtx, a.botHandleMarkAllReadCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuMarkAllReadBtnConfirmID}, a.botHandleCallback(botCtx, a.botHandleMarkAllReadConfirmCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuCategoryNextUpdateBtnPreviousID}, a.botHandleCallback(botCtx, a.botHandleCategoryPreviousUpdateCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuHistoryBtnPageID}, a.botHandleCallback(botCtx, a.botHandleHistoryPageCallback))
+
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuFilterCategoriesBtnCategoryID}, a.botHandleCallback(botCtx, a.botHandleFilterCategoryCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuCategoryFilterBtnIncludeID}, a.botHandleChatAdminCallback(botCtx, a.botHandleFilterIncludeCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuCategoryFilterBtnExcludeID}, a.botHandleChatAdminCallback(botCtx, a.botHandleFilterExcludeCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuCategoryFilterBtnClearID}, a.botHandleChatAdminCallback(botCtx, a.botHandleFilterClearCallback))
+
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuAlertsBtnRemoveID}, a.botHandleChatAdminCallback(botCtx, a.botHandleAlertRemoveCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuAlertsBtnAddID}, a.botHandleChatAdminCallback(botCtx, a.botHandleAlertAddCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuMainBtnCustomFeedsID}, a.botHandleCallback(botCtx, a.botHandleCustomFeedsCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuCustomFeedsBtnRemoveID}, a.botHandleChatAdminCallback(botCtx, a.botHandleCustomFeedRemoveCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuCustomFeedsBtnAddID}, a.botHandleChatAdminCallback(botCtx, a.botHandleCustomFeedAddCallback))
+
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuSuggestionBtnApproveID}, a.botHandleAdminCallback(botCtx, a.botHandleSuggestionApproveCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuSuggestionBtnRejectID}, a.botHandleAdminCallback(botCtx, a.botHandleSuggestionRejectCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuSuggestionBtnCategoryID}, a.botHandleAdminCallback(botCtx, a.botHandleSuggestionCategoryCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuSuggestionBtnBackID}, a.botHandleAdminCallback(botCtx, a.botHandleSuggestionBackCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuAdminDeleteFeedBtnConfirmID}, a.botHandleAdminCallback(botCtx, a.botHandleAdminDeleteFeedConfirmCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuAdminDeleteFeedBtnCancelID}, a.botHandleAdminCallback(botCtx, a.botHandleAdminDeleteFeedCancelCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuAdminBroadcastBtnConfirmID}, a.botHandleAdminCallback(botCtx, a.botHandleAdminBroadcastConfirmCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuAdminBroadcastBtnCancelID}, a.botHandleAdminCallback(botCtx, a.botHandleAdminBroadcastCancelCallback))
 
-	menuDelete := NewBotMenuDelete()
-	a.Bot.Handle(&menuDelete.BtnConfirm, a.botHandleCallback(botCtx, a.botHandleDeleteConfirmCallback))
-	a.Bot.Handle(&menuDelete.BtnCancel, a.botHandleCallback(botCtx, a.botHandleDeleteCancelCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuSearchBtnPageID}, a.botHandleCallback(botCtx, a.botHandleSearchPageCallback))
+
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuUpdateBtnSaveID}, a.botHandleCallback(botCtx, a.botHandleSaveUpdateCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuSavedBtnPageID}, a.botHandleCallback(botCtx, a.botHandleSavedPageCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuSavedBtnRemoveID}, a.botHandleCallback(botCtx, a.botHandleSavedRemoveCallback))
+
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuLanguageBtnSelectID}, a.botHandleChatAdminCallback(botCtx, a.botHandleLanguageSelectCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuLanguageBtnPageID}, a.botHandleCallback(botCtx, a.botHandleLanguagePageCallback))
+
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuChannelBtnAutoPostID}, a.botHandleChatAdminCallback(botCtx, a.botHandleChannelAutoPostCallback))
+
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuDeleteBtnConfirmID}, a.botHandleChatAdminCallback(botCtx, a.botHandleDeleteConfirmCallback))
+	a.Bot.Handle(&telebot.Btn{Unique: BotMenuDeleteBtnCancelID}, a.botHandleCallback(botCtx, a.botHandleDeleteCancelCallback))
 	return nil
 }
 
+// botLocalizer returns the Localizer of the language the bot speaks to the user.
+func (a *App) botLocalizer(user *model.Subscriber) *i18n.Localizer {
+	return a.I18n.Localizer(user.GetLocale())
+}
+
+// botIsAdmin checks if the user is one of the configured bot admins, the groups and the channels never are.
+func (a *App) botIsAdmin(user *model.Subscriber) bool {
+	if user == nil || user.Chat != model.ChatPrivate || !strings.HasPrefix(user.UserID, "telegram:") {
+		return false
+	}
+	id := strings.TrimPrefix(user.UserID, "telegram:")
+	for _, admin := range a.Config.BotAdmins {
+		if admin == id {
+			return true
+		}
+	}
+	return false
+}
+
+// botIsChatAdmin checks if the sender administers the group or the channel the user is,
+// everyone is the admin of their private chat.
+func (a *App) botIsChatAdmin(user *model.Subscriber, chat *telebot.Chat, sender *telebot.User) bool {
+	if user.Chat == model.ChatPrivate {
+		return true
+	}
+	if sender == nil {
+		return false
+	}
+	member, err := a.Bot.ChatMemberOf(chat, sender)
+	if err != nil {
+		log.Printf("[bot] botIsChatAdmin(): get member %d of chat %d: %v", sender.ID, chat.ID, err)
+		return false
+	}
+	return member.Role == telebot.Creator || member.Role == telebot.Administrator
+}
+
 func getBotWebhookPath(bot *bot.Bot) (string, error) {
 	u, err := bot.WebhookURL()
 	if err != nil {
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"gopkg.in/tucnak/telebot.v2"
+	"log"
+	"strings"
+)
+
+// botSendError is a class of errors returned by Telegram when sending a message to a user.
+type botSendError string
+
+// Classes of errors sending a message.
+const (
+	BotSendErrorOther        botSendError = ""
+	BotSendErrorBlocked      botSendError = "blocked"
+	BotSendErrorChatNotFound botSendError = "chat not found"
+	BotSendErrorDeactivated  botSendError = "deactivated"
+	BotSendErrorNoRights     botSendError = "no rights"
+	BotSendErrorRateLimited  botSendError = "rate limited"
+)
+
+// classifyBotSendError tells what class an error returned by telebot.Bot Send belongs to.
+func classifyBotSendError(err error) botSendError {
+	switch err {
+	case nil:
+		return BotSendErrorOther
+	case telebot.ErrBlockedByUser, telebot.ErrNotStartedByUser, telebot.ErrBotKickedFromGroup, telebot.ErrBotKickedFromSuperGroup:
+		return BotSendErrorBlocked
+	case telebot.ErrChatNotFound:
+		return BotSendErrorChatNotFound
+	case telebot.ErrUserIsDeactivated:
+		return BotSendErrorDeactivated
+	case telebot.ErrNoRightsToSend:
+		return BotSendErrorNoRights
+	}
+	if _, ok := err.(telebot.FloodError); ok {
+		return BotSendErrorRateLimited
+	}
+	// Telegram varies the wording of some errors, so the unknown ones are matched by the description as well.
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "bot was blocked"), strings.Contains(msg, "bot was kicked"), strings.Contains(msg, "bot is not a member"):
+		return BotSendErrorBlocked
+	case strings.Contains(msg, "chat not found"):
+		return BotSendErrorChatNotFound
+	case strings.Contains(msg, "user is deactivated"):
+		return BotSendErrorDeactivated
+	case strings.Contains(msg, "rights to send"), strings.Contains(msg, "need administrator rights"):
+		return BotSendErrorNoRights
+	case strings.Contains(msg, "too many requests"):
+		return BotSendErrorRateLimited
+	}
+	return BotSendErrorOther
+}
+
+// unreachable tells whether the user, the group or the channel can not receive messages anymore.
+func (e botSendError) unreachable() bool {
+	return e == BotSendErrorBlocked || e == BotSendErrorChatNotFound || e == BotSendErrorDeactivated || e == BotSendErrorNoRights
+}
+
+// helperHandleSendError marks the subscriber inactive if the error tells the user can not be reached anymore.
+func (a *App) helperHandleSendError(ctx context.Context, s *model.Subscriber, err error) {
+	kind := classifyBotSendError(err)
+	if kind == BotSendErrorRateLimited {
+		log.Printf("[bot] Rate limited sending to %q: %v", s.UserID, err)
+		return
+	}
+	if !kind.unreachable() || s.Inactive {
+		return
+	}
+	if err := a.SubscriberModel.Deactivate(ctx, s, string(kind)); err != nil {
+		log.Printf("[bot] helperHandleSendError(): deactivate user: %v", err)
+		return
+	}
+	log.Printf("[bot] Deactivated user %q: %s", s.ID, kind)
+}
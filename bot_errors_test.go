@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"gopkg.in/tucnak/telebot.v2"
+	"testing"
+)
+
+func TestClassifyBotSendError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want botSendError
+	}{
+		{name: "nil", err: nil, want: BotSendErrorOther},
+		{name: "blocked", err: telebot.ErrBlockedByUser, want: BotSendErrorBlocked},
+		{name: "kicked", err: telebot.ErrBotKickedFromGroup, want: BotSendErrorBlocked},
+		{name: "chat not found", err: telebot.ErrChatNotFound, want: BotSendErrorChatNotFound},
+		{name: "deactivated", err: telebot.ErrUserIsDeactivated, want: BotSendErrorDeactivated},
+		{name: "flood", err: telebot.FloodError{APIError: telebot.NewAPIError(429, "Too Many Requests: retry after 5"), RetryAfter: 5}, want: BotSendErrorRateLimited},
+		{name: "no rights", err: telebot.ErrNoRightsToSend, want: BotSendErrorNoRights},
+		{name: "not a channel admin", err: errors.New("telegram unknown: Bad Request: need administrator rights in the channel chat (400)"), want: BotSendErrorNoRights},
+		{name: "not a member", err: errors.New("telegram unknown: Forbidden: bot is not a member of the channel chat (403)"), want: BotSendErrorBlocked},
+		{name: "unknown blocked", err: errors.New("telegram unknown: Forbidden: bot was blocked by the user (403)"), want: BotSendErrorBlocked},
+		{name: "other", err: telebot.ErrMessageTooLong, want: BotSendErrorOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyBotSendError(tt.err); got != tt.want {
+				t.Errorf("classifyBotSendError(%v): got %q; want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApp_helperHandleSendError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantReason string
+	}{
+		{name: "blocked", err: telebot.ErrBlockedByUser, wantReason: string(BotSendErrorBlocked)},
+		{name: "rate limited", err: telebot.FloodError{APIError: telebot.NewAPIError(429, "Too Many Requests: retry after 5"), RetryAfter: 5}},
+		{name: "other", err: telebot.ErrMessageTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscribers := &testSubscriberModel{}
+			a := &App{SubscriberModel: subscribers}
+			s := &model.Subscriber{ID: "s1"}
+			a.helperHandleSendError(context.Background(), s, tt.err)
+			if got := subscribers.deactivated[s.ID]; got != tt.wantReason {
+				t.Errorf("helperHandleSendError(%v): got deactivated for %q; want %q", tt.err, got, tt.wantReason)
+			}
+		})
+	}
+}
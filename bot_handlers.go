@@ -1,23 +1,34 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/autopost"
+	"github.com/d-ashesss/news-feed-bot/pkg/feed/fetcher"
+	"github.com/d-ashesss/news-feed-bot/pkg/i18n"
 	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/d-ashesss/news-feed-bot/pkg/search"
 	"gopkg.in/tucnak/telebot.v2"
+	"html"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
 // botHandleStartCmd handles /start command.
 //
 //	Shows welcome message.
 func (a *App) botHandleStartCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
 	if _, err := a.Bot.Send(
-		m.Sender,
-		fmt.Sprintf("Welcome to this humble news bot!\n"+
-			"Here you can receive news updates from the most famous world news agencies in the categories that you choose for yourself!\n"+
-			"Please check out the menu to select the categories and start receiving the updates."),
+		m.Chat,
+		l.T("msg.welcome"),
 		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
 	); err != nil {
 		log.Printf("[bot] botHandleStartCmd() Failed to reply: %v", err)
@@ -28,24 +39,35 @@ func (a *App) botHandleStartCmd(ctx context.Context, m *telebot.Message) {
 // botHandleMenuCmd handles /menu command.
 //
 //	Shows main menu.
-func (a *App) botHandleMenuCmd(_ context.Context, m *telebot.Message) {
+func (a *App) botHandleMenuCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
 	if _, err := a.Bot.Send(
-		m.Sender,
-		fmt.Sprintf("Please select the desired action:"),
+		m.Chat,
+		l.T("msg.select_action"),
 		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-		NewBotMenuMain().Menu,
+		NewBotMenuMain(l).Menu,
 	); err != nil {
 		log.Printf("[bot] botHandleMenuCmd() Failed to reply: %v", err)
 	}
 }
 
 // botHandleBackToMainMenuCallback returns user to main menu.
-func (a *App) botHandleBackToMainMenuCallback(_ context.Context, cb *telebot.Callback) {
+func (a *App) botHandleBackToMainMenuCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	if user.IsChannel() {
+		a.helperEditChannelMenu(cb, l, user, "")
+		_ = a.Bot.Respond(cb)
+		return
+	}
 	if _, err := a.Bot.Edit(
 		cb.Message,
-		fmt.Sprintf("Please select the desired action:"),
+		l.T("msg.select_action"),
 		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-		NewBotMenuMain().Menu,
+		NewBotMenuMain(l).Menu,
 	); err != nil {
 		log.Printf("[bot] botHandleBackToMainMenuCallback() Failed to reply: %v", err)
 	}
@@ -53,81 +75,436 @@ func (a *App) botHandleBackToMainMenuCallback(_ context.Context, cb *telebot.Cal
 }
 
 // botHandleCheckUpdatesCallback handles request to show unread updates.
+//
+//	Callback data is the ID of the category whose subcategories to show followed by the page number.
 func (a *App) botHandleCheckUpdatesCallback(ctx context.Context, cb *telebot.Callback) {
 	user := ctx.Value(BotCtxUser).(*model.Subscriber)
 
-	subs, err := a.SubscriptionModel.GetSubscriptionStatus(ctx, user)
+	catID, page := parseBotPageData(cb.Data)
+	a.helperShowCategoryUpdates(ctx, cb, user, catID, page)
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleMarkCategoryReadCallback marks all updates in selected category as read.
+func (a *App) botHandleMarkCategoryReadCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	cat, err := a.CategoryModel.Get(ctx, cb.Data)
 	if err != nil {
-		log.Printf("[bot] botHandleCheckUpdatesCallback(): subscription status: %v", err)
+		log.Printf("[bot] botHandleMarkCategoryReadCallback(): get category: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
 		return
 	}
-	unread := 0
-	selectedSubs := make([]model.Subscription, 0, len(subs))
-	for _, sub := range subs {
-		if sub.Subscribed {
-			unread += sub.Unread
-			selectedSubs = append(selectedSubs, sub)
+	n, err := a.SubscriptionModel.MarkRead(ctx, user, cat)
+	if err != nil {
+		log.Printf("[bot] botHandleMarkCategoryReadCallback(): mark read: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	a.helperShowCategoryUpdates(ctx, cb, user, cat.ID, 0)
+	_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.N("msg.updates.category_marked_read", n, n, cat.LocalName(l.Lang))})
+}
+
+// botHandleCategoryListCallback shows a page of unread updates in selected category.
+func (a *App) botHandleCategoryListCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+
+	catID, offset := parseBotPageData(cb.Data)
+	a.helperEditUpdatesPage(ctx, cb, user, catID, offset)
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleUpdatesPageOpenCallback shows selected update from the page marking it as read.
+func (a *App) botHandleUpdatesPageOpenCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	upID, offset := parseBotPageData(cb.Data)
+	up, err := a.SubscriptionModel.TakeUpdate(ctx, user, upID)
+	if err == model.ErrNotFound {
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.updates.already_read")})
+		return
+	}
+	if err != nil {
+		log.Printf("[bot] botHandleUpdatesPageOpenCallback(): take update: %v", err)
+		return
+	}
+	h := a.helperAddToHistory(ctx, user, up)
+	if _, err := a.Bot.Send(
+		cb.Message.Chat,
+		up.FormatMessage(),
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuUpdate(l, h.ID, false).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleUpdatesPageOpenCallback(): Failed to show update: %v", err)
+		a.helperHandleSendError(ctx, user, err)
+	}
+	if err := a.Bot.Delete(cb.Message); err != nil {
+		log.Printf("[bot] botHandleUpdatesPageOpenCallback(): Failed to delete prev message: %v", err)
+	}
+	if up.Category == nil {
+		_ = a.Bot.Respond(cb)
+		return
+	}
+	text, menu, err := a.helperUpdatesPage(ctx, user, up.Category, offset)
+	if err != nil {
+		log.Printf("[bot] botHandleUpdatesPageOpenCallback(): %v", err)
+	} else if _, err := a.Bot.Send(cb.Message.Chat, text, menu); err != nil {
+		log.Printf("[bot] botHandleUpdatesPageOpenCallback(): Failed to show page: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleUpdatesPageMarkReadCallback marks all updates on the page as read.
+func (a *App) botHandleUpdatesPageMarkReadCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	catID, offset, from, to, ok := parseBotPageRangeData(cb.Data)
+	if !ok {
+		// nothing is marked read without the dates of the shown page, it is shown again instead
+		a.helperEditUpdatesPage(ctx, cb, user, catID, offset)
+		_ = a.Bot.Respond(cb)
+		return
+	}
+	cat, err := a.CategoryModel.Get(ctx, catID)
+	if err != nil {
+		log.Printf("[bot] botHandleUpdatesPageMarkReadCallback(): get category: %v", err)
+		return
+	}
+	page, err := a.SubscriptionModel.GetUnreadRange(ctx, user, *cat, from, to)
+	if err != nil {
+		log.Printf("[bot] botHandleUpdatesPageMarkReadCallback(): get unread: %v", err)
+		return
+	}
+	if err := a.SubscriptionModel.MarkUpdatesRead(ctx, user, page); err != nil {
+		log.Printf("[bot] botHandleUpdatesPageMarkReadCallback(): mark read: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	a.helperEditUpdatesPage(ctx, cb, user, catID, offset)
+	_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.N("msg.updates.marked_read", len(page))})
+}
+
+// botHandleUpdatesPageSizeCallback switches user to the next page size of the list view.
+func (a *App) botHandleUpdatesPageSizeCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	catID, offset := parseBotPageData(cb.Data)
+	user.PageSize = user.NextPageSize()
+	if err := a.SubscriberModel.Save(ctx, user); err != nil {
+		log.Printf("[bot] botHandleUpdatesPageSizeCallback(): save user: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	a.helperEditUpdatesPage(ctx, cb, user, catID, offset)
+	_ = a.Bot.Respond(cb)
+}
+
+// helperEditUpdatesPage replaces the message with a page of unread updates in the category.
+func (a *App) helperEditUpdatesPage(ctx context.Context, cb *telebot.Callback, user *model.Subscriber, catID string, offset int) {
+	cat, err := a.CategoryModel.Get(ctx, catID)
+	if err != nil {
+		log.Printf("[bot] helperEditUpdatesPage(): get category: %v", err)
+		return
+	}
+	text, menu, err := a.helperUpdatesPage(ctx, user, cat, offset)
+	if err != nil {
+		log.Printf("[bot] helperEditUpdatesPage(): %v", err)
+		return
+	}
+	if _, err := a.Bot.Edit(cb.Message, text, menu); err != nil && !strings.Contains(err.Error(), "new message content and reply markup are exactly the same") {
+		log.Printf("[bot] helperEditUpdatesPage(): Failed to edit message: %v", err)
+	}
+}
+
+// helperUpdatesPage builds the message with a page of unread updates in the category.
+//
+//	The offset is moved back to the last page if the updates it pointed to were read meanwhile.
+func (a *App) helperUpdatesPage(ctx context.Context, user *model.Subscriber, cat *model.Category, offset int) (string, *telebot.ReplyMarkup, error) {
+	size := user.GetPageSize()
+	if offset < 0 {
+		offset = 0
+	}
+	page, total, err := a.SubscriptionModel.GetUnreadPage(ctx, user, *cat, offset, size)
+	if err != nil {
+		return "", nil, fmt.Errorf("get unread: %v", err)
+	}
+	if last := lastPageOffset(total, size); offset > last {
+		offset = last
+		if page, total, err = a.SubscriptionModel.GetUnreadPage(ctx, user, *cat, offset, size); err != nil {
+			return "", nil, fmt.Errorf("get unread: %v", err)
 		}
 	}
-	if len(selectedSubs) == 0 {
+	l := a.botLocalizer(user)
+	return formatBotUpdatesPageMessage(l, cat, page, offset, total), NewBotMenuUpdatesPage(l, cat, page, offset, total, size).Menu, nil
+}
+
+// lastPageOffset returns the offset of the last page of total items.
+func lastPageOffset(total, size int) int {
+	if total <= 0 {
+		return 0
+	}
+	return (total - 1) / size * size
+}
+
+// parseBotPageData splits callback data of the form "<id>|<offset>" or "<id>|<page>".
+func parseBotPageData(data string) (string, int) {
+	parts := strings.SplitN(data, "|", 2)
+	if len(parts) != 2 {
+		return parts[0], 0
+	}
+	offset, _ := strconv.Atoi(parts[1])
+	return parts[0], offset
+}
+
+// parseBotPageRangeData parses the callback data of a page made of the category ID, the offset of the page
+// and the dates of its first and last updates, see formatBotPageDate.
+func parseBotPageRangeData(data string) (string, int, time.Time, time.Time, bool) {
+	parts := strings.Split(data, "|")
+	if len(parts) != 4 {
+		catID, offset := parseBotPageData(data)
+		return catID, offset, time.Time{}, time.Time{}, false
+	}
+	offset, _ := strconv.Atoi(parts[1])
+	from, err := parseBotPageDate(parts[2])
+	if err != nil {
+		return parts[0], offset, time.Time{}, time.Time{}, false
+	}
+	to, err := parseBotPageDate(parts[3])
+	if err != nil {
+		return parts[0], offset, time.Time{}, time.Time{}, false
+	}
+	return parts[0], offset, from, to, true
+}
+
+// formatBotPageDate encodes the date of an update in the callback data,
+// the microseconds the DB keeps the dates with are written in base 36 to fit the size of the data.
+func formatBotPageDate(date time.Time) string {
+	return strconv.FormatInt(date.UnixMicro(), 36)
+}
+
+// parseBotPageDate decodes the date written by formatBotPageDate.
+func parseBotPageDate(s string) (time.Time, error) {
+	us, err := strconv.ParseInt(s, 36, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMicro(us).UTC(), nil
+}
+
+// formatBotUpdatesPageMessage describes a page of unread updates in the category.
+func formatBotUpdatesPageMessage(l *i18n.Localizer, cat *model.Category, ups []model.Update, offset, total int) string {
+	if len(ups) == 0 {
+		return l.T("msg.page.empty", cat.LocalName(l.Lang))
+	}
+	var b strings.Builder
+	b.WriteString(l.T("msg.page.header", cat.LocalName(l.Lang), offset+1, offset+len(ups), total) + "\n")
+	for i, up := range ups {
+		b.WriteString(fmt.Sprintf("\n%d. %s\n%s", offset+i+1, up.Title, up.Date.Format("02 Jan 15:04")))
+	}
+	return b.String()
+}
+
+// botHandleMarkAllReadCallback asks user to confirm marking updates in all categories as read.
+func (a *App) botHandleMarkAllReadCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		l.T("msg.updates.mark_all_read"),
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuMarkAllRead(l).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleMarkAllReadCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleMarkAllReadConfirmCallback marks updates in all categories as read.
+func (a *App) botHandleMarkAllReadConfirmCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	n, err := a.SubscriptionModel.MarkRead(ctx, user, nil)
+	if err != nil {
+		log.Printf("[bot] botHandleMarkAllReadConfirmCallback(): mark read: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	a.helperShowCategoryUpdates(ctx, cb, user, "", 0)
+	_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.N("msg.updates.marked_read", n)})
+}
+
+// helperShowCategoryUpdates shows the number of unread updates in each of the selected categories.
+//
+//	catID selects the level of nested categories to show: the one of the category if it has received
+//	subcategories or the one it is listed in otherwise, the top level if empty. page is the page of the level to show.
+func (a *App) helperShowCategoryUpdates(ctx context.Context, cb *telebot.Callback, user *model.Subscriber, catID string, page int) {
+	l := a.botLocalizer(user)
+	subs, err := a.SubscriptionModel.GetSubscriptionStatus(ctx, user)
+	if err != nil {
+		log.Printf("[bot] helperShowCategoryUpdates(): subscription status: %v", err)
+		return
+	}
+	parent := findBotUpdatesLevel(subs, catID)
+	if parent == nil && len(model.ReceivedSubscriptions(subs, "")) == 0 {
 		if _, err := a.Bot.Edit(
 			cb.Message,
-			fmt.Sprintf("You don't have any categories selected"),
+			l.T("msg.updates.no_categories_selected"),
 			&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-			NewBotMenuNoCategoriesSelected().Menu,
+			NewBotMenuNoCategoriesSelected(l).Menu,
 		); err != nil {
-			log.Printf("[bot] botHandleCheckUpdatesCallback(): Failed to edit message: %v", err)
+			log.Printf("[bot] helperShowCategoryUpdates(): Failed to edit message: %v", err)
 		}
+		return
+	}
+	var text string
+	if parent != nil {
+		text = l.N("msg.updates.category_total", parent.Unread, parent.Unread, parent.Category.LocalName(l.Lang))
 	} else {
-		if _, err := a.Bot.Edit(
-			cb.Message,
-			fmt.Sprintf("You have in total %d unread update(s) in categories you've selected:", unread),
-			&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-			NewBotMenuCategoryUpdates(selectedSubs).Menu,
-		); err != nil && !strings.Contains(err.Error(), "new message content and reply markup are exactly the same") {
-			log.Printf("[bot] botHandleCheckUpdatesCallback(): Failed to edit message: %v", err)
+		unread := 0
+		for _, sub := range model.ReceivedSubscriptions(subs, "") {
+			unread += sub.Unread
 		}
+		text = l.N("msg.updates.total", unread)
 	}
-	_ = a.Bot.Respond(cb)
+	if user.Pruned > 0 {
+		text = l.N("msg.updates.pruned", user.Pruned) + "\n" + text
+		if err := a.SubscriberModel.ClearPruned(ctx, user, user.Pruned); err != nil {
+			log.Printf("[bot] helperShowCategoryUpdates(): clear pruned: %v", err)
+		}
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		text,
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuCategoryUpdates(l, subs, parent, page).Menu,
+	); err != nil && !strings.Contains(err.Error(), "new message content and reply markup are exactly the same") {
+		log.Printf("[bot] helperShowCategoryUpdates(): Failed to edit message: %v", err)
+	}
+}
+
+// findBotUpdatesLevel returns the category whose received subcategories to list in the updates menu,
+// the closest to selected one with such subcategories. Returns nil for the top level.
+func findBotUpdatesLevel(subs []model.Subscription, catID string) *model.Subscription {
+	for i := 0; i < model.MaxCategoryDepth && len(catID) > 0; i++ {
+		sub, ok := model.FindSubscription(subs, catID)
+		if !ok {
+			return nil
+		}
+		if len(model.ReceivedSubscriptions(subs, catID)) > 0 {
+			return &sub
+		}
+		catID = sub.Category.ParentID
+	}
+	return nil
 }
 
 // botHandleSelectCategoriesCallback handles request to show the list of categories available for subscription.
+//
+//	Callback data is the ID of the category whose subcategories to show, the top level if empty,
+//	followed by the page number.
 func (a *App) botHandleSelectCategoriesCallback(ctx context.Context, cb *telebot.Callback) {
 	user := ctx.Value(BotCtxUser).(*model.Subscriber)
 
+	catID, page := parseBotPageData(cb.Data)
+	a.helperShowSelectCategories(ctx, cb, user, catID, page)
+	_ = a.Bot.Respond(cb)
+}
+
+// helperShowSelectCategories shows a page of the categories along with their subscription status.
+//
+//	catID selects the level of nested categories to show: the one of the category if it has subcategories
+//	or the one it is listed in otherwise, the top level if empty.
+func (a *App) helperShowSelectCategories(ctx context.Context, cb *telebot.Callback, user *model.Subscriber, catID string, page int) {
+	l := a.botLocalizer(user)
 	subs, err := a.SubscriptionModel.GetSubscriptionStatus(ctx, user)
 	if err != nil {
-		log.Printf("[bot] botHandleSelectCategoriesCallback(): subscription status: %v", err)
+		log.Printf("[bot] helperShowSelectCategories(): subscription status: %v", err)
 		return
 	}
+	subs = publicSubscriptions(subs)
 	if len(subs) == 0 {
 		if _, err := a.Bot.Edit(
 			cb.Message,
-			fmt.Sprintf("Unfortunately I do not have any categories available at the moment, please come back later."),
+			l.T("msg.categories.none"),
 			&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-			NewBotMenuSelectCategories(subs).Menu,
+			NewBotMenuSelectCategories(l, subs, nil, 0).Menu,
 		); err != nil {
-			log.Printf("[bot] botHandleSelectCategoriesCallback(): Failed to edit message: %v", err)
+			log.Printf("[bot] helperShowSelectCategories(): Failed to edit message: %v", err)
 		}
-		_ = a.Bot.Respond(cb)
 		return
 	}
+	var parent *model.Subscription
+	if sub, ok := model.FindSubscription(subs, catID); ok && sub.Subcategories == 0 {
+		catID = sub.Category.ParentID
+	}
+	if sub, ok := model.FindSubscription(subs, catID); ok && sub.Subcategories > 0 {
+		parent = &sub
+	}
 	if _, err := a.Bot.Edit(
 		cb.Message,
-		fmt.Sprintf("Select categories for which you would like to recieve updates:"),
+		formatBotSelectCategoriesMessage(l, subs, parent),
 		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-		NewBotMenuSelectCategories(subs).Menu,
-	); err != nil {
-		log.Printf("[bot] botHandleSelectCategoriesCallback(): Failed to edit message: %v", err)
+		NewBotMenuSelectCategories(l, subs, parent, page).Menu,
+	); err != nil && !strings.Contains(err.Error(), "new message content and reply markup are exactly the same") {
+		log.Printf("[bot] helperShowSelectCategories(): Failed to edit message: %v", err)
 	}
-	_ = a.Bot.Respond(cb)
+}
+
+// publicSubscriptions drops the personal category, it can not be subscribed to and its feeds are private.
+func publicSubscriptions(subs []model.Subscription) []model.Subscription {
+	public := make([]model.Subscription, 0, len(subs))
+	for _, sub := range subs {
+		if !sub.Category.Personal {
+			public = append(public, sub)
+		}
+	}
+	return public
+}
+
+// formatBotSelectCategoriesMessage describes the categories nested in the parent, the top level if nil,
+// available for subscription.
+func formatBotSelectCategoriesMessage(l *i18n.Localizer, subs []model.Subscription, parent *model.Subscription) string {
+	var b strings.Builder
+	parentID := ""
+	if parent != nil {
+		parentID = parent.Category.ID
+		b.WriteString(l.T("msg.categories.select_nested", escapeBotMarkdown(parent.Category.Label(l.Lang))))
+		if desc := parent.Category.LocalDescription(l.Lang); len(desc) > 0 {
+			b.WriteString("\n" + escapeBotMarkdown(desc))
+		}
+	} else {
+		b.WriteString(l.T("msg.categories.select"))
+	}
+	for _, sub := range model.Subscriptions(subs, parentID) {
+		if desc := sub.Category.LocalDescription(l.Lang); len(desc) > 0 {
+			b.WriteString(fmt.Sprintf("\n*%s* — %s", escapeBotMarkdown(sub.Category.Label(l.Lang)), escapeBotMarkdown(desc)))
+		}
+	}
+	return b.String()
+}
+
+// botMarkdownEscaper escapes the characters of the Markdown markup of Telegram.
+var botMarkdownEscaper = strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
+
+// escapeBotMarkdown escapes the text to be shown as is in a Markdown message.
+func escapeBotMarkdown(text string) string {
+	return botMarkdownEscaper.Replace(text)
 }
 
 // botHandleToggleCategoryCallback toggles selection of a category.
+//
+//	Callback data is the ID of the category followed by the number of the page it is shown on.
 func (a *App) botHandleToggleCategoryCallback(ctx context.Context, cb *telebot.Callback) {
 	user := ctx.Value(BotCtxUser).(*model.Subscriber)
 
-	cat, err := a.CategoryModel.Get(ctx, cb.Data)
+	catID, page := parseBotPageData(cb.Data)
+	cat, err := a.CategoryModel.Get(ctx, catID)
 	if err != nil {
 		log.Printf("[bot] botHandleToggleCategoryCallback(): get category: %v", err)
 		return
@@ -144,25 +521,127 @@ func (a *App) botHandleToggleCategoryCallback(ctx context.Context, cb *telebot.C
 		}
 	}
 
-	subs, err := a.SubscriptionModel.GetSubscriptionStatus(ctx, user)
+	a.helperShowSelectCategories(ctx, cb, user, cat.ID, page)
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleCategoryFeedsCallback shows the feeds of a category.
+//
+//	Callback data is the ID of the category followed by the number of the page to show.
+func (a *App) botHandleCategoryFeedsCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+
+	catID, page := parseBotPageData(cb.Data)
+	cat, err := a.CategoryModel.Get(ctx, catID)
+	if err == nil && cat.Personal {
+		err = model.ErrInvalidCategory
+	}
+	if err != nil {
+		log.Printf("[bot] botHandleCategoryFeedsCallback(): get category: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: a.botLocalizer(user).T("msg.error"), ShowAlert: true})
+		return
+	}
+	a.helperShowCategoryFeeds(ctx, cb, user, cat, page)
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleToggleFeedCallback toggles a feed of a category.
+//
+//	The feed is muted or unmuted if the user receives the updates from the category,
+//	subscribed to or unsubscribed from on its own otherwise.
+//	Callback data is the ID of the category, the ID of the feed and the number of the page it is shown on.
+func (a *App) botHandleToggleFeedCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	data := strings.Split(cb.Data, "|")
+	if len(data) != 3 {
+		_ = a.Bot.Respond(cb)
+		return
+	}
+	page, _ := strconv.Atoi(data[2])
+	cat, err := a.CategoryModel.Get(ctx, data[0])
+	if err == nil && cat.Personal {
+		err = model.ErrInvalidCategory
+	}
+	if err != nil {
+		log.Printf("[bot] botHandleToggleFeedCallback(): get category: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	feed, err := a.FeedModel.Get(ctx, cat, data[1])
+	if err != nil {
+		log.Printf("[bot] botHandleToggleFeedCallback(): get feed: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	sub, err := a.SubscriptionModel.GetCategorySubscription(ctx, user, *cat)
+	if err != nil {
+		log.Printf("[bot] botHandleToggleFeedCallback(): subscription status: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	switch {
+	case user.IsMuted(*feed):
+		err = a.SubscriptionModel.UnmuteFeed(ctx, user, *feed)
+	case sub.Receives():
+		err = a.SubscriptionModel.MuteFeed(ctx, user, *feed)
+	case user.HasFeed(*feed):
+		err = a.SubscriptionModel.UnsubscribeFeed(ctx, user, *feed)
+	default:
+		err = a.SubscriptionModel.SubscribeFeed(ctx, user, *feed)
+	}
+	if err != nil {
+		log.Printf("[bot] botHandleToggleFeedCallback(): toggle feed: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+
+	a.helperShowCategoryFeeds(ctx, cb, user, cat, page)
+	_ = a.Bot.Respond(cb)
+}
+
+// helperShowCategoryFeeds shows a page of the feeds of the category along with their subscription status.
+func (a *App) helperShowCategoryFeeds(ctx context.Context, cb *telebot.Callback, user *model.Subscriber, cat *model.Category, page int) {
+	l := a.botLocalizer(user)
+	sub, err := a.SubscriptionModel.GetCategorySubscription(ctx, user, *cat)
+	if err != nil {
+		log.Printf("[bot] helperShowCategoryFeeds(): subscription status: %v", err)
+		return
+	}
+	feeds, err := a.FeedModel.GetAll(ctx, cat)
 	if err != nil {
-		log.Printf("[bot] botHandleToggleCategoryCallback(): subscription status: %v", err)
+		log.Printf("[bot] helperShowCategoryFeeds(): get feeds: %v", err)
 		return
 	}
+	subs := model.FeedSubscriptions(user, feeds, sub.Receives())
 	if _, err := a.Bot.Edit(
 		cb.Message,
-		fmt.Sprintf("Select categories for which you would like to recieve updates:"),
+		formatBotCategoryFeedsMessage(l, *sub, subs),
 		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-		NewBotMenuSelectCategories(subs).Menu,
-	); err != nil {
-		log.Printf("[bot] botHandleToggleCategoryCallback(): Failed to edit message: %v", err)
+		NewBotMenuCategoryFeeds(l, *cat, subs, page).Menu,
+	); err != nil && !strings.Contains(err.Error(), "new message content and reply markup are exactly the same") {
+		log.Printf("[bot] helperShowCategoryFeeds(): Failed to edit message: %v", err)
+	}
+}
+
+// formatBotCategoryFeedsMessage describes the feeds of the category and what choosing one does.
+func formatBotCategoryFeedsMessage(l *i18n.Localizer, sub model.Subscription, feeds []model.FeedSubscription) string {
+	text := l.T("msg.feeds.header", sub.Category.Label(l.Lang)) + "\n"
+	switch {
+	case len(feeds) == 0:
+		return text + l.T("msg.feeds.none")
+	case sub.Receives():
+		return text + l.T("msg.feeds.mute")
+	default:
+		return text + l.T("msg.feeds.pick")
 	}
-	_ = a.Bot.Respond(cb)
 }
 
 // botHandleCategoryUpdatesCallback shows the oldest update from selected category.
 func (a *App) botHandleCategoryUpdatesCallback(ctx context.Context, cb *telebot.Callback) {
 	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
 
 	cat, err := a.CategoryModel.Get(ctx, cb.Data)
 	if err != nil {
@@ -174,9 +653,9 @@ func (a *App) botHandleCategoryUpdatesCallback(ctx context.Context, cb *telebot.
 	if err == model.ErrNoUpdates {
 		if _, err := a.Bot.Edit(
 			cb.Message,
-			fmt.Sprintf("You don't have any updates available in category *%v*", cat.Name),
+			l.T("msg.updates.none_in_category", cat.LocalName(l.Lang)),
 			&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-			NewBotMenuNoUpdatesInCategory().Menu,
+			NewBotMenuNoUpdatesInCategory(l).Menu,
 		); err != nil {
 			log.Printf("[bot] botHandleCategoryUpdatesCallback(): Failed to edit message: %v", err)
 		}
@@ -187,41 +666,90 @@ func (a *App) botHandleCategoryUpdatesCallback(ctx context.Context, cb *telebot.
 		log.Printf("[bot] botHandleCategoryUpdatesCallback(): shift update: %v", err)
 		return
 	}
+	h := a.helperAddToHistory(ctx, user, up)
+	a.helperShowUpdate(ctx, cb, user, cat, up, h.ID, 1)
+}
+
+// botHandleCategoryPreviousUpdateCallback shows again an update from the reading history of selected category.
+func (a *App) botHandleCategoryPreviousUpdateCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	data := strings.SplitN(cb.Data, "|", 2)
+	if len(data) != 2 {
+		_ = a.Bot.Respond(cb)
+		return
+	}
+	offset, _ := strconv.Atoi(data[1])
+	cat, err := a.CategoryModel.Get(ctx, data[0])
+	if err != nil {
+		log.Printf("[bot] botHandleCategoryPreviousUpdateCallback(): get category: %v", err)
+		return
+	}
+	history, err := a.HistoryModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleCategoryPreviousUpdateCallback(): get history: %v", err)
+		return
+	}
+	var items []model.HistoryItem
+	for _, h := range history {
+		if h.CategoryID == cat.ID {
+			items = append(items, h)
+		}
+	}
+	if offset < 0 || offset >= len(items) {
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.updates.no_earlier")})
+		return
+	}
+	a.helperShowUpdate(ctx, cb, user, cat, items[offset].Update(cat), items[offset].ID, offset+1)
+}
+
+// helperAddToHistory records the delivered update in the reading history, the outdated records are pruned by the cleanup cron.
+//
+//	Returns the new record, it has no ID if it failed to be saved.
+func (a *App) helperAddToHistory(ctx context.Context, user *model.Subscriber, up *model.Update) *model.HistoryItem {
+	h := model.NewHistoryItem(user, up, time.Now().UTC())
+	if err := a.HistoryModel.Add(ctx, h); err != nil {
+		log.Printf("[bot] helperAddToHistory(): add: %v", err)
+		h.ID = ""
+	}
+	return h
+}
 
+// helperShowUpdate replaces the menu with the update followed by the controls to navigate the category.
+//
+//	historyID is the ID of the update in the reading history,
+//	prev is the position in the reading history of the category the Previous button leads to.
+func (a *App) helperShowUpdate(ctx context.Context, cb *telebot.Callback, user *model.Subscriber, cat *model.Category, up *model.Update, historyID string, prev int) {
+	l := a.botLocalizer(user)
 	if err := a.Bot.Delete(cb.Message); err != nil {
-		log.Printf("[bot] botHandleCategoryUpdatesCallback(): Failed to delete prev message: %v", err)
+		log.Printf("[bot] helperShowUpdate(): Failed to delete prev message: %v", err)
 	}
 	if _, err := a.Bot.Send(
-		cb.Sender,
+		cb.Message.Chat,
 		up.FormatMessage(),
 		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuUpdate(l, historyID, false).Menu,
 	); err != nil {
-		log.Printf("[bot] botHandleCategoryUpdatesCallback(): Failed to show update: %v", err)
+		log.Printf("[bot] helperShowUpdate(): Failed to show update: %v", err)
+		a.helperHandleSendError(ctx, user, err)
 	}
 	sub, err := a.SubscriptionModel.GetCategorySubscription(ctx, user, *cat)
 	if err != nil {
-		log.Printf("[bot] botHandleCategoryUpdatesCallback(): get status: %v", err)
+		log.Printf("[bot] helperShowUpdate(): get status: %v", err)
 		return
 	}
-	if sub.Unread > 0 {
-		if _, err := a.Bot.Send(
-			cb.Sender,
-			fmt.Sprintf("There %d more update(s) in category *%v*", sub.Unread, cat.Name),
-			&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-			NewBotMenuCategoryNextUpdate(cat).Menu,
-		); err != nil {
-			log.Printf("[bot] botHandleCategoryUpdatesCallback(): Failed to show update: %v", err)
-		}
-	} else {
-		if _, err := a.Bot.Send(
-			cb.Sender,
-			fmt.Sprintf("There are no more updates available in category *%v*", cat.Name),
-			&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-			NewBotMenuNoUpdatesInCategory().Menu,
-		); err != nil {
-			log.Printf("[bot] botHandleCategoryUpdatesCallback(): Failed to edit message: %v", err)
-		}
-
+	text := l.T("msg.updates.no_more", cat.LocalName(l.Lang))
+	if sub.OwnUnread > 0 {
+		text = l.N("msg.updates.more", sub.OwnUnread, sub.OwnUnread, cat.LocalName(l.Lang))
+	}
+	if _, err := a.Bot.Send(
+		cb.Message.Chat,
+		text,
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuCategoryNextUpdate(l, cat, prev, sub.OwnUnread > 0).Menu,
+	); err != nil {
+		log.Printf("[bot] helperShowUpdate(): Failed to show update: %v", err)
 	}
 	_ = a.Bot.Respond(cb)
 }
@@ -231,12 +759,15 @@ func (a *App) botHandleCategoryUpdatesCallback(ctx context.Context, cb *telebot.
 //	Provides user with a choise to delete his data from the service.
 //	- Confirm action will be handled by botHandleDeleteConfirmCallback
 //	- Cancel action will be handled by botHandleDeleteCancelCallback
-func (a *App) botHandleDeleteCmd(_ context.Context, m *telebot.Message) {
+func (a *App) botHandleDeleteCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
 	if _, err := a.Bot.Send(
-		m.Sender,
-		fmt.Sprintf("You data is about to be deleted from our service"),
+		m.Chat,
+		l.T("msg.delete.confirm"),
 		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
-		NewBotMenuDelete().Menu,
+		NewBotMenuDelete(l).Menu,
 	); err != nil {
 		log.Printf("[bot] botHandleDeleteCmd() Failed to reply: %v", err)
 	}
@@ -245,26 +776,53 @@ func (a *App) botHandleDeleteCmd(_ context.Context, m *telebot.Message) {
 	}
 }
 
+// botHandleExportCmd handles /export command.
+//
+//	Sends user's data, including the alerts and the bookmarks, as a JSON file.
+func (a *App) botHandleExportCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	data, err := a.exportSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleExportCmd(): export user: %v", err)
+		if _, err := a.Bot.Send(m.Chat, l.T("msg.error")); err != nil {
+			log.Printf("[bot] botHandleExportCmd(): Failed to reply: %v", err)
+		}
+		return
+	}
+	doc := &telebot.Document{
+		File:     telebot.FromReader(bytes.NewReader(data)),
+		MIME:     "application/json",
+		FileName: "news-feed-bot.json",
+		Caption:  l.T("msg.export.caption"),
+	}
+	if _, err := a.Bot.Send(m.Chat, doc); err != nil {
+		log.Printf("[bot] botHandleExportCmd(): Failed to send export: %v", err)
+	}
+}
+
 // botHandleDeleteConfirmCallback handles confirmation callback of Delete User menu.
 func (a *App) botHandleDeleteConfirmCallback(ctx context.Context, cb *telebot.Callback) {
 	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
 
 	if err := a.SubscriberModel.Delete(ctx, user); err != nil {
 		log.Printf("[bot] botHandleDeleteConfirmCallback() Failed to delete user: %v", err)
-		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: "Something went wrong, please try again later.", ShowAlert: true})
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
 		return
 	}
 
 	if _, err := a.Bot.Edit(
 		cb.Message,
-		fmt.Sprintf("Your data was successfully deleted 👍"),
+		l.T("msg.delete.done"),
 		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
 	); err != nil {
 		log.Printf("[bot] botHandleDeleteConfirmCallback() Failed to edit message: %v", err)
 	}
 	if _, err := a.Bot.Send(
-		cb.Sender,
-		"You can always come back later, if you want. See you!",
+		cb.Message.Chat,
+		l.T("msg.delete.bye"),
 	); err != nil {
 		log.Printf("[bot] botHandleDeleteConfirmCallback() Failed to reply: %v", err)
 	}
@@ -272,10 +830,13 @@ func (a *App) botHandleDeleteConfirmCallback(ctx context.Context, cb *telebot.Ca
 }
 
 // botHandleDeleteCancelCallback handles cancellation callback of Delete User menu.
-func (a *App) botHandleDeleteCancelCallback(_ context.Context, cb *telebot.Callback) {
+func (a *App) botHandleDeleteCancelCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
 	if _, err := a.Bot.Edit(
 		cb.Message,
-		fmt.Sprintf("Your data will not be deleted 👍"),
+		l.T("msg.delete.cancelled"),
 		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
 	); err != nil {
 		log.Printf("[bot] botHandleDeleteCancelCallback() Failed to edit message: %v", err)
@@ -283,6 +844,2204 @@ func (a *App) botHandleDeleteCancelCallback(_ context.Context, cb *telebot.Callb
 	_ = a.Bot.Respond(cb)
 }
 
-// botHandleTextMessage is an arbitrary method to handle any text message that was not handled by a specific handler.
-func (a *App) botHandleTextMessage(_ context.Context, _ *telebot.Message) {
+// botHandleFiltersCallback handles request to show the list of subscribed categories to set up filters for.
+//
+//	Callback data is the number of the page to show.
+func (a *App) botHandleFiltersCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	page, _ := strconv.Atoi(cb.Data)
+	subs, err := a.SubscriptionModel.GetSubscriptionStatus(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleFiltersCallback(): subscription status: %v", err)
+		return
+	}
+	selectedSubs := make([]model.Subscription, 0, len(subs))
+	for _, sub := range subs {
+		if sub.Receives() {
+			selectedSubs = append(selectedSubs, sub)
+		}
+	}
+	if len(selectedSubs) == 0 {
+		if _, err := a.Bot.Edit(
+			cb.Message,
+			l.T("msg.updates.no_categories_selected"),
+			&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+			NewBotMenuNoCategoriesSelected(l).Menu,
+		); err != nil {
+			log.Printf("[bot] botHandleFiltersCallback(): Failed to edit message: %v", err)
+		}
+	} else {
+		if _, err := a.Bot.Edit(
+			cb.Message,
+			l.T("msg.filters.select"),
+			&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+			NewBotMenuFilterCategories(l, selectedSubs, user, page).Menu,
+		); err != nil {
+			log.Printf("[bot] botHandleFiltersCallback(): Failed to edit message: %v", err)
+		}
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleFilterCategoryCallback shows the filter of selected category.
+func (a *App) botHandleFilterCategoryCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	cat, err := a.CategoryModel.Get(ctx, cb.Data)
+	if err != nil {
+		log.Printf("[bot] botHandleFilterCategoryCallback(): get category: %v", err)
+		return
+	}
+	if len(user.Input) > 0 {
+		user.Input = ""
+		if err := a.SubscriberModel.Save(ctx, user); err != nil {
+			log.Printf("[bot] botHandleFilterCategoryCallback(): save user: %v", err)
+		}
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotFilterMessage(l, cat, user.GetFilter(*cat)),
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuCategoryFilter(l, cat).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleFilterCategoryCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleFilterIncludeCallback asks user for a pattern to include updates in selected category.
+func (a *App) botHandleFilterIncludeCallback(ctx context.Context, cb *telebot.Callback) {
+	a.helperRequestFilterPattern(ctx, cb, BotInputFilterInclude, "msg.filter.include_prompt")
+}
+
+// botHandleFilterExcludeCallback asks user for a pattern to exclude updates from selected category.
+func (a *App) botHandleFilterExcludeCallback(ctx context.Context, cb *telebot.Callback) {
+	a.helperRequestFilterPattern(ctx, cb, BotInputFilterExclude, "msg.filter.exclude_prompt")
+}
+
+func (a *App) helperRequestFilterPattern(ctx context.Context, cb *telebot.Callback, input string, prompt string) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	cat, err := a.CategoryModel.Get(ctx, cb.Data)
+	if err != nil {
+		log.Printf("[bot] helperRequestFilterPattern(): get category: %v", err)
+		return
+	}
+	user.Input = input + ":" + cat.ID
+	if err := a.SubscriberModel.Save(ctx, user); err != nil {
+		log.Printf("[bot] helperRequestFilterPattern(): save user: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		l.T(prompt, escapeBotMarkdown(cat.LocalName(l.Lang))),
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuCategoryFilterInput(l, cat).Menu,
+	); err != nil {
+		log.Printf("[bot] helperRequestFilterPattern(): Failed to edit message: %v", err)
+	}
+	a.helperRequestGroupReply(user, cb.Message.Chat, l)
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleFilterClearCallback removes the filter of selected category.
+func (a *App) botHandleFilterClearCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	cat, err := a.CategoryModel.Get(ctx, cb.Data)
+	if err != nil {
+		log.Printf("[bot] botHandleFilterClearCallback(): get category: %v", err)
+		return
+	}
+	if err := a.SubscriptionModel.SetFilter(ctx, user, *cat, model.Filter{}); err != nil {
+		log.Printf("[bot] botHandleFilterClearCallback(): set filter: %v", err)
+		return
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotFilterMessage(l, cat, model.Filter{}),
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuCategoryFilter(l, cat).Menu,
+	); err != nil && !strings.Contains(err.Error(), "new message content and reply markup are exactly the same") {
+		log.Printf("[bot] botHandleFilterClearCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleFilterPatternInput adds a pattern sent by user to the filter of a category.
+func (a *App) botHandleFilterPatternInput(ctx context.Context, m *telebot.Message, input string, catID string) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	cat, err := a.CategoryModel.Get(ctx, catID)
+	if err != nil {
+		log.Printf("[bot] botHandleFilterPatternInput(): get category: %v", err)
+		return
+	}
+	pattern := strings.TrimSpace(m.Text)
+	if err := model.ValidatePattern(pattern); err != nil {
+		if _, err := a.Bot.Send(
+			m.Chat,
+			l.N("msg.filter.invalid", model.MaxPatternLength),
+			NewBotMenuCategoryFilterInput(l, cat).Menu,
+		); err != nil {
+			log.Printf("[bot] botHandleFilterPatternInput(): Failed to reply: %v", err)
+		}
+		return
+	}
+	filter := user.GetFilter(*cat)
+	if filter.Len() >= model.MaxFilterPatterns {
+		if _, err := a.Bot.Send(
+			m.Chat,
+			l.N("msg.filter.too_many", model.MaxFilterPatterns),
+			NewBotMenuCategoryFilter(l, cat).Menu,
+		); err != nil {
+			log.Printf("[bot] botHandleFilterPatternInput(): Failed to reply: %v", err)
+		}
+		return
+	}
+	if input == BotInputFilterInclude {
+		filter.Include = append(filter.Include, pattern)
+	} else {
+		filter.Exclude = append(filter.Exclude, pattern)
+	}
+	user.Input = ""
+	if err := a.SubscriptionModel.SetFilter(ctx, user, *cat, filter); err != nil {
+		log.Printf("[bot] botHandleFilterPatternInput(): set filter: %v", err)
+		return
+	}
+	if _, err := a.Bot.Send(
+		m.Chat,
+		formatBotFilterMessage(l, cat, filter),
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuCategoryFilter(l, cat).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleFilterPatternInput(): Failed to reply: %v", err)
+	}
+}
+
+// formatBotFilterMessage describes the filter of a category.
+func formatBotFilterMessage(l *i18n.Localizer, cat *model.Category, filter model.Filter) string {
+	var b strings.Builder
+	b.WriteString(l.T("msg.filter.header", escapeBotMarkdown(cat.LocalName(l.Lang))) + "\n")
+	if filter.IsEmpty() {
+		b.WriteString(l.T("msg.filter.none") + "\n")
+	}
+	if len(filter.Include) > 0 {
+		b.WriteString(l.T("msg.filter.include", formatBotFilterPatterns(filter.Include)) + "\n")
+	}
+	if len(filter.Exclude) > 0 {
+		b.WriteString(l.T("msg.filter.exclude", formatBotFilterPatterns(filter.Exclude)) + "\n")
+	}
+	b.WriteString("\n" + l.T("msg.filter.help"))
+	return b.String()
+}
+
+// formatBotFilterPatterns lists the patterns of a filter escaping their Markdown.
+func formatBotFilterPatterns(patterns []string) string {
+	escaped := make([]string, len(patterns))
+	for i, p := range patterns {
+		escaped[i] = escapeBotMarkdown(p)
+	}
+	return strings.Join(escaped, ", ")
+}
+
+// botHandleAlertCmd handles /alert command.
+//
+//	Adds an alert for the phrase following the command, or shows the list of alerts.
+func (a *App) botHandleAlertCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	if phrase := strings.TrimSpace(m.Payload); len(phrase) > 0 {
+		if msg, err := a.helperAddAlert(ctx, l, user, phrase); err != nil {
+			if _, err := a.Bot.Send(m.Chat, msg); err != nil {
+				log.Printf("[bot] botHandleAlertCmd(): Failed to reply: %v", err)
+			}
+			return
+		}
+	}
+	alerts, err := a.AlertModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleAlertCmd(): get alerts: %v", err)
+		return
+	}
+	if _, err := a.Bot.Send(
+		m.Chat,
+		formatBotAlertsMessage(l, alerts),
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuAlerts(l, alerts, 0).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleAlertCmd(): Failed to reply: %v", err)
+	}
+}
+
+// botHandleAlertsCallback handles request to show the list of alerts.
+//
+//	Callback data is the number of the page to show.
+func (a *App) botHandleAlertsCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	if len(user.Input) > 0 {
+		user.Input = ""
+		if err := a.SubscriberModel.Save(ctx, user); err != nil {
+			log.Printf("[bot] botHandleAlertsCallback(): save user: %v", err)
+		}
+	}
+	page, _ := strconv.Atoi(cb.Data)
+	alerts, err := a.AlertModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleAlertsCallback(): get alerts: %v", err)
+		return
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotAlertsMessage(l, alerts),
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuAlerts(l, alerts, page).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleAlertsCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleAlertAddCallback asks user for a phrase to be alerted about.
+func (a *App) botHandleAlertAddCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	user.Input = BotInputAlert + ":"
+	if err := a.SubscriberModel.Save(ctx, user); err != nil {
+		log.Printf("[bot] botHandleAlertAddCallback(): save user: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		l.T("msg.alert.prompt"),
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuAlertInput(l).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleAlertAddCallback(): Failed to edit message: %v", err)
+	}
+	a.helperRequestGroupReply(user, cb.Message.Chat, l)
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleAlertRemoveCallback removes selected alert.
+//
+//	Callback data is the ID of the alert followed by the number of the page it is shown on.
+func (a *App) botHandleAlertRemoveCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	alertID, page := parseBotPageData(cb.Data)
+	alerts, err := a.AlertModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleAlertRemoveCallback(): get alerts: %v", err)
+		return
+	}
+	for i, alert := range alerts {
+		if alert.ID != alertID {
+			continue
+		}
+		if err := a.AlertModel.Delete(ctx, &alert); err != nil {
+			log.Printf("[bot] botHandleAlertRemoveCallback(): delete alert: %v", err)
+			return
+		}
+		alerts = append(alerts[:i], alerts[i+1:]...)
+		if a.Alerts != nil {
+			a.Alerts.Invalidate()
+		}
+		break
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotAlertsMessage(l, alerts),
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuAlerts(l, alerts, page).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleAlertRemoveCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleAlertPhraseInput adds an alert for the phrase sent by user.
+func (a *App) botHandleAlertPhraseInput(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	if msg, err := a.helperAddAlert(ctx, l, user, strings.TrimSpace(m.Text)); err != nil {
+		if _, err := a.Bot.Send(m.Chat, msg, NewBotMenuAlertInput(l).Menu); err != nil {
+			log.Printf("[bot] botHandleAlertPhraseInput(): Failed to reply: %v", err)
+		}
+		return
+	}
+	user.Input = ""
+	if err := a.SubscriberModel.Save(ctx, user); err != nil {
+		log.Printf("[bot] botHandleAlertPhraseInput(): save user: %v", err)
+	}
+	alerts, err := a.AlertModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleAlertPhraseInput(): get alerts: %v", err)
+		return
+	}
+	if _, err := a.Bot.Send(
+		m.Chat,
+		formatBotAlertsMessage(l, alerts),
+		&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		NewBotMenuAlerts(l, alerts, 0).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleAlertPhraseInput(): Failed to reply: %v", err)
+	}
+}
+
+// helperAddAlert creates an alert for the phrase, on failure returns the message to show to the user.
+func (a *App) helperAddAlert(ctx context.Context, l *i18n.Localizer, user *model.Subscriber, phrase string) (string, error) {
+	if err := model.ValidateAlertPhrase(phrase); err != nil {
+		return l.N("msg.alert.invalid", model.MaxAlertPhraseLength), err
+	}
+	alerts, err := a.AlertModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] helperAddAlert(): get alerts: %v", err)
+		return l.T("msg.error"), err
+	}
+	if len(alerts) >= model.MaxAlerts {
+		return l.N("msg.alert.too_many", model.MaxAlerts), model.ErrTooManyAlerts
+	}
+	for _, alert := range alerts {
+		if strings.EqualFold(alert.Phrase, phrase) {
+			return l.T("msg.alert.duplicate"), model.ErrInvalidAlert
+		}
+	}
+	if _, err := a.AlertModel.Create(ctx, &model.Alert{Subscriber: user, Phrase: phrase}); err != nil {
+		log.Printf("[bot] helperAddAlert(): create alert: %v", err)
+		return l.T("msg.error"), err
+	}
+	if a.Alerts != nil {
+		a.Alerts.Invalidate()
+	}
+	return "", nil
+}
+
+// formatBotAlertsMessage describes the alerts of the user.
+func formatBotAlertsMessage(l *i18n.Localizer, alerts []model.Alert) string {
+	if len(alerts) == 0 {
+		return l.T("msg.alerts.empty")
+	}
+	var b strings.Builder
+	b.WriteString(l.T("msg.alerts.header") + "\n")
+	for _, alert := range alerts {
+		b.WriteString("`" + alert.Phrase + "`\n")
+	}
+	b.WriteString("\n" + l.T("msg.alerts.help"))
+	return b.String()
+}
+
+// botDiscoverTimeout limits the time spent on looking for a feed at the link sent by user.
+const botDiscoverTimeout = 15 * time.Second
+
+// botHandleCustomFeedsCmd handles /feeds command.
+//
+//	Adds the feed at the link following the command, or shows the list of the feeds added by user.
+func (a *App) botHandleCustomFeedsCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	var notice string
+	if link := strings.TrimSpace(m.Payload); len(link) > 0 {
+		msg, err := a.helperAddCustomFeed(ctx, l, user, link)
+		if err != nil {
+			if _, err := a.Bot.Send(m.Chat, msg); err != nil {
+				log.Printf("[bot] botHandleCustomFeedsCmd(): Failed to reply: %v", err)
+			}
+			return
+		}
+		notice = msg + "\n\n"
+	}
+	feeds, err := a.helperCustomFeeds(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleCustomFeedsCmd(): get feeds: %v", err)
+		return
+	}
+	if _, err := a.Bot.Send(
+		m.Chat,
+		notice+formatBotCustomFeedsMessage(l, feeds),
+		NewBotMenuCustomFeeds(l, feeds, 0).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleCustomFeedsCmd(): Failed to reply: %v", err)
+	}
+}
+
+// botHandleCustomFeedsCallback handles request to show the list of the feeds added by user.
+//
+//	Callback data is the number of the page to show.
+func (a *App) botHandleCustomFeedsCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	if len(user.Input) > 0 {
+		user.Input = ""
+		if err := a.SubscriberModel.Save(ctx, user); err != nil {
+			log.Printf("[bot] botHandleCustomFeedsCallback(): save user: %v", err)
+		}
+	}
+	page, _ := strconv.Atoi(cb.Data)
+	feeds, err := a.helperCustomFeeds(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleCustomFeedsCallback(): get feeds: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotCustomFeedsMessage(l, feeds),
+		NewBotMenuCustomFeeds(l, feeds, page).Menu,
+	); err != nil && !strings.Contains(err.Error(), "new message content and reply markup are exactly the same") {
+		log.Printf("[bot] botHandleCustomFeedsCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleCustomFeedAddCallback asks user for a link to the feed to add.
+func (a *App) botHandleCustomFeedAddCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	user.Input = BotInputCustomFeed + ":"
+	if err := a.SubscriberModel.Save(ctx, user); err != nil {
+		log.Printf("[bot] botHandleCustomFeedAddCallback(): save user: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		l.T("msg.custom_feed.prompt"),
+		NewBotMenuCustomFeedInput(l).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleCustomFeedAddCallback(): Failed to edit message: %v", err)
+	}
+	a.helperRequestGroupReply(user, cb.Message.Chat, l)
+	_ = a.Bot.Respond(cb)
+}
+
+// helperRequestGroupReply asks a group to reply with the input it was prompted for.
+//
+//	In privacy mode the bot only receives the messages of a group that reply to its own,
+//	so the plain input would never reach botHandleTextMessage.
+func (a *App) helperRequestGroupReply(user *model.Subscriber, chat *telebot.Chat, l *i18n.Localizer) {
+	if !user.IsGroup() {
+		return
+	}
+	if _, err := a.Bot.Send(
+		chat,
+		l.T("msg.input.reply"),
+		&telebot.ReplyMarkup{ForceReply: true},
+	); err != nil {
+		log.Printf("[bot] helperRequestGroupReply(): Failed to send message: %v", err)
+	}
+}
+
+// botHandleCustomFeedRemoveCallback unsubscribes user from selected feed.
+//
+//	The feed itself is deleted by the cleanup once no one is subscribed to it.
+//	Callback data is the ID of the feed followed by the number of the page it is shown on.
+func (a *App) botHandleCustomFeedRemoveCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	feedID, page := parseBotPageData(cb.Data)
+	if err := a.SubscriptionModel.UnsubscribeFeed(ctx, user, model.Feed{ID: feedID}); err != nil {
+		log.Printf("[bot] botHandleCustomFeedRemoveCallback(): unsubscribe: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	feeds, err := a.helperCustomFeeds(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleCustomFeedRemoveCallback(): get feeds: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotCustomFeedsMessage(l, feeds),
+		NewBotMenuCustomFeeds(l, feeds, page).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleCustomFeedRemoveCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleCustomFeedURLInput adds the feed at the link sent by user.
+func (a *App) botHandleCustomFeedURLInput(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	msg, err := a.helperAddCustomFeed(ctx, l, user, strings.TrimSpace(m.Text))
+	if err != nil {
+		if _, err := a.Bot.Send(m.Chat, msg, NewBotMenuCustomFeedInput(l).Menu); err != nil {
+			log.Printf("[bot] botHandleCustomFeedURLInput(): Failed to reply: %v", err)
+		}
+		return
+	}
+	user.Input = ""
+	if err := a.SubscriberModel.Save(ctx, user); err != nil {
+		log.Printf("[bot] botHandleCustomFeedURLInput(): save user: %v", err)
+	}
+	feeds, err := a.helperCustomFeeds(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleCustomFeedURLInput(): get feeds: %v", err)
+		return
+	}
+	if _, err := a.Bot.Send(
+		m.Chat,
+		msg+"\n\n"+formatBotCustomFeedsMessage(l, feeds),
+		NewBotMenuCustomFeeds(l, feeds, 0).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleCustomFeedURLInput(): Failed to reply: %v", err)
+	}
+}
+
+// helperAddCustomFeed subscribes user to the feed at the link, returns the message to show to the user.
+//
+//	The link is looked up among the feeds known already, so the same feed is fetched once,
+//	a new feed is discovered through the fetcher and added to the personal category.
+func (a *App) helperAddCustomFeed(ctx context.Context, l *i18n.Localizer, user *model.Subscriber, link string) (string, error) {
+	if err := model.ValidateFeedURL(link); err != nil {
+		return l.T("msg.custom_feed.invalid"), err
+	}
+	feeds, err := a.helperCustomFeeds(ctx, user)
+	if err != nil {
+		log.Printf("[bot] helperAddCustomFeed(): get feeds: %v", err)
+		return l.T("msg.error"), err
+	}
+	if len(feeds) >= model.MaxCustomFeeds {
+		return l.N("msg.custom_feed.too_many", model.MaxCustomFeeds), model.ErrTooManyFeeds
+	}
+
+	feed, err := a.helperFindFeed(ctx, link)
+	if err == model.ErrNotFound {
+		dctx, cancel := context.WithTimeout(ctx, botDiscoverTimeout)
+		defer cancel()
+		found, derr := fetcher.New(a.SubscriptionModel).Discover(dctx, link)
+		if derr != nil {
+			log.Printf("[bot] helperAddCustomFeed(): discover %q: %v", link, derr)
+			return l.T("msg.custom_feed.not_found"), derr
+		}
+		if feed, err = a.helperFindFeed(ctx, found.URL); err == model.ErrNotFound {
+			feed, err = a.helperCreateCustomFeed(ctx, found)
+		}
+	}
+	if err != nil {
+		log.Printf("[bot] helperAddCustomFeed(): get feed: %v", err)
+		return l.T("msg.error"), err
+	}
+
+	if feed.Category.Personal && user.HasFeed(*feed) {
+		return l.T("msg.custom_feed.duplicate"), model.ErrInvalidFeed
+	}
+	if err := a.SubscriptionModel.SubscribeFeed(ctx, user, *feed); err != nil {
+		log.Printf("[bot] helperAddCustomFeed(): subscribe: %v", err)
+		return l.T("msg.error"), err
+	}
+	title := categoryFeedLabel(model.FeedSubscription{Feed: *feed})
+	if !feed.Category.Personal {
+		return l.T("msg.custom_feed.shared", title, feed.Category.Label(l.Lang)), nil
+	}
+	return l.T("msg.custom_feed.added", title), nil
+}
+
+// helperCreateCustomFeed adds the feed to the personal category, the category is created if missing.
+func (a *App) helperCreateCustomFeed(ctx context.Context, feed *model.Feed) (*model.Feed, error) {
+	cats, err := a.CategoryModel.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cat, ok := model.FindPersonalCategory(cats)
+	if !ok {
+		cat = *model.NewPersonalCategory()
+		if _, err := a.CategoryModel.Create(ctx, &cat); err != nil {
+			return nil, err
+		}
+	}
+	feed.Category = &cat
+	feed.LastUpdate = time.Now().UTC()
+	if _, err := a.FeedModel.Create(ctx, feed); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// helperFindFeed looks for the feed with the link in all categories, returns model.ErrNotFound if there is none.
+//
+//	The feed of a category available to everyone is preferred to the one added by users.
+func (a *App) helperFindFeed(ctx context.Context, link string) (*model.Feed, error) {
+	cats, err := a.CategoryModel.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := model.FeedURLKey(link)
+	var found *model.Feed
+	for i := range cats {
+		feeds, err := a.FeedModel.GetAll(ctx, &cats[i])
+		if err != nil {
+			return nil, err
+		}
+		for j := range feeds {
+			if model.FeedURLKey(feeds[j].URL) != key {
+				continue
+			}
+			feeds[j].Category = &cats[i]
+			if !cats[i].Personal {
+				return &feeds[j], nil
+			}
+			found = &feeds[j]
+		}
+	}
+	if found == nil {
+		return nil, model.ErrNotFound
+	}
+	return found, nil
+}
+
+// helperFindSharedFeed looks for the feed with the link in the categories available to everyone,
+// returns model.ErrNotFound if there is none.
+func (a *App) helperFindSharedFeed(ctx context.Context, link string) (*model.Feed, error) {
+	feed, err := a.helperFindFeed(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	if feed.Category.Personal {
+		return nil, model.ErrNotFound
+	}
+	return feed, nil
+}
+
+// helperCustomFeeds returns the feeds of the personal category user is subscribed to.
+func (a *App) helperCustomFeeds(ctx context.Context, user *model.Subscriber) ([]model.Feed, error) {
+	cats, err := a.CategoryModel.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cat, ok := model.FindPersonalCategory(cats)
+	if !ok {
+		return nil, nil
+	}
+	feeds := make([]model.Feed, 0, len(user.Feeds))
+	for _, id := range user.Feeds {
+		f, err := a.FeedModel.Get(ctx, &cat, id)
+		if err == model.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, *f)
+	}
+	return feeds, nil
+}
+
+// formatBotCustomFeedsMessage describes the feeds added by user.
+func formatBotCustomFeedsMessage(l *i18n.Localizer, feeds []model.Feed) string {
+	if len(feeds) == 0 {
+		return l.T("msg.custom_feeds.empty")
+	}
+	var b strings.Builder
+	b.WriteString(l.T("msg.custom_feeds.header") + "\n")
+	for _, f := range feeds {
+		b.WriteString("\n" + categoryFeedLabel(model.FeedSubscription{Feed: f}) + " — " + f.URL)
+	}
+	b.WriteString("\n\n" + l.T("msg.custom_feeds.help"))
+	return b.String()
+}
+
+// botHandleSuggestCmd handles /suggest command.
+//
+//	Queues the feed at the link following the command for the admins to add it for everyone.
+func (a *App) botHandleSuggestCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	msg := l.T("msg.suggest.help")
+	if link := strings.TrimSpace(m.Payload); len(link) > 0 {
+		msg, _ = a.helperSuggestFeed(ctx, l, user, link)
+	}
+	if _, err := a.Bot.Send(m.Chat, msg); err != nil {
+		log.Printf("[bot] botHandleSuggestCmd(): Failed to reply: %v", err)
+	}
+}
+
+// helperSuggestFeed queues the feed at the link for moderation and notifies the admins,
+// returns the message to show to the user.
+func (a *App) helperSuggestFeed(ctx context.Context, l *i18n.Localizer, user *model.Subscriber, link string) (string, error) {
+	if len(a.Config.BotAdmins) == 0 {
+		return l.T("msg.suggest.disabled"), model.ErrInvalidSuggestion
+	}
+	if err := model.ValidateFeedURL(link); err != nil {
+		return l.T("msg.custom_feed.invalid"), err
+	}
+	own, err := a.SuggestionModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] helperSuggestFeed(): get suggestions: %v", err)
+		return l.T("msg.error"), err
+	}
+	pending := 0
+	for _, s := range own {
+		if s.IsPending() {
+			pending++
+		}
+	}
+	if pending >= model.MaxPendingSuggestions {
+		return l.N("msg.suggest.too_many", model.MaxPendingSuggestions), model.ErrTooManySuggestions
+	}
+	if msg, err := a.helperCheckSuggestedFeed(ctx, l, link); err != nil {
+		return msg, err
+	}
+
+	dctx, cancel := context.WithTimeout(ctx, botDiscoverTimeout)
+	defer cancel()
+	found, err := fetcher.New(a.SubscriptionModel).Discover(dctx, link)
+	if err != nil {
+		log.Printf("[bot] helperSuggestFeed(): discover %q: %v", link, err)
+		return l.T("msg.custom_feed.not_found"), err
+	}
+	if msg, err := a.helperCheckSuggestedFeed(ctx, l, found.URL); err != nil {
+		return msg, err
+	}
+
+	s := &model.Suggestion{
+		Subscriber: user,
+		Title:      found.Title,
+		URL:        found.URL,
+		Status:     model.SuggestionPending,
+		Created:    time.Now().UTC(),
+	}
+	if _, err := a.SuggestionModel.Create(ctx, s); err != nil {
+		log.Printf("[bot] helperSuggestFeed(): create suggestion: %v", err)
+		return l.T("msg.error"), err
+	}
+	a.helperNotifyBotAdmins(ctx, s)
+	return l.T("msg.suggest.queued", suggestionLabel(*s)), nil
+}
+
+// helperCheckSuggestedFeed makes sure the feed at the link is neither available for everyone
+// nor waiting for moderation already, returns the message to show to the user otherwise.
+func (a *App) helperCheckSuggestedFeed(ctx context.Context, l *i18n.Localizer, link string) (string, error) {
+	feed, err := a.helperFindSharedFeed(ctx, link)
+	if err == nil {
+		title := categoryFeedLabel(model.FeedSubscription{Feed: *feed})
+		return l.T("msg.suggest.exists", title, feed.Category.Label(l.Lang)), model.ErrInvalidSuggestion
+	}
+	if err != model.ErrNotFound {
+		log.Printf("[bot] helperCheckSuggestedFeed(): get feed: %v", err)
+		return l.T("msg.error"), err
+	}
+	pending, err := a.SuggestionModel.GetPending(ctx)
+	if err != nil {
+		log.Printf("[bot] helperCheckSuggestedFeed(): get pending suggestions: %v", err)
+		return l.T("msg.error"), err
+	}
+	key := model.FeedURLKey(link)
+	for _, s := range pending {
+		if model.FeedURLKey(s.URL) == key {
+			return l.T("msg.suggest.duplicate"), model.ErrInvalidSuggestion
+		}
+	}
+	return "", nil
+}
+
+// helperNotifyBotAdmins sends the suggestion with the moderation buttons to every admin.
+func (a *App) helperNotifyBotAdmins(ctx context.Context, s *model.Suggestion) {
+	for _, id := range a.Config.BotAdmins {
+		admin, err := a.SubscriberModel.Get(ctx, "telegram:"+id)
+		if err != nil {
+			admin = model.NewSubscriber("telegram:" + id)
+		}
+		to, err := botRecipient(admin)
+		if err != nil {
+			log.Printf("[bot] helperNotifyBotAdmins(): invalid admin %q: %v", id, err)
+			continue
+		}
+		l := a.botLocalizer(admin)
+		if _, err := a.Bot.Send(to, formatBotSuggestionMessage(l, s), NewBotMenuSuggestion(l, s).Menu); err != nil {
+			log.Printf("[bot] helperNotifyBotAdmins(): Failed to notify %q: %v", id, err)
+		}
+	}
+}
+
+// botHandleSuggestionApproveCallback shows admin the categories to approve the suggested feed into.
+//
+//	Callback data is the ID of the suggestion followed by the number of the page of categories to show.
+func (a *App) botHandleSuggestionApproveCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	id, page := parseBotPageData(cb.Data)
+	s, ok := a.helperGetPendingSuggestion(ctx, cb, l, id)
+	if !ok {
+		return
+	}
+	cats, err := a.CategoryModel.GetAll(ctx)
+	if err != nil {
+		log.Printf("[bot] botHandleSuggestionApproveCallback(): get categories: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	sort.Slice(cats, func(i, j int) bool {
+		return categoryPathLabel(l, cats, cats[i]) < categoryPathLabel(l, cats, cats[j])
+	})
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotSuggestionMessage(l, s)+"\n\n"+l.T("msg.suggestion.pick"),
+		NewBotMenuSuggestionCategories(l, s, cats, page).Menu,
+	); err != nil && !strings.Contains(err.Error(), "new message content and reply markup are exactly the same") {
+		log.Printf("[bot] botHandleSuggestionApproveCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleSuggestionBackCallback returns admin from the list of categories to the moderation buttons.
+//
+//	Callback data is the ID of the suggestion.
+func (a *App) botHandleSuggestionBackCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	s, ok := a.helperGetPendingSuggestion(ctx, cb, l, cb.Data)
+	if !ok {
+		return
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotSuggestionMessage(l, s),
+		NewBotMenuSuggestion(l, s).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleSuggestionBackCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleSuggestionCategoryCallback approves the suggestion adding the feed to the selected category.
+//
+//	Callback data is the ID of the suggestion followed by the ID of the category.
+func (a *App) botHandleSuggestionCategoryCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	data := strings.SplitN(cb.Data, "|", 2)
+	if len(data) != 2 {
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	s, ok := a.helperGetPendingSuggestion(ctx, cb, l, data[0])
+	if !ok {
+		return
+	}
+	cat, err := a.CategoryModel.Get(ctx, data[1])
+	if err != nil || cat.Personal {
+		log.Printf("[bot] botHandleSuggestionCategoryCallback(): get category %q: %v", data[1], err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	feed, err := a.helperFindFeed(ctx, s.URL)
+	var personal *model.Feed
+	if err == nil && feed.Category.Personal {
+		personal, feed = feed, nil
+	} else if err == model.ErrNotFound {
+		feed = nil
+	} else if err != nil {
+		log.Printf("[bot] botHandleSuggestionCategoryCallback(): find feed: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	approved := cat
+	if feed != nil {
+		approved = feed.Category
+	}
+	// the suggestion is claimed before the feed is added, so that the admins approving it at once do not add it twice
+	pending := *s
+	s.Approve(user.UserID, approved, time.Now().UTC())
+	if err := a.SuggestionModel.Moderate(ctx, s); err == model.ErrSuggestionModerated {
+		a.helperGetPendingSuggestion(ctx, cb, l, s.ID)
+		return
+	} else if err != nil {
+		log.Printf("[bot] botHandleSuggestionCategoryCallback(): save suggestion: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	if feed == nil {
+		if feed, err = a.helperAddSuggestedFeed(ctx, s, cat, personal); err != nil {
+			log.Printf("[bot] botHandleSuggestionCategoryCallback(): add feed: %v", err)
+			if err := a.SuggestionModel.Save(ctx, &pending); err != nil {
+				log.Printf("[bot] botHandleSuggestionCategoryCallback(): restore suggestion: %v", err)
+			}
+			_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+			return
+		}
+	}
+	log.Printf("[bot] Suggestion %q approved into %q by %s", s.ID, feed.Category.ID, user.UserID)
+	if _, err := a.Bot.Edit(cb.Message, formatBotModeratedSuggestionMessage(l, s, feed.Category)); err != nil {
+		log.Printf("[bot] botHandleSuggestionCategoryCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+	a.helperNotifySuggester(ctx, s, feed.Category)
+}
+
+// helperAddSuggestedFeed adds the feed of the approved suggestion to the category.
+//
+//	The feed added by the subscribers already is moved from the personal category along with its subscribers
+//	and the state of the fetching, so that the updates they have got are not sent again.
+func (a *App) helperAddSuggestedFeed(ctx context.Context, s *model.Suggestion, cat *model.Category, personal *model.Feed) (*model.Feed, error) {
+	feed := &model.Feed{Category: cat, Title: s.Title, URL: s.URL, LastUpdate: time.Now().UTC()}
+	if personal != nil {
+		feed.LastUpdate, feed.SeenGUIDs = personal.LastUpdate, personal.SeenGUIDs
+	}
+	if _, err := a.FeedModel.Create(ctx, feed); err != nil {
+		return nil, err
+	}
+	if personal == nil {
+		return feed, nil
+	}
+	// the personal feed is kept for its subscribers unless they are moved
+	if err := a.SubscriberModel.ReplaceFeed(ctx, personal, feed); err != nil {
+		log.Printf("[bot] helperAddSuggestedFeed(): move subscribers of %q: %v", personal.ID, err)
+		return feed, nil
+	}
+	if err := a.FeedModel.Delete(ctx, personal); err != nil {
+		log.Printf("[bot] helperAddSuggestedFeed(): delete personal feed %q: %v", personal.ID, err)
+	}
+	return feed, nil
+}
+
+// botHandleSuggestionRejectCallback rejects the suggestion.
+//
+//	Callback data is the ID of the suggestion.
+func (a *App) botHandleSuggestionRejectCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	s, ok := a.helperGetPendingSuggestion(ctx, cb, l, cb.Data)
+	if !ok {
+		return
+	}
+	s.Reject(user.UserID, time.Now().UTC())
+	if err := a.SuggestionModel.Moderate(ctx, s); err == model.ErrSuggestionModerated {
+		a.helperGetPendingSuggestion(ctx, cb, l, s.ID)
+		return
+	} else if err != nil {
+		log.Printf("[bot] botHandleSuggestionRejectCallback(): save suggestion: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	log.Printf("[bot] Suggestion %q rejected by %s", s.ID, user.UserID)
+	if _, err := a.Bot.Edit(cb.Message, formatBotModeratedSuggestionMessage(l, s, nil)); err != nil {
+		log.Printf("[bot] botHandleSuggestionRejectCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+	a.helperNotifySuggester(ctx, s, nil)
+}
+
+// helperGetPendingSuggestion loads the suggestion for the admin to moderate.
+//
+//	Responds to the callback and tells false if the suggestion can't be moderated,
+//	the message of a suggestion moderated already is updated with the outcome.
+func (a *App) helperGetPendingSuggestion(ctx context.Context, cb *telebot.Callback, l *i18n.Localizer, id string) (*model.Suggestion, bool) {
+	s, err := a.SuggestionModel.Get(ctx, id)
+	if err != nil {
+		log.Printf("[bot] helperGetPendingSuggestion(): get suggestion %q: %v", id, err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return nil, false
+	}
+	if !s.IsPending() {
+		var cat *model.Category
+		if s.Status == model.SuggestionApproved {
+			cat, _ = a.CategoryModel.Get(ctx, s.Category)
+		}
+		if _, err := a.Bot.Edit(cb.Message, formatBotModeratedSuggestionMessage(l, s, cat)); err != nil {
+			log.Printf("[bot] helperGetPendingSuggestion(): Failed to edit message: %v", err)
+		}
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.suggestion.moderated")})
+		return nil, false
+	}
+	return s, true
+}
+
+// helperNotifySuggester tells the author of the suggestion whether it was approved into the category or rejected.
+func (a *App) helperNotifySuggester(ctx context.Context, s *model.Suggestion, cat *model.Category) {
+	if s.Subscriber == nil || len(s.Subscriber.ID) == 0 {
+		return
+	}
+	to, err := botRecipient(s.Subscriber)
+	if err != nil {
+		log.Printf("[bot] helperNotifySuggester(): %v", err)
+		return
+	}
+	l := a.botLocalizer(s.Subscriber)
+	msg := l.T("msg.suggest.rejected", suggestionLabel(*s))
+	if s.Status == model.SuggestionApproved && cat != nil {
+		msg = l.T("msg.suggest.approved", suggestionLabel(*s), cat.Label(l.Lang))
+	}
+	if _, err := a.Bot.Send(to, msg); err != nil {
+		a.helperHandleSendError(ctx, s.Subscriber, err)
+	}
+}
+
+// suggestionLabel returns the title of the suggested feed falling back to its URL.
+func suggestionLabel(s model.Suggestion) string {
+	if len(s.Title) > 0 {
+		return s.Title
+	}
+	return s.URL
+}
+
+// formatBotSuggestionMessage describes the suggestion to the admin.
+func formatBotSuggestionMessage(l *i18n.Localizer, s *model.Suggestion) string {
+	author := "?"
+	if s.Subscriber != nil {
+		author = s.Subscriber.Name
+		if len(author) == 0 {
+			author = s.Subscriber.UserID
+		}
+	}
+	return l.T("msg.suggestion.new", author, suggestionLabel(*s), s.URL)
+}
+
+// formatBotModeratedSuggestionMessage describes the suggestion to the admin along with the outcome of the moderation.
+func formatBotModeratedSuggestionMessage(l *i18n.Localizer, s *model.Suggestion, cat *model.Category) string {
+	outcome := l.T("msg.suggestion.rejected")
+	if s.Status == model.SuggestionApproved {
+		label := s.Category
+		if cat != nil {
+			label = cat.Label(l.Lang)
+		}
+		outcome = l.T("msg.suggestion.approved", label)
+	}
+	return formatBotSuggestionMessage(l, s) + "\n\n" + outcome
+}
+
+// botHandleAdminCmd handles /admin command available to the bot admins.
+//
+//	The first word following the command selects the action, the rest are its arguments.
+func (a *App) botHandleAdminCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	args := strings.Fields(m.Payload)
+	if len(args) == 0 {
+		args = []string{"help"}
+	}
+	switch args[0] {
+	case "categories":
+		a.helperAdminCategories(ctx, m, l)
+	case "addfeed":
+		a.helperAdminAddFeed(ctx, m, l, args[1:])
+	case "delfeed":
+		a.helperAdminDeleteFeed(ctx, m, l, args[1:])
+	case "fetch":
+		a.helperAdminFetch(ctx, m, l, args[1:])
+	case "stats":
+		a.helperAdminStats(ctx, m, l)
+	case "broadcast":
+		a.helperAdminBroadcast(ctx, m, l, strings.TrimSpace(m.Payload)[len(args[0]):])
+	case "broadcasts":
+		a.helperAdminBroadcasts(ctx, m, l)
+	default:
+		a.helperAdminReply(m, l.T("msg.admin.help"))
+	}
+}
+
+// helperAdminReply sends the plain text reply to the admin command.
+func (a *App) helperAdminReply(m *telebot.Message, msg string, options ...interface{}) {
+	if _, err := a.Bot.Send(m.Chat, msg, options...); err != nil {
+		log.Printf("[bot] helperAdminReply(): Failed to reply: %v", err)
+	}
+}
+
+// helperAdminCategories lists the categories along with their IDs and the number of feeds.
+func (a *App) helperAdminCategories(ctx context.Context, m *telebot.Message, l *i18n.Localizer) {
+	cats, err := a.CategoryModel.GetAll(ctx)
+	if err != nil {
+		log.Printf("[bot] helperAdminCategories(): get categories: %v", err)
+		a.helperAdminReply(m, l.T("msg.error"))
+		return
+	}
+	if len(cats) == 0 {
+		a.helperAdminReply(m, l.T("msg.categories.none"))
+		return
+	}
+	labels := make(map[string]string, len(cats))
+	for _, cat := range cats {
+		labels[cat.ID] = categoryPathLabel(l, cats, cat)
+	}
+	sort.Slice(cats, func(i, j int) bool {
+		return labels[cats[i].ID] < labels[cats[j].ID]
+	})
+	var b strings.Builder
+	b.WriteString(l.T("msg.admin.categories.header"))
+	for i := range cats {
+		feeds, err := a.FeedModel.GetAll(ctx, &cats[i])
+		if err != nil {
+			log.Printf("[bot] helperAdminCategories(): get feeds of %q: %v", cats[i].ID, err)
+			a.helperAdminReply(m, l.T("msg.error"))
+			return
+		}
+		b.WriteString("\n" + l.T("msg.admin.categories.item", cats[i].ID, html.EscapeString(labels[cats[i].ID]), len(feeds)))
+	}
+	a.helperAdminReply(m, b.String(), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+}
+
+// helperAdminAddFeed adds the feed at the link to the category, the arguments are the ID of the category and the link.
+func (a *App) helperAdminAddFeed(ctx context.Context, m *telebot.Message, l *i18n.Localizer, args []string) {
+	if len(args) != 2 {
+		a.helperAdminReply(m, l.T("msg.admin.addfeed.usage"))
+		return
+	}
+	cat, err := a.CategoryModel.Get(ctx, args[0])
+	if err == model.ErrNotFound || (err == nil && cat.Personal) {
+		a.helperAdminReply(m, l.T("msg.admin.category.not_found", args[0]))
+		return
+	}
+	if err != nil {
+		log.Printf("[bot] helperAdminAddFeed(): get category %q: %v", args[0], err)
+		a.helperAdminReply(m, l.T("msg.error"))
+		return
+	}
+	link := args[1]
+	if err := model.ValidateFeedURL(link); err != nil {
+		a.helperAdminReply(m, l.T("msg.custom_feed.invalid"))
+		return
+	}
+	if msg, ok := a.helperAdminCheckFeed(ctx, l, link); !ok {
+		a.helperAdminReply(m, msg)
+		return
+	}
+	dctx, cancel := context.WithTimeout(ctx, botDiscoverTimeout)
+	defer cancel()
+	found, err := fetcher.New(a.SubscriptionModel).Discover(dctx, link)
+	if err != nil {
+		log.Printf("[bot] helperAdminAddFeed(): discover %q: %v", link, err)
+		a.helperAdminReply(m, l.T("msg.custom_feed.not_found"))
+		return
+	}
+	if msg, ok := a.helperAdminCheckFeed(ctx, l, found.URL); !ok {
+		a.helperAdminReply(m, msg)
+		return
+	}
+	feed := &model.Feed{Category: cat, Title: found.Title, URL: found.URL, LastUpdate: time.Now().UTC()}
+	if _, err := a.FeedModel.Create(ctx, feed); err != nil {
+		log.Printf("[bot] helperAdminAddFeed(): create feed: %v", err)
+		a.helperAdminReply(m, l.T("msg.error"))
+		return
+	}
+	log.Printf("[bot] Feed %q added to %q by admin", feed.ID, cat.ID)
+	title := categoryFeedLabel(model.FeedSubscription{Feed: *feed})
+	a.helperAdminReply(m, l.T("msg.admin.addfeed.done", title, cat.Label(l.Lang), feed.ID))
+}
+
+// helperAdminCheckFeed makes sure the feed at the link is not available to everyone already,
+// returns the message to show to the admin otherwise.
+func (a *App) helperAdminCheckFeed(ctx context.Context, l *i18n.Localizer, link string) (string, bool) {
+	feed, err := a.helperFindSharedFeed(ctx, link)
+	if err == model.ErrNotFound {
+		return "", true
+	}
+	if err != nil {
+		log.Printf("[bot] helperAdminCheckFeed(): get feed: %v", err)
+		return l.T("msg.error"), false
+	}
+	title := categoryFeedLabel(model.FeedSubscription{Feed: *feed})
+	return l.T("msg.suggest.exists", title, feed.Category.Label(l.Lang)), false
+}
+
+// helperAdminDeleteFeed asks the admin to confirm the deletion of the feed, the argument is the ID or the link of the feed.
+func (a *App) helperAdminDeleteFeed(ctx context.Context, m *telebot.Message, l *i18n.Localizer, args []string) {
+	if len(args) != 1 {
+		a.helperAdminReply(m, l.T("msg.admin.delfeed.usage"))
+		return
+	}
+	feed, err := a.helperFindFeedByRef(ctx, args[0])
+	if err == model.ErrNotFound {
+		a.helperAdminReply(m, l.T("msg.admin.feed.not_found", args[0]))
+		return
+	}
+	if err != nil {
+		log.Printf("[bot] helperAdminDeleteFeed(): get feed: %v", err)
+		a.helperAdminReply(m, l.T("msg.error"))
+		return
+	}
+	title := categoryFeedLabel(model.FeedSubscription{Feed: *feed})
+	a.helperAdminReply(
+		m,
+		l.T("msg.admin.delfeed.confirm", title, feed.Category.Label(l.Lang), feed.URL),
+		NewBotMenuAdminDeleteFeed(l, feed).Menu,
+	)
+}
+
+// helperFindFeedByRef looks for the feed with the ID or the link in all categories,
+// returns model.ErrNotFound if there is none.
+func (a *App) helperFindFeedByRef(ctx context.Context, ref string) (*model.Feed, error) {
+	if model.ValidateFeedURL(ref) == nil {
+		return a.helperFindFeed(ctx, ref)
+	}
+	cats, err := a.CategoryModel.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cats {
+		feed, err := a.FeedModel.Get(ctx, &cats[i], ref)
+		if err == model.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return feed, nil
+	}
+	return nil, model.ErrNotFound
+}
+
+// botHandleAdminDeleteFeedConfirmCallback deletes the feed confirmed by the admin.
+//
+//	Callback data is the ID of the category followed by the ID of the feed.
+func (a *App) botHandleAdminDeleteFeedConfirmCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	data := strings.SplitN(cb.Data, "|", 2)
+	if len(data) != 2 {
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	cat, err := a.CategoryModel.Get(ctx, data[0])
+	if err != nil {
+		log.Printf("[bot] botHandleAdminDeleteFeedConfirmCallback(): get category %q: %v", data[0], err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	feed, err := a.FeedModel.Get(ctx, cat, data[1])
+	if err == model.ErrNotFound {
+		if _, err := a.Bot.Edit(cb.Message, l.T("msg.admin.feed.not_found", data[1])); err != nil {
+			log.Printf("[bot] botHandleAdminDeleteFeedConfirmCallback(): Failed to edit message: %v", err)
+		}
+		_ = a.Bot.Respond(cb)
+		return
+	}
+	if err == nil {
+		err = a.FeedModel.Delete(ctx, feed)
+	}
+	if err != nil {
+		log.Printf("[bot] botHandleAdminDeleteFeedConfirmCallback(): delete feed: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	log.Printf("[bot] Feed %q of %q deleted by %s", feed.ID, cat.ID, user.UserID)
+	title := categoryFeedLabel(model.FeedSubscription{Feed: *feed})
+	if _, err := a.Bot.Edit(cb.Message, l.T("msg.admin.delfeed.done", title)); err != nil {
+		log.Printf("[bot] botHandleAdminDeleteFeedConfirmCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleAdminDeleteFeedCancelCallback handles cancellation of the deletion of a feed.
+func (a *App) botHandleAdminDeleteFeedCancelCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	if _, err := a.Bot.Edit(cb.Message, l.T("msg.admin.delfeed.cancelled")); err != nil {
+		log.Printf("[bot] botHandleAdminDeleteFeedCancelCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// helperAdminFetch fetches all the feeds regardless of their schedule, the optional argument is the ID of the category
+// to fetch the feeds of.
+//
+//	The fetch takes a while, so the admin is replied to once again when it is over.
+func (a *App) helperAdminFetch(ctx context.Context, m *telebot.Message, l *i18n.Localizer, args []string) {
+	if len(args) > 1 {
+		a.helperAdminReply(m, l.T("msg.admin.fetch.usage"))
+		return
+	}
+	var cat *model.Category
+	if len(args) == 1 {
+		var err error
+		if cat, err = a.CategoryModel.Get(ctx, args[0]); err == model.ErrNotFound {
+			a.helperAdminReply(m, l.T("msg.admin.category.not_found", args[0]))
+			return
+		} else if err != nil {
+			log.Printf("[bot] helperAdminFetch(): get category %q: %v", args[0], err)
+			a.helperAdminReply(m, l.T("msg.error"))
+			return
+		}
+	}
+	a.helperAdminReply(m, l.T("msg.admin.fetch.started"))
+	go func() {
+		var err error
+		if cat != nil {
+			a.Coordinator.FetchCategory(ctx, cat, true)
+		} else {
+			err = a.Coordinator.FetchAll(ctx, true)
+		}
+		a.saveSearchIndex()
+		if err != nil {
+			log.Printf("[bot] helperAdminFetch(): %v", err)
+			a.helperAdminReply(m, l.T("msg.admin.fetch.failed", err.Error()))
+			return
+		}
+		a.helperAdminReply(m, l.T("msg.admin.fetch.done"))
+	}()
+}
+
+// botAdminStats are the totals shown to the admin.
+type botAdminStats struct {
+	Subscribers int // Subscribers is the number of all subscribers.
+	Active      int // Active is the number of subscribers the bot can reach.
+	Joined      int // Joined is the number of subscribers who started using the bot within the last week.
+	Seen        int // Seen is the number of subscribers who used the bot within the last week.
+	Categories  int // Categories is the number of categories available to everyone.
+	Feeds       int // Feeds is the number of feeds in the categories available to everyone.
+	CustomFeeds int // CustomFeeds is the number of feeds added by the subscribers.
+	Unread      int // Unread is the number of unread updates of all subscribers.
+}
+
+// helperAdminStats shows the totals of the subscribers, the feeds and the unread updates.
+func (a *App) helperAdminStats(ctx context.Context, m *telebot.Message, l *i18n.Localizer) {
+	st, err := a.adminStats(ctx, time.Now())
+	if err != nil {
+		log.Printf("[bot] helperAdminStats(): %v", err)
+		a.helperAdminReply(m, l.T("msg.error"))
+		return
+	}
+	a.helperAdminReply(m, formatBotAdminStatsMessage(l, st))
+}
+
+// adminStats counts the totals without loading the entities, the subscribers joined and seen are counted
+// within a week before now.
+func (a *App) adminStats(ctx context.Context, now time.Time) (botAdminStats, error) {
+	const week = 7 * 24 * time.Hour
+	var st botAdminStats
+	ss, err := a.SubscriberModel.GetStats(ctx, now.Add(-week))
+	if err != nil {
+		return st, fmt.Errorf("count subscribers: %v", err)
+	}
+	st.Subscribers, st.Active, st.Joined, st.Seen = ss.Total, ss.Active, ss.Joined, ss.Seen
+	cats, err := a.CategoryModel.GetAll(ctx)
+	if err != nil {
+		return st, fmt.Errorf("get categories: %v", err)
+	}
+	for i := range cats {
+		n, err := a.FeedModel.GetCount(ctx, &cats[i])
+		if err != nil {
+			return st, fmt.Errorf("count feeds of %q: %v", cats[i].ID, err)
+		}
+		if cats[i].Personal {
+			st.CustomFeeds += n
+			continue
+		}
+		st.Categories++
+		st.Feeds += n
+	}
+	if st.Unread, err = a.SubscriptionModel.GetUnreadCount(ctx); err != nil {
+		return st, fmt.Errorf("count unread updates: %v", err)
+	}
+	return st, nil
+}
+
+// formatBotAdminStatsMessage describes the totals to the admin.
+func formatBotAdminStatsMessage(l *i18n.Localizer, st botAdminStats) string {
+	return l.T("msg.admin.stats",
+		st.Subscribers, st.Active, st.Subscribers-st.Active, st.Joined, st.Seen,
+		st.Categories, st.Feeds, st.CustomFeeds, st.Unread,
+	)
+}
+
+// botAdminBroadcastsLimit is the number of the recent broadcasts shown to the admin.
+const botAdminBroadcastsLimit = 5
+
+// helperAdminBroadcast asks the admin to confirm the broadcast, the arguments are the segment
+// of the subscribers to send the message to followed by the message itself.
+func (a *App) helperAdminBroadcast(ctx context.Context, m *telebot.Message, l *i18n.Localizer, args string) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	if a.Broadcasts == nil {
+		a.helperAdminReply(m, l.T("msg.error"))
+		return
+	}
+	segment, text := splitBotCommandArg(args)
+	b := &model.Broadcast{Text: text, Author: user.UserID, Created: time.Now().UTC()}
+	if err := b.SetSegment(segment); err != nil || model.ValidateBroadcastText(text) != nil {
+		a.helperAdminReply(m, l.T("msg.admin.broadcast.usage", model.MaxBroadcastLength))
+		return
+	}
+	if len(b.Category) > 0 {
+		if _, err := a.CategoryModel.Get(ctx, b.Category); err != nil {
+			a.helperAdminReply(m, l.T("msg.admin.category.not_found", b.Category))
+			return
+		}
+	}
+	recipients, err := a.Broadcasts.Recipients(ctx, *b)
+	if err != nil {
+		log.Printf("[bot] helperAdminBroadcast(): %v", err)
+		a.helperAdminReply(m, l.T("msg.error"))
+		return
+	}
+	if _, err := a.BroadcastModel.Create(ctx, b); err != nil {
+		log.Printf("[bot] helperAdminBroadcast(): create broadcast: %v", err)
+		a.helperAdminReply(m, l.T("msg.error"))
+		return
+	}
+	a.helperAdminReply(
+		m,
+		l.N("msg.admin.broadcast.confirm", len(recipients), b.Segment())+"\n\n"+b.Text,
+		NewBotMenuAdminBroadcast(l, b).Menu,
+	)
+}
+
+// splitBotCommandArg splits the first word off the arguments of a command keeping the rest intact.
+func splitBotCommandArg(args string) (string, string) {
+	args = strings.TrimSpace(args)
+	i := strings.IndexFunc(args, unicode.IsSpace)
+	if i < 0 {
+		return args, ""
+	}
+	return args[:i], strings.TrimSpace(args[i:])
+}
+
+// botHandleAdminBroadcastConfirmCallback starts the delivery of the broadcast confirmed by the admin.
+//
+//	The delivery takes a while, so the admin is sent the report once it is over.
+//	Callback data is the ID of the broadcast.
+func (a *App) botHandleAdminBroadcastConfirmCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	b, err := a.BroadcastModel.Get(ctx, cb.Data)
+	if err != nil {
+		log.Printf("[bot] botHandleAdminBroadcastConfirmCallback(): get broadcast %q: %v", cb.Data, err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	if b.IsStarted() {
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.admin.broadcast.already_started")})
+		return
+	}
+	b.Started = time.Now().UTC()
+	if err := a.BroadcastModel.Save(ctx, b); err != nil {
+		log.Printf("[bot] botHandleAdminBroadcastConfirmCallback(): save broadcast: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	log.Printf("[bot] Broadcast %q started by %s", b.ID, user.UserID)
+	if _, err := a.Bot.Edit(cb.Message, l.T("msg.admin.broadcast.started", b.Segment())+"\n\n"+b.Text); err != nil {
+		log.Printf("[bot] botHandleAdminBroadcastConfirmCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+
+	go func() {
+		err := a.Broadcasts.Run(ctx, b)
+		if err == model.ErrLeaseTaken {
+			return
+		}
+		if err != nil {
+			log.Printf("[bot] botHandleAdminBroadcastConfirmCallback(): deliver %q: %v", b.ID, err)
+			if _, err := a.Bot.Send(cb.Message.Chat, l.T("msg.admin.broadcast.interrupted", b.Total())); err != nil {
+				log.Printf("[bot] botHandleAdminBroadcastConfirmCallback(): Failed to send report: %v", err)
+			}
+			return
+		}
+		if _, err := a.Bot.Send(cb.Message.Chat, formatBotBroadcastReport(l, *b)); err != nil {
+			log.Printf("[bot] botHandleAdminBroadcastConfirmCallback(): Failed to send report: %v", err)
+		}
+	}()
+}
+
+// botHandleAdminBroadcastCancelCallback discards the broadcast not confirmed by the admin.
+//
+//	Callback data is the ID of the broadcast.
+func (a *App) botHandleAdminBroadcastCancelCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	b, err := a.BroadcastModel.Get(ctx, cb.Data)
+	if err != nil && err != model.ErrNotFound {
+		log.Printf("[bot] botHandleAdminBroadcastCancelCallback(): get broadcast %q: %v", cb.Data, err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	if err == nil && b.IsStarted() {
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.admin.broadcast.already_started")})
+		return
+	}
+	if err == nil {
+		if err := a.BroadcastModel.Delete(ctx, b); err != nil {
+			log.Printf("[bot] botHandleAdminBroadcastCancelCallback(): delete broadcast: %v", err)
+		}
+	}
+	if _, err := a.Bot.Edit(cb.Message, l.T("msg.admin.broadcast.cancelled")); err != nil {
+		log.Printf("[bot] botHandleAdminBroadcastCancelCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// helperAdminBroadcasts shows the progress of the recent broadcasts.
+func (a *App) helperAdminBroadcasts(ctx context.Context, m *telebot.Message, l *i18n.Localizer) {
+	if a.BroadcastModel == nil {
+		a.helperAdminReply(m, l.T("msg.error"))
+		return
+	}
+	bs, err := a.BroadcastModel.GetAll(ctx)
+	if err != nil {
+		log.Printf("[bot] helperAdminBroadcasts(): get broadcasts: %v", err)
+		a.helperAdminReply(m, l.T("msg.error"))
+		return
+	}
+	started := make([]model.Broadcast, 0, botAdminBroadcastsLimit)
+	for _, b := range bs {
+		if b.IsStarted() && len(started) < botAdminBroadcastsLimit {
+			started = append(started, b)
+		}
+	}
+	if len(started) == 0 {
+		a.helperAdminReply(m, l.T("msg.admin.broadcasts.none"))
+		return
+	}
+	reports := make([]string, 0, len(started))
+	for _, b := range started {
+		reports = append(reports, formatBotBroadcastReport(l, b))
+	}
+	a.helperAdminReply(m, strings.Join(reports, "\n\n"))
+}
+
+// formatBotBroadcastReport describes the progress of the broadcast to the admin.
+func formatBotBroadcastReport(l *i18n.Localizer, b model.Broadcast) string {
+	status := l.T("msg.admin.broadcast.in_progress")
+	if b.IsFinished() {
+		status = l.T("msg.admin.broadcast.finished")
+	}
+	preview := []rune(b.Text)
+	if len(preview) > botBroadcastPreviewLength {
+		preview = append(preview[:botBroadcastPreviewLength], '…')
+	}
+	return l.T("msg.admin.broadcast.report",
+		b.Started.Format("2006-01-02 15:04"), b.Segment(), status,
+		b.Delivered, b.Blocked, b.Failed, string(preview),
+	)
+}
+
+// botBroadcastPreviewLength limits the text of a broadcast shown in the report.
+const botBroadcastPreviewLength = 40
+
+// botSearchMaxQueryLength limits the search query so it fits into the callback data of the page buttons.
+const botSearchMaxQueryLength = 40
+
+// botInlineResultsLimit is the number of inline query results sent at once.
+const botInlineResultsLimit = 20
+
+// botHandleSearchCmd handles /search command.
+//
+//	Shows the first page of recent updates matching the terms following the command.
+func (a *App) botHandleSearchCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	query := strings.TrimSpace(m.Payload)
+	if len(query) == 0 || len(query) > botSearchMaxQueryLength {
+		if _, err := a.Bot.Send(
+			m.Chat,
+			l.N("msg.search.help", botSearchMaxQueryLength, botSearchMaxQueryLength, a.Bot.GetName()),
+			&telebot.SendOptions{ParseMode: telebot.ModeMarkdown},
+		); err != nil {
+			log.Printf("[bot] botHandleSearchCmd(): Failed to reply: %v", err)
+		}
+		return
+	}
+	res := a.Search.Search(query, 0, BotMenuSearchPageSize)
+	if _, err := a.Bot.Send(
+		m.Chat,
+		formatBotSearchMessage(l, query, 0, res),
+		NewBotMenuSearchResults(l, query, 0, res).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleSearchCmd(): Failed to reply: %v", err)
+	}
+}
+
+// botHandleSearchPageCallback shows another page of search results.
+func (a *App) botHandleSearchPageCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	// the query may contain the separator itself, so the page number is taken from the end
+	i := strings.LastIndex(cb.Data, "|")
+	if i < 0 {
+		_ = a.Bot.Respond(cb)
+		return
+	}
+	query := cb.Data[:i]
+	page, err := strconv.Atoi(cb.Data[i+1:])
+	if err != nil || page < 0 {
+		log.Printf("[bot] botHandleSearchPageCallback(): invalid page: %q", cb.Data[i+1:])
+		_ = a.Bot.Respond(cb)
+		return
+	}
+	offset := page * BotMenuSearchPageSize
+	res := a.Search.Search(query, offset, BotMenuSearchPageSize)
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotSearchMessage(l, query, offset, res),
+		NewBotMenuSearchResults(l, query, page, res).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleSearchPageCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// formatBotSearchMessage describes a page of search results.
+func formatBotSearchMessage(l *i18n.Localizer, query string, offset int, res search.Result) string {
+	if len(res.Updates) == 0 {
+		return l.T("msg.search.nothing", query)
+	}
+	return l.T("msg.search.header", offset+1, offset+len(res.Updates), res.Total, query)
+}
+
+// botHandleInlineQuery handles inline queries like `@bot <category or keyword>` sent from any chat.
+//
+//	Answers with the latest updates of the category named in the query or the ones matching the keywords,
+//	an empty query gets the latest updates of all categories. Results are cached per query.
+func (a *App) botHandleInlineQuery(ctx context.Context, q *telebot.Query) {
+	offset, _ := strconv.Atoi(q.Offset)
+	query := strings.TrimSpace(q.Text)
+	key := strings.ToLower(query) + "|" + strconv.Itoa(offset)
+	res, ok := a.InlineCache.Get(key)
+	if !ok {
+		res = a.helperInlineQueryResult(ctx, query, offset)
+		a.InlineCache.Put(key, res)
+	}
+	l := a.I18n.Localizer(q.From.LanguageCode)
+	results := make(telebot.Results, len(res.Updates))
+	for i, up := range res.Updates {
+		result := &telebot.ArticleResult{
+			Title:       up.Title,
+			Description: up.Summary,
+			URL:         up.URL,
+			ThumbURL:    up.Image,
+		}
+		result.SetResultID(strconv.Itoa(offset + i))
+		result.SetContent(&telebot.InputTextMessageContent{
+			Text:      formatStoryHTML(up),
+			ParseMode: telebot.ModeHTML,
+		})
+		result.SetReplyMarkup([][]telebot.InlineButton{{{Text: l.T(BotBtnReadStoryLabel), URL: up.URL}}})
+		results[i] = result
+	}
+	resp := &telebot.QueryResponse{Results: results, CacheTime: int(a.InlineCache.TTL.Seconds())}
+	if next := offset + len(res.Updates); next < res.Total {
+		resp.NextOffset = strconv.Itoa(next)
+	}
+	if err := a.Bot.Answer(q, resp); err != nil {
+		log.Printf("[bot] botHandleInlineQuery(): Failed to answer: %v", err)
+	}
+}
+
+// helperInlineQueryResult finds the updates for an inline query.
+func (a *App) helperInlineQueryResult(ctx context.Context, query string, offset int) search.Result {
+	if len(query) == 0 {
+		return a.Search.Latest("", offset, botInlineResultsLimit)
+	}
+	cats, err := a.CategoryModel.GetAll(ctx)
+	if err != nil {
+		log.Printf("[bot] helperInlineQueryResult(): get categories: %v", err)
+	}
+	for _, cat := range cats {
+		if cat.HasName(query) {
+			return a.Search.Latest(cat.ID, offset, botInlineResultsLimit)
+		}
+	}
+	return a.Search.Search(query, offset, botInlineResultsLimit)
+}
+
+// formatStoryHTML formats the update to be shared in a chat.
+func formatStoryHTML(up model.Update) string {
+	var b strings.Builder
+	b.WriteString("<b>" + html.EscapeString(up.Title) + "</b>\n")
+	if len(up.Summary) > 0 {
+		b.WriteString(html.EscapeString(up.Summary) + "\n")
+	}
+	b.WriteString("\n")
+	if up.Category != nil {
+		b.WriteString("<i>" + html.EscapeString(up.Category.Name) + "</i> | ")
+	}
+	b.WriteString(up.Date.Format(time.RFC1123) + "\n")
+	b.WriteString(html.EscapeString(up.URL))
+	return b.String()
+}
+
+// botHandleSaveUpdateCallback bookmarks the delivered update.
+//
+//	The update itself is already deleted, so the bookmark is made of its record in the reading history.
+func (a *App) botHandleSaveUpdateCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	h, err := a.HistoryModel.Get(ctx, user, cb.Data)
+	if err == model.ErrNotFound {
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.saved.unavailable")})
+		return
+	}
+	if err != nil {
+		log.Printf("[bot] botHandleSaveUpdateCallback(): get history item: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	var cat *model.Category
+	if len(h.CategoryID) > 0 {
+		if cat, err = a.CategoryModel.Get(ctx, h.CategoryID); err != nil {
+			log.Printf("[bot] botHandleSaveUpdateCallback(): get category: %v", err)
+			cat = nil
+		}
+	}
+	if msg, err := a.helperSaveBookmark(ctx, l, user, h.Update(cat)); err != nil {
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: msg})
+		return
+	}
+	if _, err := a.Bot.EditReplyMarkup(cb.Message, NewBotMenuUpdate(l, h.ID, true).Menu); err != nil {
+		log.Printf("[bot] botHandleSaveUpdateCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.saved.done")})
+}
+
+// helperSaveBookmark creates a bookmark for the update, on failure returns the message to show to the user.
+func (a *App) helperSaveBookmark(ctx context.Context, l *i18n.Localizer, user *model.Subscriber, up *model.Update) (string, error) {
+	bookmarks, err := a.BookmarkModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] helperSaveBookmark(): get bookmarks: %v", err)
+		return l.T("msg.error"), err
+	}
+	for _, b := range bookmarks {
+		if b.URL == up.URL {
+			return l.T("msg.saved.duplicate"), model.ErrInvalidBookmark
+		}
+	}
+	if len(bookmarks) >= model.MaxBookmarks {
+		return l.N("msg.saved.too_many", model.MaxBookmarks), model.ErrTooManyBookmarks
+	}
+	b := &model.Bookmark{
+		Subscriber: user,
+		Title:      up.Title,
+		Date:       up.Date,
+		URL:        up.URL,
+		Saved:      time.Now().UTC(),
+	}
+	if len(b.Title) == 0 {
+		b.Title = up.URL
+	}
+	if up.Category != nil {
+		b.Category = up.Category.Name
+	}
+	if _, err := a.BookmarkModel.Create(ctx, b); err != nil {
+		log.Printf("[bot] helperSaveBookmark(): create bookmark: %v", err)
+		return l.T("msg.error"), err
+	}
+	return "", nil
+}
+
+// botHandleHistoryCmd handles /history command.
+//
+//	Shows the first page of the updates recently delivered to user.
+func (a *App) botHandleHistoryCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	history, err := a.HistoryModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleHistoryCmd(): get history: %v", err)
+		return
+	}
+	if _, err := a.Bot.Send(
+		m.Chat,
+		formatBotHistoryMessage(l, history, 0),
+		NewBotMenuHistory(l, history, 0).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleHistoryCmd(): Failed to reply: %v", err)
+	}
+}
+
+// botHandleHistoryPageCallback shows another page of user's reading history.
+func (a *App) botHandleHistoryPageCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	page, _ := strconv.Atoi(cb.Data)
+	history, err := a.HistoryModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleHistoryPageCallback(): get history: %v", err)
+		return
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotHistoryMessage(l, history, page),
+		NewBotMenuHistory(l, history, page).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleHistoryPageCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// formatBotHistoryMessage describes a page of user's reading history.
+func formatBotHistoryMessage(l *i18n.Localizer, history []model.HistoryItem, page int) string {
+	if len(history) == 0 {
+		return l.T("msg.history.empty")
+	}
+	offset := BotMenuHistoryPager.Offset(len(history), page)
+	end := offset + BotMenuHistoryPageSize
+	if end > len(history) {
+		end = len(history)
+	}
+	var b strings.Builder
+	b.WriteString(l.T("msg.history.header", offset+1, end, len(history)) + "\n")
+	for i := offset; i < end; i++ {
+		b.WriteString(fmt.Sprintf("\n%d. %s | %s", i+1, history[i].Title, history[i].Read.Format("02 Jan 15:04")))
+	}
+	return b.String()
+}
+
+// botHandleSavedCmd handles /saved command.
+//
+//	Shows the first page of user's bookmarks.
+func (a *App) botHandleSavedCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	bookmarks, err := a.BookmarkModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleSavedCmd(): get bookmarks: %v", err)
+		return
+	}
+	if _, err := a.Bot.Send(
+		m.Chat,
+		formatBotSavedMessage(l, bookmarks, 0),
+		NewBotMenuSaved(l, bookmarks, 0).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleSavedCmd(): Failed to reply: %v", err)
+	}
+}
+
+// botHandleSavedPageCallback shows another page of user's bookmarks.
+func (a *App) botHandleSavedPageCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	page, _ := strconv.Atoi(cb.Data)
+	bookmarks, err := a.BookmarkModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleSavedPageCallback(): get bookmarks: %v", err)
+		return
+	}
+	a.helperShowSavedPage(cb, l, bookmarks, page)
+}
+
+// botHandleSavedRemoveCallback removes selected bookmark.
+func (a *App) botHandleSavedRemoveCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	data := strings.SplitN(cb.Data, "|", 2)
+	var page int
+	if len(data) == 2 {
+		page, _ = strconv.Atoi(data[1])
+	}
+	bookmarks, err := a.BookmarkModel.GetForSubscriber(ctx, user)
+	if err != nil {
+		log.Printf("[bot] botHandleSavedRemoveCallback(): get bookmarks: %v", err)
+		return
+	}
+	for i, b := range bookmarks {
+		if b.ID != data[0] {
+			continue
+		}
+		if err := a.BookmarkModel.Delete(ctx, &b); err != nil {
+			log.Printf("[bot] botHandleSavedRemoveCallback(): delete bookmark: %v", err)
+			return
+		}
+		bookmarks = append(bookmarks[:i], bookmarks[i+1:]...)
+		break
+	}
+	a.helperShowSavedPage(cb, l, bookmarks, page)
+}
+
+// helperShowSavedPage shows the page of user's bookmarks, the last one if the page is gone after a removal.
+func (a *App) helperShowSavedPage(cb *telebot.Callback, l *i18n.Localizer, bookmarks []model.Bookmark, page int) {
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotSavedMessage(l, bookmarks, page),
+		NewBotMenuSaved(l, bookmarks, page).Menu,
+	); err != nil {
+		log.Printf("[bot] helperShowSavedPage(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// formatBotSavedMessage describes a page of user's bookmarks.
+func formatBotSavedMessage(l *i18n.Localizer, bookmarks []model.Bookmark, page int) string {
+	if len(bookmarks) == 0 {
+		return l.T("msg.saved.empty", l.T(BotMenuUpdateBtnSaveLabel))
+	}
+	offset := BotMenuSavedPager.Offset(len(bookmarks), page)
+	end := offset + BotMenuSavedPageSize
+	if end > len(bookmarks) {
+		end = len(bookmarks)
+	}
+	var b strings.Builder
+	b.WriteString(l.T("msg.saved.header", offset+1, end, len(bookmarks)) + "\n")
+	for i := offset; i < end; i++ {
+		b.WriteString(fmt.Sprintf("\n%d. %s", i+1, bookmarks[i].Title))
+		if len(bookmarks[i].Category) > 0 {
+			b.WriteString(" | " + bookmarks[i].Category)
+		}
+		if !bookmarks[i].Date.IsZero() {
+			b.WriteString(" | " + bookmarks[i].Date.Format("02 Jan 2006"))
+		}
+	}
+	return b.String()
+}
+
+// botHandleLanguageCmd handles /language command.
+//
+//	Shows the list of languages the bot speaks.
+func (a *App) botHandleLanguageCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	if _, err := a.Bot.Send(
+		m.Chat,
+		l.T("msg.language.select"),
+		NewBotMenuLanguage(l, a.I18n, user.Locale, 0).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleLanguageCmd(): Failed to reply: %v", err)
+	}
+}
+
+// botHandleLanguagePageCallback shows another page of the list of languages.
+func (a *App) botHandleLanguagePageCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	page, _ := strconv.Atoi(cb.Data)
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		l.T("msg.language.select"),
+		NewBotMenuLanguage(l, a.I18n, user.Locale, page).Menu,
+	); err != nil && !strings.Contains(err.Error(), "new message content and reply markup are exactly the same") {
+		log.Printf("[bot] botHandleLanguagePageCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleLanguageSelectCallback switches the bot to selected language, or to the one of the Telegram client if empty.
+func (a *App) botHandleLanguageSelectCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+
+	user.Locale = cb.Data
+	if err := a.SubscriberModel.Save(ctx, user); err != nil {
+		log.Printf("[bot] botHandleLanguageSelectCallback(): save user: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: a.botLocalizer(user).T("msg.error"), ShowAlert: true})
+		return
+	}
+	l := a.botLocalizer(user)
+	if user.IsChannel() {
+		a.helperEditChannelMenu(cb, l, user, l.T("msg.language.changed"))
+		_ = a.Bot.Respond(cb)
+		return
+	}
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		l.T("msg.language.changed")+"\n\n"+l.T("msg.select_action"),
+		NewBotMenuMain(l).Menu,
+	); err != nil {
+		log.Printf("[bot] botHandleLanguageSelectCallback(): Failed to edit message: %v", err)
+	}
+	_ = a.Bot.Respond(cb)
+}
+
+// botHandleTextMessage is an arbitrary method to handle any text message that was not handled by a specific handler.
+//
+//	Handles text input the user was asked for.
+func (a *App) botHandleTextMessage(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+
+	input := strings.SplitN(user.Input, ":", 2)
+	if len(input) != 2 {
+		return
+	}
+	// the input asked for in a group is accepted from its admins only
+	if !a.botIsChatAdmin(user, m.Chat, m.Sender) {
+		return
+	}
+	switch input[0] {
+	case BotInputFilterInclude, BotInputFilterExclude:
+		a.botHandleFilterPatternInput(ctx, m, input[0], input[1])
+	case BotInputAlert:
+		a.botHandleAlertPhraseInput(ctx, m)
+	case BotInputCustomFeed:
+		a.botHandleCustomFeedURLInput(ctx, m)
+	}
+}
+
+// botHandleChannelCmd handles the commands posted to a channel.
+//
+//	/start, /menu and /autopost show the settings of the channel, /template sets the template of the posts,
+//	/language picks the language of the bot. The command posts are deleted so the audience does not see them.
+func (a *App) botHandleChannelCmd(ctx context.Context, m *telebot.Message) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	cmd, _, _ := parseBotCommand(m.Text, a.Bot.Me.Username)
+	switch cmd {
+	case "/start", "/menu", "/autopost":
+		a.helperSendChannelMenu(m, l, user, "")
+	case "/template":
+		a.helperSetChannelTemplate(ctx, m, l, user)
+	case "/language":
+		a.botHandleLanguageCmd(ctx, m)
+	default:
+		return
+	}
+	if err := a.Bot.Delete(m); err != nil {
+		log.Printf("[bot] botHandleChannelCmd(): Failed to delete command: %v", err)
+	}
+}
+
+// helperSetChannelTemplate sets the template of the posts to the payload of the command,
+// an empty payload restores the default template.
+func (a *App) helperSetChannelTemplate(ctx context.Context, m *telebot.Message, l *i18n.Localizer, user *model.Subscriber) {
+	if len(m.Payload) > 0 {
+		if _, err := autopost.ParseTemplate(m.Payload); err != nil {
+			a.helperSendChannelMenu(m, l, user, l.T("msg.channel.template.invalid", autopost.MaxTemplateLength, err.Error()))
+			return
+		}
+	}
+	user.Template = m.Payload
+	if err := a.SubscriberModel.Save(ctx, user); err != nil {
+		log.Printf("[bot] helperSetChannelTemplate(): save user: %v", err)
+		a.helperSendChannelMenu(m, l, user, l.T("msg.error"))
+		return
+	}
+	a.helperSendChannelMenu(m, l, user, l.T("msg.channel.template.saved"))
+}
+
+// botHandleChannelAutoPostCallback turns the auto-post mode of the channel on or off.
+//
+//	The updates received before the mode is turned on are marked read, so only the new ones are posted.
+func (a *App) botHandleChannelAutoPostCallback(ctx context.Context, cb *telebot.Callback) {
+	user := ctx.Value(BotCtxUser).(*model.Subscriber)
+	l := a.botLocalizer(user)
+
+	if !user.IsChannel() {
+		_ = a.Bot.Respond(cb)
+		return
+	}
+	if !user.AutoPost {
+		if _, err := a.SubscriptionModel.MarkRead(ctx, user, nil); err != nil {
+			log.Printf("[bot] botHandleChannelAutoPostCallback(): mark read: %v", err)
+			_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+			return
+		}
+	}
+	user.AutoPost = !user.AutoPost
+	if err := a.SubscriberModel.Save(ctx, user); err != nil {
+		log.Printf("[bot] botHandleChannelAutoPostCallback(): save user: %v", err)
+		_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: l.T("msg.error"), ShowAlert: true})
+		return
+	}
+	a.helperEditChannelMenu(cb, l, user, "")
+	_ = a.Bot.Respond(cb)
+}
+
+// helperSendChannelMenu posts the settings of the channel preceded by the note if it is not empty.
+func (a *App) helperSendChannelMenu(m *telebot.Message, l *i18n.Localizer, user *model.Subscriber, note string) {
+	if _, err := a.Bot.Send(
+		m.Chat,
+		formatBotChannelMessage(l, user, note),
+		NewBotMenuChannel(l, user).Menu,
+	); err != nil {
+		log.Printf("[bot] helperSendChannelMenu(): Failed to reply: %v", err)
+	}
+}
+
+// helperEditChannelMenu shows the settings of the channel in place of the menu preceded by the note if it is not empty.
+func (a *App) helperEditChannelMenu(cb *telebot.Callback, l *i18n.Localizer, user *model.Subscriber, note string) {
+	if _, err := a.Bot.Edit(
+		cb.Message,
+		formatBotChannelMessage(l, user, note),
+		NewBotMenuChannel(l, user).Menu,
+	); err != nil && !strings.Contains(err.Error(), "new message content and reply markup are exactly the same") {
+		log.Printf("[bot] helperEditChannelMenu(): Failed to edit message: %v", err)
+	}
+}
+
+// formatBotChannelMessage describes the settings of the channel, the template is shown as is.
+func formatBotChannelMessage(l *i18n.Localizer, user *model.Subscriber, note string) string {
+	status := l.T("msg.channel.autopost.off")
+	if user.AutoPost {
+		status = l.T("msg.channel.autopost.on")
+	}
+	template := user.Template
+	if len(template) == 0 {
+		template = l.T("msg.channel.template.default")
+	}
+	msg := l.T("msg.channel.settings", status, template)
+	if len(note) > 0 {
+		msg = note + "\n\n" + msg
+	}
+	return msg
+}
+
+// botHandleMigration moves the subscriber of a group upgraded to a supergroup to the ID of the supergroup.
+func (a *App) botHandleMigration(ctx context.Context, from, to int64) {
+	user, err := a.SubscriberModel.Get(ctx, fmt.Sprintf("telegram:%d", from))
+	if err == model.ErrNotFound {
+		return
+	}
+	if err != nil {
+		log.Printf("[bot] botHandleMigration(): get user: %v", err)
+		return
+	}
+	// the supergroup could already have a subscriber, e.g. created by the service message of the migration,
+	// its subscriptions are merged into the moved one which replaces it
+	existing, err := a.SubscriberModel.Get(ctx, fmt.Sprintf("telegram:%d", to))
+	if err != nil && err != model.ErrNotFound {
+		log.Printf("[bot] botHandleMigration(): get user of the new chat: %v", err)
+		return
+	}
+	if err == nil {
+		mergeBotSubscriber(user, existing)
+		if err := a.SubscriberModel.Delete(ctx, existing); err != nil {
+			log.Printf("[bot] botHandleMigration(): delete user of the new chat: %v", err)
+			return
+		}
+		log.Printf("[bot] Replacing user %q of chat %d with %q", existing.ID, to, user.ID)
+	}
+	user.UserID = fmt.Sprintf("telegram:%d", to)
+	if err := a.SubscriberModel.Save(ctx, user); err != nil {
+		log.Printf("[bot] botHandleMigration(): save user: %v", err)
+		return
+	}
+	log.Printf("[bot] Moved user %q from chat %d to %d", user.ID, from, to)
+}
+
+// mergeBotSubscriber adds the subscriptions of the other subscriber to the subscriber.
+func mergeBotSubscriber(s, other *model.Subscriber) {
+	for _, cat := range other.Categories {
+		if !s.HasCategory(cat) {
+			s.AddCategory(cat)
+		}
+	}
+	for _, id := range other.Feeds {
+		s.AddFeed(model.Feed{ID: id})
+	}
 }
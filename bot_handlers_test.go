@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/i18n"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestLastPageOffset(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Total int
+		Want  int
+	}{
+		{Name: "Empty", Total: 0, Want: 0},
+		{Name: "SinglePage", Total: 5, Want: 0},
+		{Name: "PartialPage", Total: 7, Want: 5},
+		{Name: "FullPages", Total: 10, Want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := lastPageOffset(tt.Total, 5); got != tt.Want {
+				t.Errorf("lastPageOffset(%d): got %d; want %d", tt.Total, got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestNewBotMenuUpdatesPage(t *testing.T) {
+	l := i18n.NewBundle(i18n.DefaultLanguage).Localizer(i18n.DefaultLanguage)
+	// the IDs are as long as the ones generated by the DB
+	cat := &model.Category{ID: "cat4567890123456789X"}
+	ups := make([]model.Update, 10)
+	for i := range ups {
+		ups[i].Date = time.Now()
+	}
+	m := NewBotMenuUpdatesPage(l, cat, ups, 100, 200, 10)
+	if open := m.Menu.InlineKeyboard[:2]; len(open[0]) != BotMenuUpdatesPageRowSize || len(open[1]) != BotMenuUpdatesPageRowSize {
+		t.Errorf("NewBotMenuUpdatesPage(): got rows %v; want the open buttons split in rows of %d", open, BotMenuUpdatesPageRowSize)
+	}
+	for _, row := range m.Menu.InlineKeyboard {
+		if len(row) > 8 {
+			t.Errorf("NewBotMenuUpdatesPage(): got %d buttons in a row; want up to 8", len(row))
+		}
+		for _, btn := range row {
+			if len(btn.Data) > 64 {
+				t.Errorf("NewBotMenuUpdatesPage(): got %d bytes of callback data %q; want up to 64", len(btn.Data), btn.Data)
+			}
+		}
+	}
+}
+
+func TestFormatBotSelectCategoriesMessage(t *testing.T) {
+	bundle, err := loadLocales("")
+	if err != nil {
+		t.Fatalf("loadLocales(): %v", err)
+	}
+	l := bundle.Localizer(i18n.DefaultLanguage)
+	subs := []model.Subscription{
+		{Category: model.Category{ID: "dev", Name: "dev_ops *", Description: "CI [beta] `tools`"}},
+		{Category: model.Category{ID: "go", ParentID: "dev", Name: "Go_lang", Description: "go_dev"}},
+	}
+	tests := []struct {
+		Name   string
+		Parent *model.Subscription
+		Want   string
+	}{
+		{Name: "TopLevel", Want: "Select categories for which you would like to receive updates:\n*dev\\_ops \\** — CI \\[beta] \\`tools\\`"},
+		{Name: "Nested", Parent: &subs[0], Want: "*dev\\_ops \\** — subscribe to the whole category or only to the subcategories you like:\nCI \\[beta] \\`tools\\`\n*Go\\_lang* — go\\_dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := formatBotSelectCategoriesMessage(l, subs, tt.Parent); got != tt.Want {
+				t.Errorf("formatBotSelectCategoriesMessage(): got %q; want %q", got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestFormatBotFilterMessage(t *testing.T) {
+	bundle, err := loadLocales("")
+	if err != nil {
+		t.Fatalf("loadLocales(): %v", err)
+	}
+	l := bundle.Localizer(i18n.DefaultLanguage)
+	cat := &model.Category{ID: "dev", Name: "dev_ops"}
+	filter := model.Filter{Include: []string{"go_lang", "/^[a-z]+`/"}, Exclude: []string{"*ads*"}}
+	want := "Filter of category *dev\\_ops*:\nInclude: go\\_lang, /^\\[a-z]+\\`/\nExclude: \\*ads\\*\n\n" + l.T("msg.filter.help")
+	if got := formatBotFilterMessage(l, cat, filter); got != want {
+		t.Errorf("formatBotFilterMessage(): got %q; want %q", got, want)
+	}
+}
+
+func TestApp_botHandleMigration(t *testing.T) {
+	subscribers := &testSubscriberModel{subscribers: []model.Subscriber{
+		{ID: "old", UserID: "telegram:-1", Chat: model.ChatGroup, Categories: []model.Category{{ID: "world"}}, Feeds: []string{"f1"}},
+		{ID: "new", UserID: "telegram:-100", Chat: model.ChatGroup, Categories: []model.Category{{ID: "world"}, {ID: "sport"}}, Feeds: []string{"f2"}},
+	}}
+	a := &App{SubscriberModel: subscribers}
+	a.botHandleMigration(context.Background(), -1, -100)
+	if len(subscribers.subscribers) != 1 {
+		t.Fatalf("botHandleMigration(): got subscribers %+v; want the one of the new chat replaced", subscribers.subscribers)
+	}
+	s := subscribers.subscribers[0]
+	if s.ID != "old" || s.UserID != "telegram:-100" {
+		t.Errorf("botHandleMigration(): got %q of %q; want old moved to telegram:-100", s.ID, s.UserID)
+	}
+	if len(s.Categories) != 2 || len(s.Feeds) != 2 {
+		t.Errorf("botHandleMigration(): got categories %v, feeds %v; want the subscriptions merged", s.Categories, s.Feeds)
+	}
+}
+
+func TestParseBotPageData(t *testing.T) {
+	if id, offset := parseBotPageData("cat|10"); id != "cat" || offset != 10 {
+		t.Errorf("parseBotPageData(): got %q, %d; want cat, 10", id, offset)
+	}
+	if id, offset := parseBotPageData("cat"); id != "cat" || offset != 0 {
+		t.Errorf("parseBotPageData(): got %q, %d; want cat, 0", id, offset)
+	}
+}
+
+func TestParseBotPageRangeData(t *testing.T) {
+	from := time.Date(2000, 1, 1, 10, 0, 0, 123456000, time.UTC)
+	to := time.Date(2030, 12, 31, 23, 59, 59, 999999000, time.UTC)
+	data := "cat|10|" + formatBotPageDate(from) + "|" + formatBotPageDate(to)
+	id, offset, gotFrom, gotTo, ok := parseBotPageRangeData(data)
+	if !ok || id != "cat" || offset != 10 || !gotFrom.Equal(from) || !gotTo.Equal(to) {
+		t.Errorf("parseBotPageRangeData(%q): got %q, %d, %v, %v, %v; want cat, 10, %v, %v", data, id, offset, gotFrom, gotTo, ok, from, to)
+	}
+	if id, offset, _, _, ok := parseBotPageRangeData("cat|10"); ok || id != "cat" || offset != 10 {
+		t.Errorf("parseBotPageRangeData(): got %q, %d, %v; want cat, 10 without the dates", id, offset, ok)
+	}
+}
+
+func TestSplitBotCommandArg(t *testing.T) {
+	if arg, rest := splitBotCommandArg(" all  Hello,\nworld "); arg != "all" || rest != "Hello,\nworld" {
+		t.Errorf("splitBotCommandArg(): got %q, %q; want all, \"Hello,\\nworld\"", arg, rest)
+	}
+	if arg, rest := splitBotCommandArg("all"); arg != "all" || rest != "" {
+		t.Errorf("splitBotCommandArg(): got %q, %q; want all, \"\"", arg, rest)
+	}
+}
+
+func TestFindBotUpdatesLevel(t *testing.T) {
+	subs := []model.Subscription{
+		{Category: model.Category{ID: "world"}, Subscribed: true},
+		{Category: model.Category{ID: "europe", ParentID: "world"}, Inherited: true},
+		{Category: model.Category{ID: "france", ParentID: "europe"}, Inherited: true},
+		{Category: model.Category{ID: "sport"}},
+		{Category: model.Category{ID: "football", ParentID: "sport"}},
+	}
+	tests := []struct {
+		Name  string
+		CatID string
+		Want  string
+	}{
+		{Name: "TopLevel", CatID: "", Want: ""},
+		{Name: "Parent", CatID: "europe", Want: "europe"},
+		{Name: "Leaf", CatID: "france", Want: "europe"},
+		{Name: "NotReceived", CatID: "football", Want: ""},
+		{Name: "Unknown", CatID: "nothing", Want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			got := ""
+			if sub := findBotUpdatesLevel(subs, tt.CatID); sub != nil {
+				got = sub.Category.ID
+			}
+			if got != tt.Want {
+				t.Errorf("findBotUpdatesLevel(%q): got %q; want %q", tt.CatID, got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestApp_botIsAdmin(t *testing.T) {
+	a := &App{Config: Config{BotAdmins: []string{"42"}}}
+	tests := []struct {
+		Name string
+		User *model.Subscriber
+		Want bool
+	}{
+		{Name: "Admin", User: model.NewSubscriber("telegram:42"), Want: true},
+		{Name: "User", User: model.NewSubscriber("telegram:7"), Want: false},
+		{Name: "OtherService", User: model.NewSubscriber("42"), Want: false},
+		{Name: "Nil", User: nil, Want: false},
+		{Name: "Group", User: &model.Subscriber{UserID: "telegram:42", Chat: model.ChatGroup}, Want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := a.botIsAdmin(tt.User); got != tt.Want {
+				t.Errorf("botIsAdmin(): got %v; want %v", got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestApp_adminStats(t *testing.T) {
+	now := time.Date(2000, 1, 31, 0, 0, 0, 0, time.UTC)
+	long := now.AddDate(0, -1, 0)
+	world, my := &model.Category{ID: "world"}, &model.Category{ID: "my", Personal: true}
+	a := &App{
+		SubscriberModel: &testSubscriberModel{subscribers: []model.Subscriber{
+			{Created: now.Add(-time.Hour), LastSeen: now.Add(-time.Hour)},
+			{Created: long, LastSeen: now.Add(-time.Hour)},
+			{Created: long, LastSeen: long, Inactive: true},
+		}},
+		CategoryModel:     &testCategoryModel{cats: map[string]*model.Category{"world": world, "my": my}},
+		FeedModel:         &testFeedModel{feeds: []model.Feed{{Category: world}, {Category: world}, {Category: my}}},
+		SubscriptionModel: &testSubscriptionModel{unread: 7},
+	}
+	st, err := a.adminStats(context.Background(), now)
+	if err != nil {
+		t.Fatalf("adminStats(): %v", err)
+	}
+	want := botAdminStats{Subscribers: 3, Active: 2, Joined: 1, Seen: 2, Categories: 1, Feeds: 2, CustomFeeds: 1, Unread: 7}
+	if st != want {
+		t.Errorf("adminStats(): got %+v; want %+v", st, want)
+	}
+}
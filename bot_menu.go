@@ -2,16 +2,28 @@ package main
 
 import (
 	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/i18n"
 	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/d-ashesss/news-feed-bot/pkg/search"
 	"gopkg.in/tucnak/telebot.v2"
+	"strconv"
 )
 
 const (
-	BotMenuMainBtnCheckUpdatesLabel = "Check for updates"
+	BotMenuMainBtnCheckUpdatesLabel = "menu.main.check_updates"
 	BotMenuMainBtnCheckUpdatesID    = "btnMenuMainCheckUpdates"
 
-	BotMenuMainBtnSelectCategoriesLabel = "Select categories"
+	BotMenuMainBtnSelectCategoriesLabel = "menu.main.select_categories"
 	BotMenuMainBtnSelectCategoriesID    = "btnMenuMainSelectCategories"
+
+	BotMenuMainBtnFiltersLabel = "menu.main.filters"
+	BotMenuMainBtnFiltersID    = "btnMenuMainFilters"
+
+	BotMenuMainBtnAlertsLabel = "menu.main.alerts"
+	BotMenuMainBtnAlertsID    = "btnMenuMainAlerts"
+
+	BotMenuMainBtnCustomFeedsLabel = "menu.main.custom_feeds"
+	BotMenuMainBtnCustomFeedsID    = "btnMenuMainCustomFeeds"
 )
 
 type BotMenuMain struct {
@@ -19,23 +31,64 @@ type BotMenuMain struct {
 
 	BtnCheckUpdates     telebot.Btn
 	BtnSelectCategories telebot.Btn
+	BtnFilters          telebot.Btn
+	BtnAlerts           telebot.Btn
+	BtnCustomFeeds      telebot.Btn
 }
 
-func NewBotMenuMain() *BotMenuMain {
+func NewBotMenuMain(l *i18n.Localizer) *BotMenuMain {
 	m := &BotMenuMain{
 		Menu: &telebot.ReplyMarkup{},
 	}
-	m.BtnCheckUpdates = m.Menu.Data(BotMenuMainBtnCheckUpdatesLabel, BotMenuMainBtnCheckUpdatesID)
-	m.BtnSelectCategories = m.Menu.Data(BotMenuMainBtnSelectCategoriesLabel, BotMenuMainBtnSelectCategoriesID)
+	m.BtnCheckUpdates = m.Menu.Data(l.T(BotMenuMainBtnCheckUpdatesLabel), BotMenuMainBtnCheckUpdatesID)
+	m.BtnSelectCategories = m.Menu.Data(l.T(BotMenuMainBtnSelectCategoriesLabel), BotMenuMainBtnSelectCategoriesID)
+	m.BtnFilters = m.Menu.Data(l.T(BotMenuMainBtnFiltersLabel), BotMenuMainBtnFiltersID)
+	m.BtnAlerts = m.Menu.Data(l.T(BotMenuMainBtnAlertsLabel), BotMenuMainBtnAlertsID)
+	m.BtnCustomFeeds = m.Menu.Data(l.T(BotMenuMainBtnCustomFeedsLabel), BotMenuMainBtnCustomFeedsID)
 	m.Menu.Inline(
 		m.Menu.Row(m.BtnCheckUpdates),
 		m.Menu.Row(m.BtnSelectCategories),
+		m.Menu.Row(m.BtnFilters),
+		m.Menu.Row(m.BtnAlerts),
+		m.Menu.Row(m.BtnCustomFeeds),
+	)
+	return m
+}
+
+const (
+	BotMenuChannelBtnAutoPostOnLabel  = "menu.channel.autopost_on"
+	BotMenuChannelBtnAutoPostOffLabel = "menu.channel.autopost_off"
+	BotMenuChannelBtnAutoPostID       = "btnMenuChannelAutoPost"
+)
+
+// BotMenuChannel represents the main menu of a channel.
+type BotMenuChannel struct {
+	Menu *telebot.ReplyMarkup
+
+	BtnSelectCategories telebot.Btn
+	BtnAutoPost         telebot.Btn
+}
+
+// NewBotMenuChannel initializes new BotMenuChannel, the auto-post button turns the mode of the channel on or off.
+func NewBotMenuChannel(l *i18n.Localizer, channel *model.Subscriber) *BotMenuChannel {
+	m := &BotMenuChannel{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	autoPostLabel := BotMenuChannelBtnAutoPostOnLabel
+	if channel.AutoPost {
+		autoPostLabel = BotMenuChannelBtnAutoPostOffLabel
+	}
+	m.BtnSelectCategories = m.Menu.Data(l.T(BotMenuMainBtnSelectCategoriesLabel), BotMenuMainBtnSelectCategoriesID)
+	m.BtnAutoPost = m.Menu.Data(l.T(autoPostLabel), BotMenuChannelBtnAutoPostID)
+	m.Menu.Inline(
+		m.Menu.Row(m.BtnSelectCategories),
+		m.Menu.Row(m.BtnAutoPost),
 	)
 	return m
 }
 
 const (
-	BotBtnBackToMainMenuLabel = "⬅️ Back to main menu"
+	BotBtnBackToMainMenuLabel = "menu.back_to_main"
 	BotBtnBackToMainMenuID    = "btnBackToMainMenu"
 )
 
@@ -45,11 +98,11 @@ type BotMenuNoUpdatesInCategory struct {
 	BtnBack telebot.Btn
 }
 
-func NewBotMenuNoUpdatesInCategory() *BotMenuNoUpdatesInCategory {
+func NewBotMenuNoUpdatesInCategory(l *i18n.Localizer) *BotMenuNoUpdatesInCategory {
 	m := &BotMenuNoUpdatesInCategory{
 		Menu: &telebot.ReplyMarkup{},
 	}
-	m.BtnBack = m.Menu.Data(BotMenuCategoryNextUpdateBtnBackLabel, BotMenuMainBtnCheckUpdatesID)
+	m.BtnBack = m.Menu.Data(l.T(BotMenuCategoryNextUpdateBtnBackLabel), BotMenuMainBtnCheckUpdatesID)
 	m.Menu.Inline(
 		m.Menu.Row(m.BtnBack),
 	)
@@ -62,12 +115,12 @@ type BotMenuNoCategoriesSelected struct {
 	BtnSelectCategories telebot.Btn
 }
 
-func NewBotMenuNoCategoriesSelected() *BotMenuNoCategoriesSelected {
+func NewBotMenuNoCategoriesSelected(l *i18n.Localizer) *BotMenuNoCategoriesSelected {
 	m := &BotMenuNoCategoriesSelected{
 		Menu: &telebot.ReplyMarkup{},
 	}
-	m.BtnSelectCategories = m.Menu.Data(BotMenuMainBtnSelectCategoriesLabel, BotMenuMainBtnSelectCategoriesID)
-	backBtn := m.Menu.Data(BotBtnBackToMainMenuLabel, BotBtnBackToMainMenuID)
+	m.BtnSelectCategories = m.Menu.Data(l.T(BotMenuMainBtnSelectCategoriesLabel), BotMenuMainBtnSelectCategoriesID)
+	backBtn := m.Menu.Data(l.T(BotBtnBackToMainMenuLabel), BotBtnBackToMainMenuID)
 	m.Menu.Inline(
 		m.Menu.Row(m.BtnSelectCategories),
 		m.Menu.Row(backBtn),
@@ -82,77 +135,700 @@ type BotMenuCategoryUpdates struct {
 const (
 	BotMenuCategoryUpdatesBtnCategoryUpdatesID = "btnMenuCategoryUpdates"
 
-	BotMenuCategoryUpdatesBtnRefreshLabel = "🔄 Refresh"
+	BotMenuCategoryUpdatesBtnRefreshLabel = "menu.updates.refresh"
+
+	BotMenuCategoryUpdatesBtnListLabel = "📋"
+	BotMenuCategoryUpdatesBtnListID    = "btnMenuCategoryUpdatesList"
+
+	BotMenuCategoryUpdatesBtnMarkReadLabel    = "✔️"
+	BotMenuCategoryUpdatesBtnMarkReadID       = "btnMenuCategoryUpdatesMarkRead"
+	BotMenuCategoryUpdatesBtnMarkAllReadLabel = "menu.updates.mark_all_read"
+	BotMenuCategoryUpdatesBtnMarkAllReadID    = "btnMenuCategoryUpdatesMarkAllRead"
 )
 
-func NewBotMenuCategoryUpdates(subs []model.Subscription) *BotMenuCategoryUpdates {
+// NewBotMenuCategoryUpdates initializes new BotMenuCategoryUpdates.
+//
+//	Lists the received categories nested in the parent, or the top level ones if parent is nil,
+//	subs is the subscription status of all the categories. Categories with received subcategories open
+//	their own level of the menu, the parent itself is listed first with its own unread updates.
+//	The categories are shown by pages, page is the number of the page to show.
+func NewBotMenuCategoryUpdates(l *i18n.Localizer, subs []model.Subscription, parent *model.Subscription, page int) *BotMenuCategoryUpdates {
 	m := &BotMenuCategoryUpdates{
 		Menu: &telebot.ReplyMarkup{},
 	}
-	rows := make([]telebot.Row, 0, len(subs)+1)
-	for _, sub := range subs {
-		label := fmt.Sprintf("%s (%d)", sub.Category.Name, sub.Unread)
-		btn := m.Menu.Data(label, BotMenuCategoryUpdatesBtnCategoryUpdatesID, sub.Category.ID)
-		rows = append(rows, m.Menu.Row(btn))
+	parentID := ""
+	if parent != nil {
+		parentID = parent.Category.ID
+	}
+	level := model.ReceivedSubscriptions(subs, parentID)
+	items := make([]telebot.Row, 0, len(level))
+	unread := 0
+	for _, sub := range level {
+		if len(model.ReceivedSubscriptions(subs, sub.Category.ID)) > 0 {
+			label := fmt.Sprintf("%s (%d) ›", sub.Category.Label(l.Lang), sub.Unread)
+			btn := m.Menu.Data(label, BotMenuMainBtnCheckUpdatesID, sub.Category.ID, "0")
+			items = append(items, m.Menu.Row(btn))
+		} else {
+			items = append(items, m.categoryRow(l, sub.Category, sub.Unread))
+		}
+		unread += sub.Unread
+	}
+	pager := NewBotPager(BotMenuMainBtnCheckUpdatesID, parentID)
+	page = pager.Clamp(len(items), page)
+	rows := make([]telebot.Row, 0, BotPagerDefaultSize+4)
+	if parent != nil && (parent.Receives() || parent.OwnUnread > 0) {
+		rows = append(rows, m.categoryRow(l, parent.Category, parent.OwnUnread))
+	}
+	rows = append(rows, pager.Page(l, m.Menu, items, page)...)
+	if parent == nil && unread > 0 {
+		markAllReadBtn := m.Menu.Data(l.T(BotMenuCategoryUpdatesBtnMarkAllReadLabel), BotMenuCategoryUpdatesBtnMarkAllReadID)
+		rows = append(rows, m.Menu.Row(markAllReadBtn))
 	}
-	backBtn := m.Menu.Data(BotBtnBackToMainMenuLabel, BotBtnBackToMainMenuID)
-	refreshBtn := m.Menu.Data(BotMenuCategoryUpdatesBtnRefreshLabel, BotMenuMainBtnCheckUpdatesID)
+	backBtn := m.Menu.Data(l.T(BotBtnBackToMainMenuLabel), BotBtnBackToMainMenuID)
+	if parent != nil {
+		backBtn = m.Menu.Data(l.T(BotMenuCategoryNextUpdateBtnBackLabel), BotMenuMainBtnCheckUpdatesID, parent.Category.ParentID, "0")
+	}
+	refreshBtn := m.Menu.Data(l.T(BotMenuCategoryUpdatesBtnRefreshLabel), BotMenuMainBtnCheckUpdatesID, parentID, strconv.Itoa(page))
 	rows = append(rows, m.Menu.Row(backBtn, refreshBtn))
 	m.Menu.Inline(rows...)
 	return m
 }
 
+// categoryRow makes a row of buttons to read the unread updates of the category.
+func (m *BotMenuCategoryUpdates) categoryRow(l *i18n.Localizer, cat model.Category, unread int) telebot.Row {
+	label := fmt.Sprintf("%s (%d)", cat.Label(l.Lang), unread)
+	btn := m.Menu.Data(label, BotMenuCategoryUpdatesBtnCategoryUpdatesID, cat.ID)
+	if unread == 0 {
+		return m.Menu.Row(btn)
+	}
+	listBtn := m.Menu.Data(BotMenuCategoryUpdatesBtnListLabel, BotMenuCategoryUpdatesBtnListID, cat.ID, "0")
+	markReadBtn := m.Menu.Data(BotMenuCategoryUpdatesBtnMarkReadLabel, BotMenuCategoryUpdatesBtnMarkReadID, cat.ID)
+	return m.Menu.Row(btn, listBtn, markReadBtn)
+}
+
 type BotMenuCategoryNextUpdate struct {
 	Menu *telebot.ReplyMarkup
 
-	BtnBack telebot.Btn
-	BtnNext telebot.Btn
+	BtnBack     telebot.Btn
+	BtnPrevious telebot.Btn
+	BtnNext     telebot.Btn
 }
 
 const (
-	BotMenuCategoryNextUpdateBtnBackLabel = "⬅️ Back to categories️"
-	BotMenuCategoryNextUpdateBtnNextLabel = "Next ➡️"
+	BotMenuCategoryNextUpdateBtnBackLabel     = "menu.back_to_categories"
+	BotMenuCategoryNextUpdateBtnPreviousLabel = "menu.previous"
+	BotMenuCategoryNextUpdateBtnPreviousID    = "btnMenuCategoryPreviousUpdate"
+	BotMenuCategoryNextUpdateBtnNextLabel     = "menu.next"
 )
 
-func NewBotMenuCategoryNextUpdate(cat *model.Category) *BotMenuCategoryNextUpdate {
+// NewBotMenuCategoryNextUpdate initializes new BotMenuCategoryNextUpdate.
+//
+//	prev is the position in the reading history of the category to show on Previous,
+//	Next is only shown if there are unread updates left.
+func NewBotMenuCategoryNextUpdate(l *i18n.Localizer, cat *model.Category, prev int, next bool) *BotMenuCategoryNextUpdate {
 	m := &BotMenuCategoryNextUpdate{
 		Menu: &telebot.ReplyMarkup{},
 	}
-	m.BtnBack = m.Menu.Data(BotMenuCategoryNextUpdateBtnBackLabel, BotMenuMainBtnCheckUpdatesID, cat.ID)
-	m.BtnNext = m.Menu.Data(BotMenuCategoryNextUpdateBtnNextLabel, BotMenuCategoryUpdatesBtnCategoryUpdatesID, cat.ID)
-	m.Menu.Inline(m.Menu.Row(m.BtnBack, m.BtnNext))
+	m.BtnBack = m.Menu.Data(l.T(BotMenuCategoryNextUpdateBtnBackLabel), BotMenuMainBtnCheckUpdatesID, cat.ID)
+	m.BtnPrevious = m.Menu.Data(l.T(BotMenuCategoryNextUpdateBtnPreviousLabel), BotMenuCategoryNextUpdateBtnPreviousID, cat.ID, strconv.Itoa(prev))
+	m.BtnNext = m.Menu.Data(l.T(BotMenuCategoryNextUpdateBtnNextLabel), BotMenuCategoryUpdatesBtnCategoryUpdatesID, cat.ID)
+	if next {
+		m.Menu.Inline(m.Menu.Row(m.BtnPrevious, m.BtnNext), m.Menu.Row(m.BtnBack))
+	} else {
+		m.Menu.Inline(m.Menu.Row(m.BtnPrevious), m.Menu.Row(m.BtnBack))
+	}
 	return m
 }
 
-const BotMenuSelectCategoriesBtnToggleCategoryID = "btnMenuToggleCategory"
+const (
+	BotMenuSelectCategoriesBtnToggleCategoryID = "btnMenuToggleCategory"
+	BotMenuSelectCategoriesBtnFeedsID          = "btnMenuCategoryFeeds"
+	BotMenuSelectCategoriesBtnFeedsText        = "📰"
+
+	BotMenuSelectCategoriesSubscribedMark = "✅ "
+	BotMenuSelectCategoriesInheritedMark  = "☑️ "
+)
 
 type BotMenuSelectCategories struct {
 	Menu *telebot.ReplyMarkup
 }
 
-func NewBotMenuSelectCategories(subs []model.Subscription) *BotMenuSelectCategories {
+// NewBotMenuSelectCategories initializes new BotMenuSelectCategories.
+//
+//	Lists the categories nested in the parent, or the top level ones if parent is nil,
+//	subs is the subscription status of all the categories. Categories with subcategories open
+//	their own level of the menu, the parent itself is listed first to subscribe to it as a whole.
+//	Each category is followed by the button to choose from its feeds.
+//	The categories are shown by pages, page is the number of the page to show.
+func NewBotMenuSelectCategories(l *i18n.Localizer, subs []model.Subscription, parent *model.Subscription, page int) *BotMenuSelectCategories {
 	m := &BotMenuSelectCategories{
 		Menu: &telebot.ReplyMarkup{},
 	}
-	rows := make([]telebot.Row, 0, len(subs)+1)
+	parentID := ""
+	if parent != nil {
+		parentID = parent.Category.ID
+	}
+	level := model.Subscriptions(subs, parentID)
+	pager := NewBotPager(BotMenuMainBtnSelectCategoriesID, parentID)
+	page = pager.Clamp(len(level), page)
+	items := make([]telebot.Row, 0, len(level))
+	for _, sub := range level {
+		var btn telebot.Btn
+		if sub.Subcategories > 0 {
+			btn = m.Menu.Data(selectCategoryLabel(l, sub)+" ›", BotMenuMainBtnSelectCategoriesID, sub.Category.ID, "0")
+		} else {
+			btn = m.Menu.Data(selectCategoryLabel(l, sub), BotMenuSelectCategoriesBtnToggleCategoryID, sub.Category.ID, strconv.Itoa(page))
+		}
+		items = append(items, m.Menu.Row(btn, m.categoryFeedsBtn(sub.Category)))
+	}
+	rows := make([]telebot.Row, 0, BotPagerDefaultSize+3)
+	if parent != nil {
+		btn := m.Menu.Data(selectCategoryLabel(l, *parent), BotMenuSelectCategoriesBtnToggleCategoryID, parentID, strconv.Itoa(page))
+		rows = append(rows, m.Menu.Row(btn, m.categoryFeedsBtn(parent.Category)))
+	}
+	rows = append(rows, pager.Page(l, m.Menu, items, page)...)
+	backBtn := m.Menu.Data(l.T(BotBtnBackToMainMenuLabel), BotBtnBackToMainMenuID)
+	if parent != nil {
+		backBtn = m.Menu.Data(l.T(BotMenuCategoryNextUpdateBtnBackLabel), BotMenuMainBtnSelectCategoriesID, parent.Category.ParentID, "0")
+	}
+	rows = append(rows, m.Menu.Row(backBtn))
+	m.Menu.Inline(rows...)
+	return m
+}
+
+// categoryFeedsBtn returns the button to choose from the feeds of the category.
+func (m *BotMenuSelectCategories) categoryFeedsBtn(cat model.Category) telebot.Btn {
+	return m.Menu.Data(BotMenuSelectCategoriesBtnFeedsText, BotMenuSelectCategoriesBtnFeedsID, cat.ID, "0")
+}
+
+// selectCategoryLabel marks the categories subscribed to directly or through a parent.
+func selectCategoryLabel(l *i18n.Localizer, sub model.Subscription) string {
+	label := sub.Category.Label(l.Lang)
+	if sub.Subscribed {
+		return BotMenuSelectCategoriesSubscribedMark + label
+	}
+	if sub.Inherited {
+		return BotMenuSelectCategoriesInheritedMark + label
+	}
+	return label
+}
+
+const (
+	BotMenuCategoryFeedsBtnToggleFeedID = "btnMenuToggleFeed"
+
+	BotMenuCategoryFeedsMutedMark = "🔇 "
+)
+
+// BotMenuCategoryFeeds represents the list of feeds of a category to subscribe to or mute.
+type BotMenuCategoryFeeds struct {
+	Menu *telebot.ReplyMarkup
+}
+
+// NewBotMenuCategoryFeeds initializes new BotMenuCategoryFeeds.
+//
+//	Lists the feeds of the category along with their subscription status.
+//	The feeds are shown by pages, page is the number of the page to show.
+func NewBotMenuCategoryFeeds(l *i18n.Localizer, cat model.Category, feeds []model.FeedSubscription, page int) *BotMenuCategoryFeeds {
+	m := &BotMenuCategoryFeeds{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	pager := NewBotPager(BotMenuSelectCategoriesBtnFeedsID, cat.ID)
+	page = pager.Clamp(len(feeds), page)
+	items := make([]telebot.Row, 0, len(feeds))
+	for _, sub := range feeds {
+		btn := m.Menu.Data(categoryFeedLabel(sub), BotMenuCategoryFeedsBtnToggleFeedID, cat.ID, sub.Feed.ID, strconv.Itoa(page))
+		items = append(items, m.Menu.Row(btn))
+	}
+	rows := pager.Page(l, m.Menu, items, page)
+	backBtn := m.Menu.Data(l.T(BotMenuCategoryNextUpdateBtnBackLabel), BotMenuMainBtnSelectCategoriesID, cat.ID, "0")
+	rows = append(rows, m.Menu.Row(backBtn))
+	m.Menu.Inline(rows...)
+	return m
+}
+
+// categoryFeedLabel marks the feeds the updates are received from and the muted ones.
+func categoryFeedLabel(sub model.FeedSubscription) string {
+	label := sub.Feed.Title
+	if len(label) == 0 {
+		label = sub.Feed.URL
+	}
+	if sub.Receives() {
+		return BotMenuSelectCategoriesSubscribedMark + label
+	}
+	if sub.Muted {
+		return BotMenuCategoryFeedsMutedMark + label
+	}
+	return label
+}
+
+const BotMenuFilterCategoriesBtnCategoryID = "btnMenuFilterCategory"
+
+// BotMenuFilterCategories represents the list of subscribed categories to set up filters for.
+type BotMenuFilterCategories struct {
+	Menu *telebot.ReplyMarkup
+}
+
+// NewBotMenuFilterCategories initializes new BotMenuFilterCategories.
+//
+//	The categories are shown by pages, page is the number of the page to show.
+func NewBotMenuFilterCategories(l *i18n.Localizer, subs []model.Subscription, user *model.Subscriber, page int) *BotMenuFilterCategories {
+	m := &BotMenuFilterCategories{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	items := make([]telebot.Row, 0, len(subs))
 	for _, sub := range subs {
-		label := sub.Category.Name
-		if sub.Subscribed {
-			label = "✅ " + label
+		label := sub.Category.Label(l.Lang)
+		if !user.GetFilter(sub.Category).IsEmpty() {
+			label = "🔍 " + label
 		}
-		btn := m.Menu.Data(label, BotMenuSelectCategoriesBtnToggleCategoryID, sub.Category.ID)
-		rows = append(rows, m.Menu.Row(btn))
+		btn := m.Menu.Data(label, BotMenuFilterCategoriesBtnCategoryID, sub.Category.ID)
+		items = append(items, m.Menu.Row(btn))
+	}
+	rows := NewBotPager(BotMenuMainBtnFiltersID).Page(l, m.Menu, items, page)
+	backBtn := m.Menu.Data(l.T(BotBtnBackToMainMenuLabel), BotBtnBackToMainMenuID)
+	rows = append(rows, m.Menu.Row(backBtn))
+	m.Menu.Inline(rows...)
+	return m
+}
+
+const (
+	BotMenuCategoryFilterBtnIncludeLabel = "menu.filter.include"
+	BotMenuCategoryFilterBtnIncludeID    = "btnMenuCategoryFilterInclude"
+	BotMenuCategoryFilterBtnExcludeLabel = "menu.filter.exclude"
+	BotMenuCategoryFilterBtnExcludeID    = "btnMenuCategoryFilterExclude"
+	BotMenuCategoryFilterBtnClearLabel   = "menu.filter.clear"
+	BotMenuCategoryFilterBtnClearID      = "btnMenuCategoryFilterClear"
+	BotMenuCategoryFilterBtnBackLabel    = "menu.back_to_categories"
+)
+
+// BotMenuCategoryFilter represents the menu to manage the filter of a single category.
+type BotMenuCategoryFilter struct {
+	Menu *telebot.ReplyMarkup
+
+	BtnInclude telebot.Btn
+	BtnExclude telebot.Btn
+	BtnClear   telebot.Btn
+	BtnBack    telebot.Btn
+}
+
+// NewBotMenuCategoryFilter initializes new BotMenuCategoryFilter.
+func NewBotMenuCategoryFilter(l *i18n.Localizer, cat *model.Category) *BotMenuCategoryFilter {
+	m := &BotMenuCategoryFilter{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	m.BtnInclude = m.Menu.Data(l.T(BotMenuCategoryFilterBtnIncludeLabel), BotMenuCategoryFilterBtnIncludeID, cat.ID)
+	m.BtnExclude = m.Menu.Data(l.T(BotMenuCategoryFilterBtnExcludeLabel), BotMenuCategoryFilterBtnExcludeID, cat.ID)
+	m.BtnClear = m.Menu.Data(l.T(BotMenuCategoryFilterBtnClearLabel), BotMenuCategoryFilterBtnClearID, cat.ID)
+	m.BtnBack = m.Menu.Data(l.T(BotMenuCategoryFilterBtnBackLabel), BotMenuMainBtnFiltersID)
+	m.Menu.Inline(
+		m.Menu.Row(m.BtnInclude, m.BtnExclude),
+		m.Menu.Row(m.BtnBack, m.BtnClear),
+	)
+	return m
+}
+
+// BotMenuCategoryFilterInput represents the menu shown while waiting for a filter pattern.
+type BotMenuCategoryFilterInput struct {
+	Menu *telebot.ReplyMarkup
+
+	BtnCancel telebot.Btn
+}
+
+// NewBotMenuCategoryFilterInput initializes new BotMenuCategoryFilterInput.
+func NewBotMenuCategoryFilterInput(l *i18n.Localizer, cat *model.Category) *BotMenuCategoryFilterInput {
+	m := &BotMenuCategoryFilterInput{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	m.BtnCancel = m.Menu.Data(l.T(BotMenuDeleteBtnCancelLabel), BotMenuFilterCategoriesBtnCategoryID, cat.ID)
+	m.Menu.Inline(m.Menu.Row(m.BtnCancel))
+	return m
+}
+
+const (
+	BotMenuAlertsBtnRemoveID = "btnMenuAlertsRemove"
+	BotMenuAlertsBtnAddLabel = "menu.alerts.add"
+	BotMenuAlertsBtnAddID    = "btnMenuAlertsAdd"
+)
+
+// BotMenuAlerts represents the list of user's alerts.
+type BotMenuAlerts struct {
+	Menu *telebot.ReplyMarkup
+}
+
+// NewBotMenuAlerts initializes new BotMenuAlerts.
+//
+//	The alerts are shown by pages, page is the number of the page to show.
+func NewBotMenuAlerts(l *i18n.Localizer, alerts []model.Alert, page int) *BotMenuAlerts {
+	m := &BotMenuAlerts{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	pager := NewBotPager(BotMenuMainBtnAlertsID)
+	page = pager.Clamp(len(alerts), page)
+	items := make([]telebot.Row, 0, len(alerts))
+	for _, alert := range alerts {
+		btn := m.Menu.Data("❌ "+alert.Phrase, BotMenuAlertsBtnRemoveID, alert.ID, strconv.Itoa(page))
+		items = append(items, m.Menu.Row(btn))
+	}
+	rows := pager.Page(l, m.Menu, items, page)
+	if len(alerts) < model.MaxAlerts {
+		rows = append(rows, m.Menu.Row(m.Menu.Data(l.T(BotMenuAlertsBtnAddLabel), BotMenuAlertsBtnAddID)))
+	}
+	backBtn := m.Menu.Data(l.T(BotBtnBackToMainMenuLabel), BotBtnBackToMainMenuID)
+	rows = append(rows, m.Menu.Row(backBtn))
+	m.Menu.Inline(rows...)
+	return m
+}
+
+// BotMenuAlertInput represents the menu shown while waiting for an alert phrase.
+type BotMenuAlertInput struct {
+	Menu *telebot.ReplyMarkup
+
+	BtnCancel telebot.Btn
+}
+
+// NewBotMenuAlertInput initializes new BotMenuAlertInput.
+func NewBotMenuAlertInput(l *i18n.Localizer) *BotMenuAlertInput {
+	m := &BotMenuAlertInput{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	m.BtnCancel = m.Menu.Data(l.T(BotMenuDeleteBtnCancelLabel), BotMenuMainBtnAlertsID)
+	m.Menu.Inline(m.Menu.Row(m.BtnCancel))
+	return m
+}
+
+const (
+	BotMenuCustomFeedsBtnRemoveID = "btnMenuCustomFeedsRemove"
+	BotMenuCustomFeedsBtnAddLabel = "menu.custom_feeds.add"
+	BotMenuCustomFeedsBtnAddID    = "btnMenuCustomFeedsAdd"
+)
+
+// BotMenuCustomFeeds represents the list of the feeds added by user.
+type BotMenuCustomFeeds struct {
+	Menu *telebot.ReplyMarkup
+}
+
+// NewBotMenuCustomFeeds initializes new BotMenuCustomFeeds.
+//
+//	The feeds are shown by pages, page is the number of the page to show.
+func NewBotMenuCustomFeeds(l *i18n.Localizer, feeds []model.Feed, page int) *BotMenuCustomFeeds {
+	m := &BotMenuCustomFeeds{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	pager := NewBotPager(BotMenuMainBtnCustomFeedsID)
+	page = pager.Clamp(len(feeds), page)
+	items := make([]telebot.Row, 0, len(feeds))
+	for _, f := range feeds {
+		btn := m.Menu.Data("❌ "+categoryFeedLabel(model.FeedSubscription{Feed: f}), BotMenuCustomFeedsBtnRemoveID, f.ID, strconv.Itoa(page))
+		items = append(items, m.Menu.Row(btn))
+	}
+	rows := pager.Page(l, m.Menu, items, page)
+	if len(feeds) < model.MaxCustomFeeds {
+		rows = append(rows, m.Menu.Row(m.Menu.Data(l.T(BotMenuCustomFeedsBtnAddLabel), BotMenuCustomFeedsBtnAddID)))
 	}
-	backBtn := m.Menu.Data(BotBtnBackToMainMenuLabel, BotBtnBackToMainMenuID)
+	backBtn := m.Menu.Data(l.T(BotBtnBackToMainMenuLabel), BotBtnBackToMainMenuID)
 	rows = append(rows, m.Menu.Row(backBtn))
 	m.Menu.Inline(rows...)
 	return m
 }
 
+// BotMenuCustomFeedInput represents the menu shown while waiting for a link to a feed.
+type BotMenuCustomFeedInput struct {
+	Menu *telebot.ReplyMarkup
+
+	BtnCancel telebot.Btn
+}
+
+// NewBotMenuCustomFeedInput initializes new BotMenuCustomFeedInput.
+func NewBotMenuCustomFeedInput(l *i18n.Localizer) *BotMenuCustomFeedInput {
+	m := &BotMenuCustomFeedInput{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	m.BtnCancel = m.Menu.Data(l.T(BotMenuDeleteBtnCancelLabel), BotMenuMainBtnCustomFeedsID)
+	m.Menu.Inline(m.Menu.Row(m.BtnCancel))
+	return m
+}
+
 const (
-	BotMenuDeleteBtnConfirmLabel = "✔️ Confirm"
+	BotMenuSuggestionBtnApproveLabel = "menu.suggestion.approve"
+	BotMenuSuggestionBtnApproveID    = "btnSuggestApprove"
+	BotMenuSuggestionBtnRejectLabel  = "menu.suggestion.reject"
+	BotMenuSuggestionBtnRejectID     = "btnSuggestReject"
+	BotMenuSuggestionBtnCategoryID   = "btnSuggestCat"
+	BotMenuSuggestionBtnBackID       = "btnSuggestBack"
+)
+
+// BotMenuSuggestion represents the moderation buttons of a feed suggested by user.
+type BotMenuSuggestion struct {
+	Menu *telebot.ReplyMarkup
+
+	BtnApprove telebot.Btn
+	BtnReject  telebot.Btn
+}
+
+// NewBotMenuSuggestion initializes new BotMenuSuggestion.
+func NewBotMenuSuggestion(l *i18n.Localizer, s *model.Suggestion) *BotMenuSuggestion {
+	m := &BotMenuSuggestion{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	m.BtnApprove = m.Menu.Data(l.T(BotMenuSuggestionBtnApproveLabel), BotMenuSuggestionBtnApproveID, s.ID, "0")
+	m.BtnReject = m.Menu.Data(l.T(BotMenuSuggestionBtnRejectLabel), BotMenuSuggestionBtnRejectID, s.ID)
+	m.Menu.Inline(m.Menu.Row(m.BtnApprove, m.BtnReject))
+	return m
+}
+
+// BotMenuSuggestionCategories represents the list of the categories to approve a suggested feed into.
+type BotMenuSuggestionCategories struct {
+	Menu *telebot.ReplyMarkup
+}
+
+// NewBotMenuSuggestionCategories initializes new BotMenuSuggestionCategories.
+//
+//	The categories are shown by pages, page is the number of the page to show.
+func NewBotMenuSuggestionCategories(l *i18n.Localizer, s *model.Suggestion, cats []model.Category, page int) *BotMenuSuggestionCategories {
+	m := &BotMenuSuggestionCategories{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	pager := NewBotPager(BotMenuSuggestionBtnApproveID, s.ID)
+	items := make([]telebot.Row, 0, len(cats))
+	for _, cat := range cats {
+		if cat.Personal {
+			continue
+		}
+		btn := m.Menu.Data(categoryPathLabel(l, cats, cat), BotMenuSuggestionBtnCategoryID, s.ID, cat.ID)
+		items = append(items, m.Menu.Row(btn))
+	}
+	rows := pager.Page(l, m.Menu, items, page)
+	backBtn := m.Menu.Data(l.T(BotMenuDeleteBtnCancelLabel), BotMenuSuggestionBtnBackID, s.ID)
+	rows = append(rows, m.Menu.Row(backBtn))
+	m.Menu.Inline(rows...)
+	return m
+}
+
+// categoryPathLabel returns the label of the category preceded by the names of its parents.
+func categoryPathLabel(l *i18n.Localizer, cats []model.Category, cat model.Category) string {
+	label := cat.Label(l.Lang)
+	for _, p := range model.CategoryParents(cats, cat) {
+		label = p.LocalName(l.Lang) + " › " + label
+	}
+	return label
+}
+
+const BotBtnReadStoryLabel = "menu.update.read_story"
+
+const (
+	BotMenuUpdateBtnSaveLabel  = "menu.update.save"
+	BotMenuUpdateBtnSavedLabel = "menu.update.saved"
+	BotMenuUpdateBtnSaveID     = "btnMenuUpdateSave"
+)
+
+// BotMenuUpdate represents the buttons attached to a delivered update.
+type BotMenuUpdate struct {
+	Menu *telebot.ReplyMarkup
+
+	BtnSave telebot.Btn
+}
+
+// NewBotMenuUpdate initializes new BotMenuUpdate.
+//
+//	The update is deleted once delivered, so the buttons refer to its record in the reading history,
+//	the Save button is left out if there is no record.
+func NewBotMenuUpdate(l *i18n.Localizer, historyID string, saved bool) *BotMenuUpdate {
+	m := &BotMenuUpdate{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	if len(historyID) == 0 {
+		return m
+	}
+	label := l.T(BotMenuUpdateBtnSaveLabel)
+	if saved {
+		label = l.T(BotMenuUpdateBtnSavedLabel)
+	}
+	m.BtnSave = m.Menu.Data(label, BotMenuUpdateBtnSaveID, historyID)
+	m.Menu.Inline(m.Menu.Row(m.BtnSave))
+	return m
+}
+
+const (
+	BotMenuHistoryPageSize  = 5
+	BotMenuHistoryBtnPageID = "btnMenuHistoryPage"
+)
+
+// BotMenuHistoryPager splits user's reading history into pages.
+var BotMenuHistoryPager = BotPager{ID: BotMenuHistoryBtnPageID, Size: BotMenuHistoryPageSize}
+
+// BotMenuHistory represents a page of user's reading history.
+type BotMenuHistory struct {
+	Menu *telebot.ReplyMarkup
+}
+
+// NewBotMenuHistory initializes new BotMenuHistory.
+func NewBotMenuHistory(l *i18n.Localizer, history []model.HistoryItem, page int) *BotMenuHistory {
+	m := &BotMenuHistory{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	items := make([]telebot.Row, 0, len(history))
+	for i, h := range history {
+		btn := m.Menu.URL(fmt.Sprintf("%d. %s", i+1, h.Title), h.URL)
+		items = append(items, m.Menu.Row(btn))
+	}
+	m.Menu.Inline(BotMenuHistoryPager.Page(l, m.Menu, items, page)...)
+	return m
+}
+
+const (
+	BotMenuSavedPageSize    = 5
+	BotMenuSavedBtnRemoveID = "btnMenuSavedRemove"
+	BotMenuSavedBtnPageID   = "btnMenuSavedPage"
+)
+
+// BotMenuSavedPager splits user's bookmarks into pages.
+var BotMenuSavedPager = BotPager{ID: BotMenuSavedBtnPageID, Size: BotMenuSavedPageSize}
+
+// BotMenuSaved represents a page of user's bookmarks.
+type BotMenuSaved struct {
+	Menu *telebot.ReplyMarkup
+}
+
+// NewBotMenuSaved initializes new BotMenuSaved.
+func NewBotMenuSaved(l *i18n.Localizer, bookmarks []model.Bookmark, page int) *BotMenuSaved {
+	m := &BotMenuSaved{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	page = BotMenuSavedPager.Clamp(len(bookmarks), page)
+	items := make([]telebot.Row, 0, len(bookmarks))
+	for i, b := range bookmarks {
+		link := m.Menu.URL(fmt.Sprintf("%d. %s", i+1, b.Title), b.URL)
+		remove := m.Menu.Data("❌", BotMenuSavedBtnRemoveID, b.ID, strconv.Itoa(page))
+		items = append(items, m.Menu.Row(link, remove))
+	}
+	m.Menu.Inline(BotMenuSavedPager.Page(l, m.Menu, items, page)...)
+	return m
+}
+
+const (
+	BotMenuSearchPageSize  = 5
+	BotMenuSearchBtnPageID = "btnMenuSearchPage"
+)
+
+// BotMenuSearchResults represents a page of search results with links to the updates.
+type BotMenuSearchResults struct {
+	Menu *telebot.ReplyMarkup
+}
+
+// NewBotMenuSearchResults initializes new BotMenuSearchResults.
+//
+//	res holds the updates found on the page.
+func NewBotMenuSearchResults(l *i18n.Localizer, query string, page int, res search.Result) *BotMenuSearchResults {
+	m := &BotMenuSearchResults{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	pager := newBotMenuSearchPager(query)
+	rows := make([]telebot.Row, 0, len(res.Updates)+1)
+	for i, up := range res.Updates {
+		btn := m.Menu.URL(fmt.Sprintf("%d. %s", page*BotMenuSearchPageSize+i+1, up.Title), up.URL)
+		rows = append(rows, m.Menu.Row(btn))
+	}
+	if nav := pager.Nav(l, m.Menu, page, pager.Pages(res.Total)); len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	m.Menu.Inline(rows...)
+	return m
+}
+
+// newBotMenuSearchPager initializes the BotPager of the search results, the query goes before the page
+// number in the callback data.
+func newBotMenuSearchPager(query string) BotPager {
+	return BotPager{ID: BotMenuSearchBtnPageID, Data: []string{query}, Size: BotMenuSearchPageSize}
+}
+
+const (
+	BotMenuUpdatesPageBtnOpenID        = "btnMenuUpdatesPageOpen"
+	BotMenuUpdatesPageBtnPrevLabel     = "menu.page.previous"
+	BotMenuUpdatesPageBtnNextLabel     = "menu.page.next"
+	BotMenuUpdatesPageBtnMarkReadLabel = "menu.page.mark_read"
+	BotMenuUpdatesPageBtnMarkReadID    = "btnPageRead" // kept short to leave room for the dates of the page in 64 bytes of callback data
+	BotMenuUpdatesPageBtnSizeLabel     = "menu.page.size"
+	BotMenuUpdatesPageBtnSizeID        = "btnMenuUpdatesPageSize"
+)
+
+// BotMenuUpdatesPageRowSize is the number of the buttons opening the updates in a row, Telegram shows up to 8.
+const BotMenuUpdatesPageRowSize = 5
+
+// BotMenuUpdatesPage represents a page of unread updates in a category.
+type BotMenuUpdatesPage struct {
+	Menu *telebot.ReplyMarkup
+}
+
+// NewBotMenuUpdatesPage initializes new BotMenuUpdatesPage.
+//
+//	ups are the updates on the page starting at offset among total unread updates of the category.
+func NewBotMenuUpdatesPage(l *i18n.Localizer, cat *model.Category, ups []model.Update, offset, total, pageSize int) *BotMenuUpdatesPage {
+	m := &BotMenuUpdatesPage{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	rows := make([]telebot.Row, 0, 6)
+	var open telebot.Row
+	for i, up := range ups {
+		open = append(open, m.Menu.Data(strconv.Itoa(offset+i+1), BotMenuUpdatesPageBtnOpenID, up.ID, strconv.Itoa(offset)))
+	}
+	for len(open) > BotMenuUpdatesPageRowSize {
+		rows = append(rows, open[:BotMenuUpdatesPageRowSize])
+		open = open[BotMenuUpdatesPageRowSize:]
+	}
+	if len(open) > 0 {
+		rows = append(rows, open)
+	}
+	var nav telebot.Row
+	if offset > 0 {
+		prev := offset - pageSize
+		if prev < 0 {
+			prev = 0
+		}
+		nav = append(nav, m.Menu.Data(l.T(BotMenuUpdatesPageBtnPrevLabel), BotMenuCategoryUpdatesBtnListID, cat.ID, strconv.Itoa(prev)))
+	}
+	if next := offset + len(ups); next < total {
+		nav = append(nav, m.Menu.Data(l.T(BotMenuUpdatesPageBtnNextLabel), BotMenuCategoryUpdatesBtnListID, cat.ID, strconv.Itoa(next)))
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	if len(ups) > 0 {
+		// the page is marked read by the dates of the shown updates, the updates at offset could have changed meanwhile
+		from, to := formatBotPageDate(ups[0].Date), formatBotPageDate(ups[len(ups)-1].Date)
+		rows = append(rows, m.Menu.Row(m.Menu.Data(l.T(BotMenuUpdatesPageBtnMarkReadLabel), BotMenuUpdatesPageBtnMarkReadID, cat.ID, strconv.Itoa(offset), from, to)))
+	}
+	sizeBtn := m.Menu.Data(l.T(BotMenuUpdatesPageBtnSizeLabel, pageSize), BotMenuUpdatesPageBtnSizeID, cat.ID, strconv.Itoa(offset))
+	backBtn := m.Menu.Data(l.T(BotMenuCategoryNextUpdateBtnBackLabel), BotMenuMainBtnCheckUpdatesID)
+	rows = append(rows, m.Menu.Row(backBtn, sizeBtn))
+	m.Menu.Inline(rows...)
+	return m
+}
+
+const BotMenuMarkAllReadBtnConfirmID = "btnMenuMarkAllReadConfirm"
+
+// BotMenuMarkAllRead represents the confirmation of marking updates in all categories as read.
+type BotMenuMarkAllRead struct {
+	Menu *telebot.ReplyMarkup
+
+	BtnConfirm telebot.Btn
+	BtnCancel  telebot.Btn
+}
+
+// NewBotMenuMarkAllRead initializes new BotMenuMarkAllRead.
+func NewBotMenuMarkAllRead(l *i18n.Localizer) *BotMenuMarkAllRead {
+	m := &BotMenuMarkAllRead{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	m.BtnConfirm = m.Menu.Data(l.T(BotMenuDeleteBtnConfirmLabel), BotMenuMarkAllReadBtnConfirmID)
+	m.BtnCancel = m.Menu.Data(l.T(BotMenuDeleteBtnCancelLabel), BotMenuMainBtnCheckUpdatesID)
+	m.Menu.Inline(m.Menu.Row(m.BtnConfirm, m.BtnCancel))
+	return m
+}
+
+const (
+	BotMenuDeleteBtnConfirmLabel = "menu.confirm"
 	BotMenuDeleteBtnConfirmID    = "btnMenuDeleteConfirm"
-	BotMenuDeleteBtnCancelLabel  = "❌ Cancel"
+	BotMenuDeleteBtnCancelLabel  = "menu.cancel"
 	BotMenuDeleteBtnCancelID     = "btnMenuDeleteCancel"
 )
 
@@ -165,12 +841,105 @@ type BotMenuDelete struct {
 }
 
 // NewBotMenuDelete initializes new BotMenuDelete.
-func NewBotMenuDelete() *BotMenuDelete {
+func NewBotMenuDelete(l *i18n.Localizer) *BotMenuDelete {
 	m := &BotMenuDelete{
 		Menu: &telebot.ReplyMarkup{},
 	}
-	m.BtnConfirm = m.Menu.Data(BotMenuDeleteBtnConfirmLabel, BotMenuDeleteBtnConfirmID)
-	m.BtnCancel = m.Menu.Data(BotMenuDeleteBtnCancelLabel, BotMenuDeleteBtnCancelID)
+	m.BtnConfirm = m.Menu.Data(l.T(BotMenuDeleteBtnConfirmLabel), BotMenuDeleteBtnConfirmID)
+	m.BtnCancel = m.Menu.Data(l.T(BotMenuDeleteBtnCancelLabel), BotMenuDeleteBtnCancelID)
 	m.Menu.Inline(m.Menu.Row(m.BtnConfirm, m.BtnCancel))
 	return m
 }
+
+const (
+	BotMenuAdminDeleteFeedBtnConfirmID = "btnAdminDeleteFeed"
+	BotMenuAdminDeleteFeedBtnCancelID  = "btnAdminDeleteFeedCancel"
+)
+
+// BotMenuAdminDeleteFeed represents the confirmation of the deletion of a feed by admin.
+type BotMenuAdminDeleteFeed struct {
+	Menu *telebot.ReplyMarkup
+
+	BtnConfirm telebot.Btn
+	BtnCancel  telebot.Btn
+}
+
+// NewBotMenuAdminDeleteFeed initializes new BotMenuAdminDeleteFeed.
+func NewBotMenuAdminDeleteFeed(l *i18n.Localizer, feed *model.Feed) *BotMenuAdminDeleteFeed {
+	m := &BotMenuAdminDeleteFeed{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	m.BtnConfirm = m.Menu.Data(l.T(BotMenuDeleteBtnConfirmLabel), BotMenuAdminDeleteFeedBtnConfirmID, feed.Category.ID, feed.ID)
+	m.BtnCancel = m.Menu.Data(l.T(BotMenuDeleteBtnCancelLabel), BotMenuAdminDeleteFeedBtnCancelID)
+	m.Menu.Inline(m.Menu.Row(m.BtnConfirm, m.BtnCancel))
+	return m
+}
+
+const (
+	BotMenuAdminBroadcastBtnConfirmID = "btnAdminBroadcast"
+	BotMenuAdminBroadcastBtnCancelID  = "btnAdminBroadcastCancel"
+)
+
+// BotMenuAdminBroadcast represents the confirmation of a broadcast by admin.
+type BotMenuAdminBroadcast struct {
+	Menu *telebot.ReplyMarkup
+
+	BtnConfirm telebot.Btn
+	BtnCancel  telebot.Btn
+}
+
+// NewBotMenuAdminBroadcast initializes new BotMenuAdminBroadcast.
+func NewBotMenuAdminBroadcast(l *i18n.Localizer, b *model.Broadcast) *BotMenuAdminBroadcast {
+	m := &BotMenuAdminBroadcast{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	m.BtnConfirm = m.Menu.Data(l.T(BotMenuDeleteBtnConfirmLabel), BotMenuAdminBroadcastBtnConfirmID, b.ID)
+	m.BtnCancel = m.Menu.Data(l.T(BotMenuDeleteBtnCancelLabel), BotMenuAdminBroadcastBtnCancelID, b.ID)
+	m.Menu.Inline(m.Menu.Row(m.BtnConfirm, m.BtnCancel))
+	return m
+}
+
+const (
+	BotMenuLanguageBtnAutoLabel = "menu.language.auto"
+	BotMenuLanguageBtnSelectID  = "btnMenuLanguageSelect"
+	BotMenuLanguageBtnPageID    = "btnMenuLanguagePage"
+
+	// BotLanguageNameKey is the message naming the language in its own catalog.
+	BotLanguageNameKey = "language.name"
+)
+
+// BotMenuLanguage represents the list of languages of the bot.
+type BotMenuLanguage struct {
+	Menu *telebot.ReplyMarkup
+}
+
+// NewBotMenuLanguage initializes new BotMenuLanguage.
+//
+//	current is the language chosen by the user, empty if it follows the Telegram client.
+//	The languages are shown by pages in two columns, page is the number of the page to show.
+func NewBotMenuLanguage(l *i18n.Localizer, bundle *i18n.Bundle, current string, page int) *BotMenuLanguage {
+	m := &BotMenuLanguage{
+		Menu: &telebot.ReplyMarkup{},
+	}
+	langs := bundle.Languages()
+	auto := l.T(BotMenuLanguageBtnAutoLabel)
+	if current == "" {
+		auto = "✅ " + auto
+	}
+	rows := []telebot.Row{m.Menu.Row(m.Menu.Data(auto, BotMenuLanguageBtnSelectID))}
+	btns := make([]telebot.Btn, 0, len(langs))
+	for _, lang := range langs {
+		label := bundle.Localizer(lang).T(BotLanguageNameKey)
+		if lang == current {
+			label = "✅ " + label
+		}
+		btns = append(btns, m.Menu.Data(label, BotMenuLanguageBtnSelectID, lang))
+	}
+	pager := NewBotPager(BotMenuLanguageBtnPageID)
+	pager.Columns = 2
+	rows = append(rows, pager.Page(l, m.Menu, pager.Layout(btns), page)...)
+	backBtn := m.Menu.Data(l.T(BotBtnBackToMainMenuLabel), BotBtnBackToMainMenuID)
+	rows = append(rows, m.Menu.Row(backBtn))
+	m.Menu.Inline(rows...)
+	return m
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/d-ashesss/news-feed-bot/pkg/model"
 	"gopkg.in/tucnak/telebot.v2"
 	"log"
+	"regexp"
+	"strings"
+	"time"
 )
 
 // botHandleMessage initializes common middleware stack to handle TG message.
@@ -39,7 +42,12 @@ func botMessageHandlerWithContext(
 // botMiddlewareMessageLogMessage logs incoming message.
 func (a *App) botMiddlewareMessageLogMessage(next func(ctx context.Context, m *telebot.Message)) func(ctx context.Context, m *telebot.Message) {
 	return func(ctx context.Context, m *telebot.Message) {
-		log.Printf("[bot] Incoiming message from %s: %q", bot.GetUserName(m.Sender), m.Text)
+		// the posts of a channel come from the channel itself
+		from := m.Chat.Title
+		if m.Sender != nil {
+			from = bot.GetUserName(m.Sender)
+		}
+		log.Printf("[bot] Incoiming message from %s: %q", from, m.Text)
 
 		next(ctx, m)
 	}
@@ -48,7 +56,7 @@ func (a *App) botMiddlewareMessageLogMessage(next func(ctx context.Context, m *t
 // botMiddlewareMessageGetUser loads existing model.Subscriber or creating a new one.
 func (a *App) botMiddlewareMessageGetUser(next func(ctx context.Context, m *telebot.Message)) func(ctx context.Context, m *telebot.Message) {
 	return func(ctx context.Context, m *telebot.Message) {
-		ctx, err := loadUser(ctx, a.SubscriberModel, m.Sender)
+		ctx, err := loadUser(ctx, a.SubscriberModel, m.Chat, m.Sender)
 		if err != nil {
 			log.Printf("[bot] Failed to load user: %q", err)
 			return
@@ -57,11 +65,105 @@ func (a *App) botMiddlewareMessageGetUser(next func(ctx context.Context, m *tele
 	}
 }
 
+// botHandleAdminMessage initializes the middleware stack to handle TG message available to the bot admins only.
+func (a *App) botHandleAdminMessage(ctx context.Context, h func(ctx context.Context, m *telebot.Message)) func(m *telebot.Message) {
+	return botMessageHandlerWithContext(
+		ctx,
+		h,
+		a.botMiddlewareMessageRequireAdmin,
+		a.botMiddlewareMessageGetUser,
+		a.botMiddlewareMessageLogMessage,
+	)
+}
+
+// botMiddlewareMessageRequireAdmin ignores the message unless it comes from a bot admin.
+//
+//	The admin commands are not revealed to the other users, so they are not replied to.
+func (a *App) botMiddlewareMessageRequireAdmin(next func(ctx context.Context, m *telebot.Message)) func(ctx context.Context, m *telebot.Message) {
+	return func(ctx context.Context, m *telebot.Message) {
+		user, _ := ctx.Value(BotCtxUser).(*model.Subscriber)
+		if !a.botIsAdmin(user) {
+			log.Printf("[bot] Ignored admin message from %s", bot.GetUserName(m.Sender))
+			return
+		}
+		next(ctx, m)
+	}
+}
+
+// botHandleChatAdminMessage initializes the middleware stack to handle TG message changing the settings of the chat.
+//
+//	Only the admins of a group are allowed to change its settings.
+func (a *App) botHandleChatAdminMessage(ctx context.Context, h func(ctx context.Context, m *telebot.Message)) func(m *telebot.Message) {
+	return botMessageHandlerWithContext(
+		ctx,
+		h,
+		a.botMiddlewareMessageRequireChatAdmin,
+		a.botMiddlewareMessageGetUser,
+		a.botMiddlewareMessageLogMessage,
+	)
+}
+
+// botMiddlewareMessageRequireChatAdmin rejects the message from a group unless it comes from a chat admin.
+func (a *App) botMiddlewareMessageRequireChatAdmin(next func(ctx context.Context, m *telebot.Message)) func(ctx context.Context, m *telebot.Message) {
+	return func(ctx context.Context, m *telebot.Message) {
+		user := ctx.Value(BotCtxUser).(*model.Subscriber)
+		if !a.botIsChatAdmin(user, m.Chat, m.Sender) {
+			if _, err := a.Bot.Send(m.Chat, a.botLocalizer(user).T("msg.chat.admins_only")); err != nil {
+				log.Printf("[bot] botMiddlewareMessageRequireChatAdmin(): Failed to reply: %v", err)
+			}
+			return
+		}
+		next(ctx, m)
+	}
+}
+
+// botHandleChannelPost initializes the middleware stack to handle the commands posted to a channel.
+//
+//	The posts other than the commands are ignored. Only the channel admins can post to the channel,
+//	so the commands are trusted.
+func (a *App) botHandleChannelPost(ctx context.Context, h func(ctx context.Context, m *telebot.Message)) func(m *telebot.Message) {
+	handle := botMessageHandlerWithContext(
+		ctx,
+		h,
+		a.botMiddlewareMessageGetUser,
+		a.botMiddlewareMessageLogMessage,
+	)
+	return func(m *telebot.Message) {
+		if _, payload, ok := parseBotCommand(m.Text, a.Bot.Me.Username); ok {
+			m.Payload = payload
+			handle(m)
+		}
+	}
+}
+
 // botHandleCallback initializes common middleware stack to handle TG message.
 func (a *App) botHandleCallback(ctx context.Context, h func(ctx context.Context, cb *telebot.Callback)) func(cb *telebot.Callback) {
 	return botCallbackHandlerWithContext(
 		ctx,
 		h,
+		a.botMiddlewareCallbackRequireChannelAdmin,
+		a.botMiddlewareCallbackGetUser,
+	)
+}
+
+// botHandleChatAdminCallback initializes the middleware stack to handle TG callback changing the settings of the chat.
+//
+//	Only the admins of a group or a channel are allowed to change its settings.
+func (a *App) botHandleChatAdminCallback(ctx context.Context, h func(ctx context.Context, cb *telebot.Callback)) func(cb *telebot.Callback) {
+	return botCallbackHandlerWithContext(
+		ctx,
+		h,
+		a.botMiddlewareCallbackRequireChatAdmin,
+		a.botMiddlewareCallbackGetUser,
+	)
+}
+
+// botHandleAdminCallback initializes the middleware stack to handle TG callback available to the bot admins only.
+func (a *App) botHandleAdminCallback(ctx context.Context, h func(ctx context.Context, cb *telebot.Callback)) func(cb *telebot.Callback) {
+	return botCallbackHandlerWithContext(
+		ctx,
+		h,
+		a.botMiddlewareCallbackRequireAdmin,
 		a.botMiddlewareCallbackGetUser,
 	)
 }
@@ -83,10 +185,20 @@ func botCallbackHandlerWithContext(
 	}
 }
 
+// botHandleQuery initializes common middleware stack to handle TG inline query.
+//
+//	Inline queries do not need the user's state, so the user is not loaded.
+func (a *App) botHandleQuery(ctx context.Context, h func(ctx context.Context, q *telebot.Query)) func(q *telebot.Query) {
+	return func(q *telebot.Query) {
+		log.Printf("[bot] Incoming inline query from %s: %q", bot.GetUserName(&q.From), q.Text)
+		h(ctx, q)
+	}
+}
+
 // botMiddlewareCallbackGetUser loads existing model.Subscriber or creating a new one.
 func (a *App) botMiddlewareCallbackGetUser(next func(ctx context.Context, cb *telebot.Callback)) func(ctx context.Context, cb *telebot.Callback) {
 	return func(ctx context.Context, cb *telebot.Callback) {
-		ctx, err := loadUser(ctx, a.SubscriberModel, cb.Sender)
+		ctx, err := loadUser(ctx, a.SubscriberModel, botCallbackChat(cb), cb.Sender)
 		if err != nil {
 			log.Printf("[bot] Failed to load user: %q", err)
 			return
@@ -95,12 +207,62 @@ func (a *App) botMiddlewareCallbackGetUser(next func(ctx context.Context, cb *te
 	}
 }
 
-func loadUser(ctx context.Context, m model.SubscriberModel, s *telebot.User) (context.Context, error) {
-	userID := fmt.Sprintf("telegram:%d", s.ID)
+// botMiddlewareCallbackRequireChatAdmin rejects the callback from a group or a channel unless it comes from a chat admin.
+func (a *App) botMiddlewareCallbackRequireChatAdmin(next func(ctx context.Context, cb *telebot.Callback)) func(ctx context.Context, cb *telebot.Callback) {
+	return func(ctx context.Context, cb *telebot.Callback) {
+		user := ctx.Value(BotCtxUser).(*model.Subscriber)
+		if !a.botIsChatAdmin(user, botCallbackChat(cb), cb.Sender) {
+			_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: a.botLocalizer(user).T("msg.chat.admins_only"), ShowAlert: true})
+			return
+		}
+		next(ctx, cb)
+	}
+}
+
+// botMiddlewareCallbackRequireChannelAdmin rejects the callback from a channel unless it comes from a channel admin.
+//
+//	The menus posted to a channel are seen by its whole audience, while they are meant for the admins only.
+func (a *App) botMiddlewareCallbackRequireChannelAdmin(next func(ctx context.Context, cb *telebot.Callback)) func(ctx context.Context, cb *telebot.Callback) {
+	return func(ctx context.Context, cb *telebot.Callback) {
+		user := ctx.Value(BotCtxUser).(*model.Subscriber)
+		if user.IsChannel() && !a.botIsChatAdmin(user, botCallbackChat(cb), cb.Sender) {
+			_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: a.botLocalizer(user).T("msg.chat.admins_only"), ShowAlert: true})
+			return
+		}
+		next(ctx, cb)
+	}
+}
+
+// botMiddlewareCallbackRequireAdmin rejects the callback unless it comes from a bot admin.
+func (a *App) botMiddlewareCallbackRequireAdmin(next func(ctx context.Context, cb *telebot.Callback)) func(ctx context.Context, cb *telebot.Callback) {
+	return func(ctx context.Context, cb *telebot.Callback) {
+		user, _ := ctx.Value(BotCtxUser).(*model.Subscriber)
+		if !a.botIsAdmin(user) {
+			log.Printf("[bot] Rejected admin callback from %s", bot.GetUserName(cb.Sender))
+			_ = a.Bot.Respond(cb, &telebot.CallbackResponse{Text: a.botLocalizer(user).T("msg.admin.forbidden"), ShowAlert: true})
+			return
+		}
+		next(ctx, cb)
+	}
+}
+
+// loadUser loads the model.Subscriber of the chat creating a new one if needed, sender is the user who sent the update.
+//
+//	The groups and the channels are subscribers of their own, named after the chat.
+func loadUser(ctx context.Context, m model.SubscriberModel, chat *telebot.Chat, sender *telebot.User) (context.Context, error) {
+	userID := fmt.Sprintf("telegram:%d", chat.ID)
+	kind := botChatKind(chat)
+	name, lang := chat.Title, ""
+	if kind == model.ChatPrivate && sender != nil {
+		name = strings.TrimSpace(sender.FirstName + " " + sender.LastName)
+		lang = sender.LanguageCode
+	}
 	user, err := m.Get(ctx, userID)
 	if err != nil {
 		log.Printf("[bot] No user for %s", userID)
 		user = model.NewSubscriber(userID)
+		user.Chat = kind
+		user.Seen(user.Created, lang, name)
 		if _, err := m.Create(ctx, user); err != nil {
 			return ctx, err
 		} else {
@@ -108,7 +270,55 @@ func loadUser(ctx context.Context, m model.SubscriberModel, s *telebot.User) (co
 		}
 	} else {
 		log.Printf("[bot] Found user %q for %s", user.ID, user.UserID)
+		changed := user.Seen(time.Now(), lang, name)
+		if user.Chat != kind {
+			user.Chat = kind
+			changed = true
+		}
+		if user.Inactive {
+			user.Activate()
+			log.Printf("[bot] Reactivated user %q", user.ID)
+			changed = true
+		}
+		if changed {
+			// the cron jobs could have changed the subscriber meanwhile, e.g. its Pruned count
+			if err := m.SaveSeen(ctx, user); err != nil {
+				log.Printf("[bot] Failed to save user %q: %v", user.ID, err)
+			}
+		}
 	}
 
 	return context.WithValue(ctx, BotCtxUser, user), nil
 }
+
+// botCallbackChat returns the chat the callback came from, the callbacks of the inline messages
+// come from the private chat of the sender.
+func botCallbackChat(cb *telebot.Callback) *telebot.Chat {
+	if cb.Message != nil && cb.Message.Chat != nil {
+		return cb.Message.Chat
+	}
+	return &telebot.Chat{ID: int64(cb.Sender.ID), Type: telebot.ChatPrivate}
+}
+
+// botChatKind tells what kind of model.Subscriber the chat is.
+func botChatKind(chat *telebot.Chat) string {
+	switch chat.Type {
+	case telebot.ChatGroup, telebot.ChatSuperGroup:
+		return model.ChatGroup
+	case telebot.ChatChannel, telebot.ChatChannelPrivate:
+		return model.ChatChannel
+	}
+	return model.ChatPrivate
+}
+
+// botCommandRx matches a command to the bot: "/<command>@<bot> <payload>", the payload can span multiple lines.
+var botCommandRx = regexp.MustCompile(`(?s)^(/\w+)(@(\w+))?(\s+(.*))?$`)
+
+// parseBotCommand extracts the command and its payload from the text, the commands to other bots are ignored.
+func parseBotCommand(text, botName string) (string, string, bool) {
+	match := botCommandRx.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil || (len(match[3]) > 0 && !strings.EqualFold(match[3], botName)) {
+		return "", "", false
+	}
+	return match[1], strings.TrimSpace(match[5]), true
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"gopkg.in/tucnak/telebot.v2"
+	"testing"
+)
+
+func TestApp_botMiddlewareMessageRequireAdmin(t *testing.T) {
+	a := &App{Config: Config{BotAdmins: []string{"42"}}}
+	tests := []struct {
+		Name   string
+		UserID string
+		Want   bool
+	}{
+		{Name: "Admin", UserID: "telegram:42", Want: true},
+		{Name: "User", UserID: "telegram:7", Want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			called := false
+			h := a.botMiddlewareMessageRequireAdmin(func(ctx context.Context, m *telebot.Message) {
+				called = true
+			})
+			ctx := context.WithValue(context.Background(), BotCtxUser, model.NewSubscriber(tt.UserID))
+			h(ctx, &telebot.Message{Sender: &telebot.User{}})
+			if called != tt.Want {
+				t.Errorf("botMiddlewareMessageRequireAdmin(): got handler called %v; want %v", called, tt.Want)
+			}
+		})
+	}
+}
+
+func TestApp_botIsChatAdmin(t *testing.T) {
+	a := &App{}
+	user := model.NewSubscriber("telegram:7")
+	if !a.botIsChatAdmin(user, &telebot.Chat{ID: 7, Type: telebot.ChatPrivate}, &telebot.User{ID: 7}) {
+		t.Errorf("botIsChatAdmin(): got false for a private chat; want true")
+	}
+	group := &model.Subscriber{UserID: "telegram:-7", Chat: model.ChatGroup}
+	if a.botIsChatAdmin(group, &telebot.Chat{ID: -7, Type: telebot.ChatGroup}, nil) {
+		t.Errorf("botIsChatAdmin(): got true for a group without sender; want false")
+	}
+}
+
+func TestBotChatKind(t *testing.T) {
+	tests := []struct {
+		Type telebot.ChatType
+		Want string
+	}{
+		{Type: telebot.ChatPrivate, Want: model.ChatPrivate},
+		{Type: telebot.ChatGroup, Want: model.ChatGroup},
+		{Type: telebot.ChatSuperGroup, Want: model.ChatGroup},
+		{Type: telebot.ChatChannel, Want: model.ChatChannel},
+		{Type: telebot.ChatChannelPrivate, Want: model.ChatChannel},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.Type), func(t *testing.T) {
+			if got := botChatKind(&telebot.Chat{Type: tt.Type}); got != tt.Want {
+				t.Errorf("botChatKind(): got %q; want %q", got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestParseBotCommand(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Text    string
+		Command string
+		Payload string
+		OK      bool
+	}{
+		{Name: "Command", Text: "/autopost", Command: "/autopost", OK: true},
+		{Name: "Payload", Text: "/template <b>{{.Title}}</b>\n{{.URL}}", Command: "/template", Payload: "<b>{{.Title}}</b>\n{{.URL}}", OK: true},
+		{Name: "Mention", Text: "/menu@NewsBot", Command: "/menu", OK: true},
+		{Name: "OtherBot", Text: "/menu@OtherBot", OK: false},
+		{Name: "Post", Text: "Breaking news", OK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			cmd, payload, ok := parseBotCommand(tt.Text, "newsbot")
+			if cmd != tt.Command || payload != tt.Payload || ok != tt.OK {
+				t.Errorf("parseBotCommand(%q): got %q, %q, %v; want %q, %q, %v", tt.Text, cmd, payload, ok, tt.Command, tt.Payload, tt.OK)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/i18n"
+	"gopkg.in/tucnak/telebot.v2"
+	"strconv"
+)
+
+// BotPagerDefaultSize is the number of rows on a page if the BotPager has no Size set.
+const BotPagerDefaultSize = 8
+
+const (
+	BotPagerBtnPrevLabel = "menu.previous"
+	BotPagerBtnNextLabel = "menu.next"
+)
+
+// BotPager splits a long list of buttons into pages with the controls to switch between them.
+//
+//	Telegram limits the size of an inline keyboard, so the lists growing along with the data are shown by pages.
+//	The controls are callbacks to ID with the Data followed by the number of the page to show, starting at 0.
+type BotPager struct {
+	ID      string   // ID is the unique of the callback showing another page.
+	Data    []string // Data precedes the page number in the callback data of the controls.
+	Size    int      // Size is the number of rows on a page, BotPagerDefaultSize if not set.
+	Columns int      // Columns is the number of buttons in a row laid out by Layout, 1 if not set.
+}
+
+// NewBotPager initializes new BotPager of the callback with the data preceding the page number.
+func NewBotPager(id string, data ...string) BotPager {
+	return BotPager{ID: id, Data: data}
+}
+
+// Layout arranges the buttons in rows of Columns buttons.
+func (p BotPager) Layout(btns []telebot.Btn) []telebot.Row {
+	cols := p.columns()
+	rows := make([]telebot.Row, 0, p.Rows(len(btns)))
+	for i := 0; i < len(btns); i += cols {
+		end := i + cols
+		if end > len(btns) {
+			end = len(btns)
+		}
+		rows = append(rows, telebot.Row(btns[i:end]))
+	}
+	return rows
+}
+
+// Rows returns the number of rows the number of buttons takes in Layout.
+func (p BotPager) Rows(btns int) int {
+	return (btns + p.columns() - 1) / p.columns()
+}
+
+// Pages returns the number of pages the number of rows takes, at least one.
+func (p BotPager) Pages(rows int) int {
+	size := p.size()
+	if rows <= size {
+		return 1
+	}
+	return (rows + size - 1) / size
+}
+
+// Clamp fits the page into the range of pages the number of rows takes.
+func (p BotPager) Clamp(rows, page int) int {
+	if page >= p.Pages(rows) {
+		page = p.Pages(rows) - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+	return page
+}
+
+// Offset returns the position of the first row on the page.
+func (p BotPager) Offset(rows, page int) int {
+	return p.Clamp(rows, page) * p.size()
+}
+
+// Page returns the rows on the page followed by the controls to switch pages if there is more than one.
+func (p BotPager) Page(l *i18n.Localizer, menu *telebot.ReplyMarkup, rows []telebot.Row, page int) []telebot.Row {
+	page = p.Clamp(len(rows), page)
+	start := page * p.size()
+	end := start + p.size()
+	if end > len(rows) {
+		end = len(rows)
+	}
+	res := make([]telebot.Row, 0, end-start+1)
+	res = append(res, rows[start:end]...)
+	if nav := p.Nav(l, menu, page, p.Pages(len(rows))); len(nav) > 0 {
+		res = append(res, nav)
+	}
+	return res
+}
+
+// Nav returns the controls to switch from the page to the previous and the next ones,
+// empty if there are no other pages.
+func (p BotPager) Nav(l *i18n.Localizer, menu *telebot.ReplyMarkup, page, pages int) telebot.Row {
+	if pages <= 1 {
+		return nil
+	}
+	var nav telebot.Row
+	if page > 0 {
+		nav = append(nav, menu.Data(l.T(BotPagerBtnPrevLabel), p.ID, p.data(page-1)...))
+	}
+	nav = append(nav, menu.Data(fmt.Sprintf("%d/%d", page+1, pages), p.ID, p.data(page)...))
+	if page < pages-1 {
+		nav = append(nav, menu.Data(l.T(BotPagerBtnNextLabel), p.ID, p.data(page+1)...))
+	}
+	return nav
+}
+
+// data returns the callback data of the control showing the page.
+func (p BotPager) data(page int) []string {
+	data := make([]string, 0, len(p.Data)+1)
+	data = append(data, p.Data...)
+	return append(data, strconv.Itoa(page))
+}
+
+// size returns the number of rows on a page.
+func (p BotPager) size() int {
+	if p.Size <= 0 {
+		return BotPagerDefaultSize
+	}
+	return p.Size
+}
+
+// columns returns the number of buttons in a row.
+func (p BotPager) columns() int {
+	if p.Columns <= 0 {
+		return 1
+	}
+	return p.Columns
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"github.com/d-ashesss/news-feed-bot/pkg/i18n"
+	"gopkg.in/tucnak/telebot.v2"
+	"strconv"
+	"testing"
+)
+
+func TestBotPager_Layout(t *testing.T) {
+	btns := make([]telebot.Btn, 5)
+	pager := BotPager{Columns: 2}
+	rows := pager.Layout(btns)
+	if len(rows) != 3 {
+		t.Fatalf("Layout(): got %d rows; want 3", len(rows))
+	}
+	if len(rows[0]) != 2 || len(rows[2]) != 1 {
+		t.Errorf("Layout(): got rows of %d and %d buttons; want 2 and 1", len(rows[0]), len(rows[2]))
+	}
+	if got := pager.Rows(len(btns)); got != 3 {
+		t.Errorf("Rows(): got %d; want 3", got)
+	}
+}
+
+func TestBotPager_Clamp(t *testing.T) {
+	pager := BotPager{Size: 5}
+	tests := []struct {
+		Name string
+		Rows int
+		Page int
+		Want int
+	}{
+		{Name: "FirstPage", Rows: 12, Page: 0, Want: 0},
+		{Name: "LastPage", Rows: 12, Page: 2, Want: 2},
+		{Name: "PastTheEnd", Rows: 12, Page: 3, Want: 2},
+		{Name: "Negative", Rows: 12, Page: -1, Want: 0},
+		{Name: "Empty", Rows: 0, Page: 1, Want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := pager.Clamp(tt.Rows, tt.Page); got != tt.Want {
+				t.Errorf("Clamp(%d, %d): got %d; want %d", tt.Rows, tt.Page, got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestBotPager_Page(t *testing.T) {
+	l := i18n.NewBundle(i18n.DefaultLanguage).Localizer(i18n.DefaultLanguage)
+	menu := &telebot.ReplyMarkup{}
+	rows := make([]telebot.Row, 12)
+	for i := range rows {
+		rows[i] = menu.Row(menu.Data(strconv.Itoa(i), "item"))
+	}
+	pager := BotPager{ID: "page", Data: []string{"cat"}, Size: 5}
+
+	t.Run("Middle", func(t *testing.T) {
+		page := pager.Page(l, menu, rows, 1)
+		if len(page) != 6 {
+			t.Fatalf("Page(): got %d rows; want 6", len(page))
+		}
+		if page[0][0].Text != "5" {
+			t.Errorf("Page(): got first row %q; want 5", page[0][0].Text)
+		}
+		nav := page[5]
+		if len(nav) != 3 {
+			t.Fatalf("Page(): got %d controls; want 3", len(nav))
+		}
+		if nav[0].Data != "cat|0" || nav[2].Data != "cat|2" {
+			t.Errorf("Page(): got controls to %q and %q; want cat|0 and cat|2", nav[0].Data, nav[2].Data)
+		}
+		if nav[1].Text != "2/3" {
+			t.Errorf("Page(): got indicator %q; want 2/3", nav[1].Text)
+		}
+	})
+
+	t.Run("Last", func(t *testing.T) {
+		page := pager.Page(l, menu, rows, 5)
+		if len(page) != 3 {
+			t.Fatalf("Page(): got %d rows; want 3", len(page))
+		}
+		if nav := page[2]; len(nav) != 2 {
+			t.Errorf("Page(): got %d controls; want 2", len(nav))
+		}
+	})
+
+	t.Run("Single", func(t *testing.T) {
+		page := pager.Page(l, menu, rows[:5], 0)
+		if len(page) != 5 {
+			t.Errorf("Page(): got %d rows; want 5 with no controls", len(page))
+		}
+	})
+}
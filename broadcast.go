@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/broadcast"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"gopkg.in/tucnak/telebot.v2"
+	"time"
+)
+
+// SendBroadcast sends the announcement to the subscriber, the subscribers who can't be reached are marked inactive.
+func (a *App) SendBroadcast(ctx context.Context, s *model.Subscriber, text string) error {
+	if a.Bot == nil {
+		return errors.New("bot is not set up")
+	}
+	to, err := botRecipient(s)
+	if err != nil {
+		return err
+	}
+	if _, err = a.Bot.Send(to, text); err == nil {
+		return nil
+	}
+	a.helperHandleSendError(ctx, s, err)
+	kind := classifyBotSendError(err)
+	if kind.unreachable() {
+		return fmt.Errorf("%w: %v", broadcast.ErrBlocked, err)
+	}
+	if kind == BotSendErrorRateLimited {
+		retry := broadcast.RetryError{After: time.Second}
+		if fe, ok := err.(telebot.FloodError); ok && fe.RetryAfter > 0 {
+			retry.After = time.Duration(fe.RetryAfter) * time.Second
+		}
+		return retry
+	}
+	return err
+}
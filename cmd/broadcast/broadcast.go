@@ -0,0 +1,120 @@
+package main
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/broadcast"
+	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+func init() {
+	log.SetFlags(0)
+}
+
+const usage = "Usage: broadcast send <all|category:<category-id>|lang:<code>> <text>\n" +
+	"       broadcast status [broadcast-id]"
+
+func main() {
+	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	ctx := context.Background()
+	fsc, err := firestore.NewClient(ctx, projectID)
+	defer func(fsc *firestore.Client) {
+		_ = fsc.Close()
+	}(fsc)
+	if err != nil {
+		log.Fatalf("failed to create firestore client: %v", err)
+	}
+
+	if len(os.Args) < 2 {
+		log.Fatalf(usage)
+	}
+
+	broadcastModel := firestoreDb.NewBroadcastModel(fsc)
+	switch os.Args[1] {
+	case "send":
+		if len(os.Args) != 4 {
+			log.Fatalf(usage)
+		}
+		categoryModel := firestoreDb.NewCategoryModel(fsc)
+		updateModel := firestoreDb.NewUpdateModel(fsc)
+		subscriberModel := firestoreDb.NewSubscriberModel(fsc, updateModel)
+		d := broadcast.NewDispatcher(broadcastModel, subscriberModel, categoryModel, nil, nil, "broadcast")
+		send(ctx, broadcastModel, categoryModel, d, os.Args[2], os.Args[3])
+	case "status":
+		if len(os.Args) > 3 {
+			log.Fatalf(usage)
+		}
+		id := ""
+		if len(os.Args) == 3 {
+			id = strings.TrimSpace(os.Args[2])
+		}
+		status(ctx, broadcastModel, id)
+	default:
+		log.Fatalf(usage)
+	}
+}
+
+// send queues the broadcast to be delivered by the app.
+func send(
+	ctx context.Context,
+	broadcastModel model.BroadcastModel,
+	categoryModel model.CategoryModel,
+	d *broadcast.Dispatcher,
+	segment, text string,
+) {
+	now := time.Now().UTC()
+	b := &model.Broadcast{Text: strings.TrimSpace(text), Author: "cli", Created: now, Started: now}
+	if err := b.SetSegment(segment); err != nil {
+		log.Fatalf("invalid segment %q", segment)
+	}
+	if err := model.ValidateBroadcastText(b.Text); err != nil {
+		log.Fatalf("invalid text: the text is up to %d characters", model.MaxBroadcastLength)
+	}
+	if len(b.Category) > 0 {
+		if _, err := categoryModel.Get(ctx, b.Category); err != nil {
+			log.Fatalf("get category %q: %s", b.Category, err)
+		}
+	}
+	recipients, err := d.Recipients(ctx, *b)
+	if err != nil {
+		log.Fatalf("failed to get recipients: %s", err)
+	}
+	if _, err := broadcastModel.Create(ctx, b); err != nil {
+		log.Fatalf("create broadcast: %v", err)
+	}
+	fmt.Printf("%s to %s: %d recipients, the delivery starts with the next run of /cron/broadcast\n", b.ID, b.Segment(), len(recipients))
+}
+
+// status prints the report of the broadcast or of all of them if id is empty.
+func status(ctx context.Context, broadcastModel model.BroadcastModel, id string) {
+	var bs []model.Broadcast
+	if len(id) > 0 {
+		b, err := broadcastModel.Get(ctx, id)
+		if err != nil {
+			log.Fatalf("get broadcast %q: %s", id, err)
+		}
+		bs = append(bs, *b)
+	} else {
+		var err error
+		if bs, err = broadcastModel.GetAll(ctx); err != nil {
+			log.Fatalf("failed to get broadcasts: %s", err)
+		}
+	}
+	for _, b := range bs {
+		state := "not started"
+		switch {
+		case b.IsFinished():
+			state = "finished " + b.Finished.Format(time.RFC822)
+		case b.IsStarted():
+			state = "in progress since " + b.Started.Format(time.RFC822)
+		}
+		fmt.Printf("%s [%s] to %s by %s, %s: delivered %d, blocked %d, failed %d\n",
+			b.ID, b.Created.Format(time.RFC822), b.Segment(), b.Author, state, b.Delivered, b.Blocked, b.Failed)
+	}
+}
@@ -26,8 +26,8 @@ func main() {
 		log.Fatalf("failed to create firestore client: %v", err)
 	}
 
-	if len(os.Args) != 2 {
-		log.Fatalf("Usage: create-category <category-name>")
+	if len(os.Args) != 2 && len(os.Args) != 3 {
+		log.Fatalf("Usage: create-category <category-name> [parent-category-id]")
 	}
 
 	categoryModel := firestoreDb.NewCategoryModel(fsc)
@@ -37,6 +37,16 @@ func main() {
 		log.Fatalf("invalid category name")
 	}
 	cat := model.NewCategory(catName)
+	if len(os.Args) == 3 {
+		cat.ParentID = strings.TrimSpace(os.Args[2])
+		cats, err := categoryModel.GetAll(ctx)
+		if err != nil {
+			log.Fatalf("failed to get categories: %s", err)
+		}
+		if err := model.ValidateCategoryParent(cats, *cat, cat.ParentID); err != nil {
+			log.Fatalf("parent %q: %s", cat.ParentID, err)
+		}
+	}
 	if _, err := categoryModel.Create(ctx, cat); err != nil {
 		log.Fatalf("failed to create category: %s", err)
 	}
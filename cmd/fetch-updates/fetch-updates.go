@@ -4,12 +4,11 @@ import (
 	"cloud.google.com/go/firestore"
 	"context"
 	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
-	"github.com/d-ashesss/news-feed-bot/pkg/feed/fetcher"
-	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/d-ashesss/news-feed-bot/pkg/feed/coordinator"
+	"github.com/google/uuid"
 	"log"
 	"os"
 	"strings"
-	"time"
 )
 
 func main() {
@@ -32,6 +31,9 @@ func main() {
 	subscriberModel := firestoreDb.NewSubscriberModel(fsc, nil)
 	updateModel := firestoreDb.NewUpdateModel(fsc)
 	subscriptionModel := firestoreDb.NewSubscriptionModel(fsc, categoryModel, subscriberModel, updateModel)
+	leaseModel := firestoreDb.NewLeaseModel(fsc)
+
+	c := coordinator.New(feedModel, categoryModel, subscriptionModel, leaseModel, "fetch-updates:"+uuid.NewString())
 
 	if len(os.Args) == 2 {
 		catID := getCatId()
@@ -39,14 +41,10 @@ func main() {
 		if err != nil {
 			log.Fatalf("get category %q: %s", catID, err)
 		}
-		fetchCategory(ctx, feedModel, subscriptionModel, cat)
+		c.FetchCategory(ctx, cat, true)
 	} else {
-		cats, err := categoryModel.GetAll(ctx)
-		if err != nil {
-			log.Fatalf("get categories: %v", err)
-		}
-		for _, cat := range cats {
-			fetchCategory(ctx, feedModel, subscriptionModel, &cat)
+		if err := c.FetchAll(ctx, true); err != nil {
+			log.Fatalf("%v", err)
 		}
 	}
 }
@@ -54,21 +52,3 @@ func main() {
 func getCatId() string {
 	return strings.TrimSpace(os.Args[1])
 }
-
-func fetchCategory(ctx context.Context, feedModel model.FeedModel, subscriptionModel model.SubscriptionModel, cat *model.Category) {
-	feeds, err := feedModel.GetAll(ctx, cat)
-	if err != nil {
-		log.Printf("get feeds in %q: %s", cat.Name, err)
-		return
-	}
-
-	f := fetcher.New(subscriptionModel)
-	for _, feed := range feeds {
-		if err := f.Fetch(ctx, &feed, cat); err != nil {
-			log.Printf("fetch updates from %q: %v", feed.Title, err)
-		}
-		if err := feedModel.SetUpdated(ctx, &feed, time.Now().UTC()); err != nil {
-			log.Printf("update feed %q: %v", feed.Title, err)
-		}
-	}
-}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
 	"log"
 	"os"
 	"time"
@@ -33,14 +34,30 @@ func main() {
 		log.Fatalf("failed to get categories: %s", err)
 	}
 
+	printCategories(ctx, feedModel, cats, "", "")
+}
+
+// printCategories prints the categories nested in the parent along with their feeds,
+// the categories with unknown parents are printed at the top level.
+func printCategories(ctx context.Context, feedModel model.FeedModel, cats []model.Category, parentID, indent string) {
+	if len(indent) >= model.MaxCategoryDepth {
+		return
+	}
 	for _, cat := range cats {
-		fmt.Printf("%s: %s\n", cat.Name, cat.ID)
+		if cat.ParentID != parentID && (len(parentID) > 0 || len(model.CategoryParents(cats, cat)) > 0) {
+			continue
+		}
+		fmt.Printf("%s%s: %s\n", indent, cat.Label(""), cat.ID)
+		for _, tr := range cat.Translations {
+			fmt.Printf("%s\t%s: %s\n", indent, tr.Lang, tr.Name)
+		}
 		feeds, err := feedModel.GetAll(ctx, &cat)
 		if err != nil {
 			log.Printf("get %s feeds: %v", cat.Name, err)
 		}
 		for _, feed := range feeds {
-			fmt.Printf("\t%s %q [%s]: %s\n", feed.ID, feed.Title, feed.LastUpdate.Format(time.RFC822), feed.URL)
+			fmt.Printf("%s\t%s %q [%s]: %s\n", indent, feed.ID, feed.Title, feed.LastUpdate.Format(time.RFC822), feed.URL)
 		}
+		printCategories(ctx, feedModel, cats, cat.ID, indent+"\t")
 	}
 }
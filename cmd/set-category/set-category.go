@@ -0,0 +1,90 @@
+package main
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"fmt"
+	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"log"
+	"os"
+	"strings"
+)
+
+func init() {
+	log.SetFlags(0)
+}
+
+const usage = `Usage:
+	set-category <category-id> emoji [emoji]
+	set-category <category-id> description [text]
+	set-category <category-id> translation <lang> [name] [description]
+	set-category <category-id> parent [parent-category-id]`
+
+func main() {
+	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	ctx := context.Background()
+	fsc, err := firestore.NewClient(ctx, projectID)
+	defer func(fsc *firestore.Client) {
+		_ = fsc.Close()
+	}(fsc)
+	if err != nil {
+		log.Fatalf("failed to create firestore client: %v", err)
+	}
+
+	if len(os.Args) < 3 {
+		log.Fatal(usage)
+	}
+
+	categoryModel := firestoreDb.NewCategoryModel(fsc)
+
+	catID := strings.TrimSpace(os.Args[1])
+	cat, err := categoryModel.Get(ctx, catID)
+	if err != nil {
+		log.Fatalf("get category %q: %s", catID, err)
+	}
+
+	switch os.Args[2] {
+	case "emoji":
+		cat.Emoji = getArg(os.Args, 3)
+	case "description":
+		cat.Description = getArg(os.Args, 3)
+	case "translation":
+		lang := strings.ToLower(getArg(os.Args, 3))
+		if len(lang) == 0 {
+			log.Fatal(usage)
+		}
+		cat.SetTranslation(model.CategoryTranslation{
+			Lang:        lang,
+			Name:        getArg(os.Args, 4),
+			Description: getArg(os.Args, 5),
+		})
+	case "parent":
+		cats, err := categoryModel.GetAll(ctx)
+		if err != nil {
+			log.Fatalf("get categories: %s", err)
+		}
+		parentID := getArg(os.Args, 3)
+		if err := model.ValidateCategoryParent(cats, *cat, parentID); err != nil {
+			log.Fatalf("parent %q: %s", parentID, err)
+		}
+		cat.ParentID = parentID
+	default:
+		log.Fatal(usage)
+	}
+
+	if err := categoryModel.Save(ctx, cat); err != nil {
+		log.Fatalf("save category: %v", err)
+	}
+	fmt.Printf("%s %s: %q\n", cat.ID, cat.Label(""), cat.Description)
+	for _, tr := range cat.Translations {
+		fmt.Printf("\t%s: %q %q\n", tr.Lang, tr.Name, tr.Description)
+	}
+}
+
+func getArg(args []string, i int) string {
+	if len(args) <= i {
+		return ""
+	}
+	return strings.TrimSpace(args[i])
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"fmt"
+	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"log"
+	"os"
+	"strings"
+)
+
+func init() {
+	log.SetFlags(0)
+}
+
+func main() {
+	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	ctx := context.Background()
+	fsc, err := firestore.NewClient(ctx, projectID)
+	defer func(fsc *firestore.Client) {
+		_ = fsc.Close()
+	}(fsc)
+	if err != nil {
+		log.Fatalf("failed to create firestore client: %v", err)
+	}
+
+	if len(os.Args) < 3 || (os.Args[2] != "include" && os.Args[2] != "exclude") {
+		log.Fatalf("Usage: set-filter <category-id>[/<feed-id>] <include|exclude> [pattern...]")
+	}
+
+	feedModel := firestoreDb.NewFeedModel(fsc)
+	categoryModel := firestoreDb.NewCategoryModel(fsc)
+
+	catID, feedID := getTarget(os.Args)
+	cat, err := categoryModel.Get(ctx, catID)
+	if err != nil {
+		log.Fatalf("get category %q: %s", catID, err)
+	}
+
+	patterns := getPatterns(os.Args)
+	for _, p := range patterns {
+		if err := model.ValidatePattern(p); err != nil {
+			log.Fatalf("pattern %q: %v", p, err)
+		}
+	}
+
+	if len(feedID) == 0 {
+		filter := setPatterns(cat.Filter, os.Args[2], patterns)
+		if err := categoryModel.SetFilter(ctx, cat, filter); err != nil {
+			log.Fatalf("set category filter: %v", err)
+		}
+		fmt.Printf("%s: include %q, exclude %q\n", cat.Name, filter.Include, filter.Exclude)
+		return
+	}
+
+	feed, err := feedModel.Get(ctx, cat, feedID)
+	if err != nil {
+		log.Fatalf("get feed %q: %s", feedID, err)
+	}
+	filter := setPatterns(feed.Filter, os.Args[2], patterns)
+	if err := feedModel.SetFilter(ctx, feed, filter); err != nil {
+		log.Fatalf("set feed filter: %v", err)
+	}
+	fmt.Printf("%s %q: include %q, exclude %q\n", feed.ID, feed.Title, filter.Include, filter.Exclude)
+}
+
+func getTarget(args []string) (string, string) {
+	target := strings.SplitN(strings.TrimSpace(args[1]), "/", 2)
+	if len(target) == 2 {
+		return target[0], target[1]
+	}
+	return target[0], ""
+}
+
+func getPatterns(args []string) []string {
+	patterns := make([]string, 0, len(args)-3)
+	for _, p := range args[3:] {
+		if p = strings.TrimSpace(p); len(p) > 0 {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+func setPatterns(filter model.Filter, kind string, patterns []string) model.Filter {
+	if kind == "include" {
+		filter.Include = patterns
+	} else {
+		filter.Exclude = patterns
+	}
+	return filter
+}
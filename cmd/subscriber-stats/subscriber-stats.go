@@ -0,0 +1,65 @@
+package main
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"fmt"
+	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
+	"log"
+	"os"
+	"sort"
+	"time"
+)
+
+func init() {
+	log.SetFlags(0)
+}
+
+func main() {
+	projectID := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	ctx := context.Background()
+	fsc, err := firestore.NewClient(ctx, projectID)
+	defer func(fsc *firestore.Client) {
+		_ = fsc.Close()
+	}(fsc)
+	if err != nil {
+		log.Fatalf("failed to create firestore client: %v", err)
+	}
+
+	updateModel := firestoreDb.NewUpdateModel(fsc)
+	subscriberModel := firestoreDb.NewSubscriberModel(fsc, updateModel)
+
+	ss, err := subscriberModel.GetAll(ctx)
+	if err != nil {
+		log.Fatalf("failed to get subscribers: %s", err)
+	}
+
+	const week = 7 * 24 * time.Hour
+	inactive, joined, seen := 0, 0, 0
+	reasons := make(map[string]int)
+	for _, s := range ss {
+		if time.Since(s.Created) < week {
+			joined++
+		}
+		if time.Since(s.LastSeen) < week {
+			seen++
+		}
+		if s.Inactive {
+			inactive++
+			reasons[s.Reason]++
+		}
+	}
+	fmt.Printf("Subscribers: %d\n", len(ss))
+	fmt.Printf("Joined this week: %d\n", joined)
+	fmt.Printf("Seen this week: %d\n", seen)
+	fmt.Printf("Active: %d\n", len(ss)-inactive)
+	fmt.Printf("Inactive: %d\n", inactive)
+	keys := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		keys = append(keys, reason)
+	}
+	sort.Strings(keys)
+	for _, reason := range keys {
+		fmt.Printf("\t%s: %d\n", reason, reasons[reason])
+	}
+}
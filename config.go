@@ -2,9 +2,19 @@ package main
 
 import (
 	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/alert"
+	"github.com/d-ashesss/news-feed-bot/pkg/autopost"
+	"github.com/d-ashesss/news-feed-bot/pkg/broadcast"
+	"github.com/d-ashesss/news-feed-bot/pkg/feed/coordinator"
+	"github.com/d-ashesss/news-feed-bot/pkg/feed/fetcher"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/d-ashesss/news-feed-bot/pkg/search"
 	"github.com/d-ashesss/news-feed-bot/secretmanager"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -13,6 +23,25 @@ type Config struct {
 	WebPort         string
 	BotWebhookMode  bool
 	BotResetWebhook bool
+	AppEngine       bool           // AppEngine shows if the app is running on Google App Engine.
+	SingleInstance  bool           // SingleInstance confirms the app is served by one instance at most, as the search index requires.
+	CronToken       string         // CronToken is a bearer token accepted by the cron endpoints.
+	AdminToken      string         // AdminToken is a bearer token accepted by the admin API, the API is disabled if empty.
+	BotAdmins       []string       // BotAdmins are the Telegram IDs of the users moderating the bot.
+	FetchInterval   time.Duration  // FetchInterval enables built-in fetch scheduler when set.
+	FeedBounds      fetcher.Bounds // FeedBounds limit adaptive polling intervals of the feeds.
+	FetchLeaseTTL   time.Duration  // FetchLeaseTTL is the time a feed stays locked by the instance fetching it.
+	AlertRateCap    int            // AlertRateCap is the number of notifications a single alert can send within AlertRateWindow.
+	AlertRateWindow time.Duration  // AlertRateWindow is the period of the alert rate limit.
+	BroadcastRate   int            // BroadcastRate is the number of broadcast messages sent per second.
+	AutoPostLimit   int            // AutoPostLimit is the number of updates posted to a channel per fetch.
+	SearchIndexPath string         // SearchIndexPath is the file the search index of this instance is kept in between restarts.
+	SearchMaxAge    time.Duration  // SearchMaxAge is how long the updates can be found by search.
+	HistoryLimit    int            // HistoryLimit is the number of delivered updates kept in the reading history of a subscriber.
+	HistoryMaxAge   time.Duration  // HistoryMaxAge is how long the delivered updates are kept in the reading history.
+	RetentionMaxAge time.Duration  // RetentionMaxAge is how long unread updates are kept, zero for no limit.
+	RetentionUnread int            // RetentionUnread is the number of unread updates kept per category of a subscriber, zero for no limit.
+	LocalesPath     string         // LocalesPath is the directory with the message catalogs replacing the built-in ones.
 }
 
 func loadConfig(ctx context.Context, projectID string, secretManager *secretmanager.SecretManager) Config {
@@ -26,6 +55,24 @@ func loadConfig(ctx context.Context, projectID string, secretManager *secretmana
 		}
 	}
 
+	var cronToken string
+	cronToken, ok = os.LookupEnv("CRON_TOKEN")
+	if !ok && secretManager != nil {
+		var err error
+		if cronToken, err = secretManager.GetSecret(ctx, "cron-token"); err != nil {
+			log.Printf("[config] secretManager.GetSecret: %v", err)
+		}
+	}
+
+	var adminToken string
+	adminToken, ok = os.LookupEnv("ADMIN_TOKEN")
+	if !ok && secretManager != nil {
+		var err error
+		if adminToken, err = secretManager.GetSecret(ctx, "admin-token"); err != nil {
+			log.Printf("[config] secretManager.GetSecret: %v", err)
+		}
+	}
+
 	baseURL := os.Getenv("APP_BASE_URL")
 	if len(baseURL) == 0 {
 		if len(projectID) > 0 {
@@ -39,6 +86,36 @@ func loadConfig(ctx context.Context, projectID string, secretManager *secretmana
 
 	_, BotWebhookMode := os.LookupEnv("BOT_WEBHOOK_MODE")
 	_, BotResetWebhook := os.LookupEnv("BOT_RESET_WEBHOOK")
+	_, AppEngine := os.LookupEnv("GAE_APPLICATION")
+	_, SingleInstance := os.LookupEnv("SINGLE_INSTANCE")
+
+	BotAdmins := lookupList("BOT_ADMINS")
+	FetchInterval := lookupDuration("FETCH_INTERVAL", 0)
+	FeedBounds := fetcher.Bounds{
+		Min: lookupDuration("FEED_MIN_INTERVAL", fetcher.DefaultBounds.Min),
+		Max: lookupDuration("FEED_MAX_INTERVAL", fetcher.DefaultBounds.Max),
+	}
+	FetchLeaseTTL := lookupDuration("FETCH_LEASE_TTL", coordinator.DefaultLeaseTTL)
+	AlertRateCap := lookupInt("ALERT_RATE_CAP", alert.DefaultRateCap)
+	AlertRateWindow := lookupDuration("ALERT_RATE_WINDOW", alert.DefaultRateWindow)
+	BroadcastRate := lookupInt("BROADCAST_RATE", broadcast.DefaultRate)
+	AutoPostLimit := lookupInt("AUTOPOST_LIMIT", autopost.DefaultLimit)
+	SearchIndexPath := os.Getenv("SEARCH_INDEX_PATH")
+	SearchMaxAge := lookupDuration("SEARCH_MAX_AGE", search.DefaultMaxAge)
+	HistoryLimit := lookupInt("HISTORY_LIMIT", model.DefaultHistoryLimit)
+	HistoryMaxAge := lookupDuration("HISTORY_MAX_AGE", model.DefaultHistoryMaxAge)
+	RetentionMaxAge := lookupDuration("RETENTION_MAX_AGE", model.DefaultRetentionMaxAge)
+	RetentionUnread := lookupInt("RETENTION_MAX_UNREAD", model.DefaultRetentionMaxUnread)
+	// zero disables a retention limit, negative values are typos
+	if RetentionMaxAge < 0 {
+		log.Printf("[config] Invalid RETENTION_MAX_AGE %v: want 0 for no limit or a positive duration", RetentionMaxAge)
+		RetentionMaxAge = model.DefaultRetentionMaxAge
+	}
+	if RetentionUnread < 0 {
+		log.Printf("[config] Invalid RETENTION_MAX_UNREAD %d: want 0 for no limit or a positive number", RetentionUnread)
+		RetentionUnread = model.DefaultRetentionMaxUnread
+	}
+	LocalesPath := os.Getenv("LOCALES_PATH")
 
 	return Config{
 		TelegramToken:   telegramToken,
@@ -46,5 +123,63 @@ func loadConfig(ctx context.Context, projectID string, secretManager *secretmana
 		WebPort:         WebPort,
 		BotWebhookMode:  BotWebhookMode,
 		BotResetWebhook: BotResetWebhook,
+		AppEngine:       AppEngine,
+		SingleInstance:  SingleInstance,
+		CronToken:       cronToken,
+		AdminToken:      adminToken,
+		BotAdmins:       BotAdmins,
+		FetchInterval:   FetchInterval,
+		FeedBounds:      FeedBounds,
+		FetchLeaseTTL:   FetchLeaseTTL,
+		AlertRateCap:    AlertRateCap,
+		AlertRateWindow: AlertRateWindow,
+		BroadcastRate:   BroadcastRate,
+		AutoPostLimit:   AutoPostLimit,
+		SearchIndexPath: SearchIndexPath,
+		SearchMaxAge:    SearchMaxAge,
+		HistoryLimit:    HistoryLimit,
+		HistoryMaxAge:   HistoryMaxAge,
+		RetentionMaxAge: RetentionMaxAge,
+		RetentionUnread: RetentionUnread,
+		LocalesPath:     LocalesPath,
+	}
+}
+
+// lookupDuration reads a duration from the environment variable falling back to the default value.
+func lookupDuration(key string, def time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("[config] Invalid %s %q: %v", key, v, err)
+		return def
+	}
+	return d
+}
+
+// lookupInt reads an integer from the environment variable falling back to the default value.
+func lookupInt(key string, def int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("[config] Invalid %s %q: %v", key, v, err)
+		return def
+	}
+	return n
+}
+
+// lookupList reads a comma-separated list from the environment variable skipping empty items.
+func lookupList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			list = append(list, v)
+		}
 	}
+	return list
 }
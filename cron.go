@@ -2,46 +2,193 @@ package main
 
 import (
 	"context"
-	"github.com/d-ashesss/news-feed-bot/pkg/feed/fetcher"
+	"crypto/subtle"
+	"fmt"
 	"github.com/d-ashesss/news-feed-bot/pkg/model"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// authCron allows requests coming from App Engine Cron Service or carrying the configured bearer token.
+//
+//	X-Appengine-Cron header is stripped from external requests by App Engine only,
+//	so it is not trusted when running anywhere else.
 func (a *App) authCron(res http.ResponseWriter, r *http.Request) {
-	if head := r.Header.Get("X-Appengine-Cron"); head != "true" {
-		res.WriteHeader(http.StatusUnauthorized)
+	if a.Config.AppEngine && r.Header.Get("X-Appengine-Cron") == "true" {
+		return
+	}
+	if checkBearerToken(r, a.Config.CronToken) {
+		return
 	}
+	res.WriteHeader(http.StatusUnauthorized)
+}
+
+// checkBearerToken tells whether the request carries the token, an empty token is never accepted.
+func checkBearerToken(r *http.Request, want string) bool {
+	if len(want) == 0 {
+		return false
+	}
+	auth := r.Header.Get("Authorization")
+	token := strings.TrimPrefix(auth, "Bearer ")
+	return token != auth && subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1
 }
 
 func (a *App) handleCronFetch(res http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	cats, err := a.CategoryModel.GetAll(ctx)
-	if err != nil {
-		log.Printf("get categories: %v", err)
+	if err := a.fetchUpdates(r.Context()); err != nil {
+		log.Printf("[cron] %v", err)
+		res.WriteHeader(500)
+	}
+	// the updates saved before a failure are posted all the same
+	if err := a.autoPostUpdates(r.Context()); err != nil {
+		log.Printf("[cron] %v", err)
+	}
+}
+
+func (a *App) handleCronCleanup(res http.ResponseWriter, r *http.Request) {
+	if err := a.cleanupUpdates(r.Context()); err != nil {
+		log.Printf("[cron] %v", err)
 		res.WriteHeader(500)
-		return
 	}
-	for _, cat := range cats {
-		a.helperFetchCategory(ctx, &cat)
+}
+
+func (a *App) handleCronBroadcast(res http.ResponseWriter, r *http.Request) {
+	if err := a.resumeBroadcasts(r.Context()); err != nil {
+		log.Printf("[cron] %v", err)
+		res.WriteHeader(500)
 	}
 }
 
-func (a *App) helperFetchCategory(ctx context.Context, cat *model.Category) {
-	feeds, err := a.FeedModel.GetAll(ctx, cat)
+// scheduledFetch is a job for the built-in fetch scheduler, the new updates are posted to the channels
+// and the interrupted broadcasts are resumed along the way.
+func (a *App) scheduledFetch(ctx context.Context) {
+	log.Printf("[scheduler] Fetching updates")
+	if err := a.fetchUpdates(ctx); err != nil {
+		log.Printf("[scheduler] %v", err)
+	}
+	if err := a.autoPostUpdates(ctx); err != nil {
+		log.Printf("[scheduler] %v", err)
+	}
+	if err := a.resumeBroadcasts(ctx); err != nil {
+		log.Printf("[scheduler] %v", err)
+	}
+}
+
+// autoPostUpdates posts the new updates to the channels in auto-post mode.
+func (a *App) autoPostUpdates(ctx context.Context) error {
+	if a.Bot == nil {
+		return nil
+	}
+	return a.AutoPosts.RunAll(ctx)
+}
+
+// resumeBroadcasts delivers the broadcasts started but not finished.
+func (a *App) resumeBroadcasts(ctx context.Context) error {
+	if a.Broadcasts == nil {
+		return nil
+	}
+	return a.Broadcasts.RunAll(ctx)
+}
+
+// fetchUpdates fetches updates from the feeds of all categories that are due.
+func (a *App) fetchUpdates(ctx context.Context) error {
+	defer a.saveSearchIndex()
+	return a.Coordinator.FetchAll(ctx, false)
+}
+
+// cleanupUpdates enforces the retention policy on unread updates and reading history of all subscribers
+// and deletes the feeds added by the subscribers no one is subscribed to anymore.
+// A failure to prune does not stop the cleanup of the other subscribers, the first one is returned.
+func (a *App) cleanupUpdates(ctx context.Context) error {
+	ss, err := a.SubscriberModel.GetAll(ctx)
 	if err != nil {
-		log.Printf("get feeds in %q: %s", cat.Name, err)
-		return
+		return fmt.Errorf("get subscribers: %v", err)
+	}
+	now := time.Now()
+	var before time.Time
+	if a.Config.RetentionMaxAge > 0 {
+		before = now.Add(-a.Config.RetentionMaxAge)
 	}
+	historyLimit, historyMaxAge := a.Config.historyRetention()
+	var res error
+	total := 0
+	for i := range ss {
+		n, err := a.SubscriptionModel.Prune(ctx, &ss[i], before, a.Config.RetentionUnread)
+		if err != nil {
+			log.Printf("[cron] Failed to prune updates of %q: %v", ss[i].ID, err)
+			if res == nil {
+				res = fmt.Errorf("prune updates of %q: %v", ss[i].ID, err)
+			}
+		}
+		total += n
+		if err := a.HistoryModel.Prune(ctx, &ss[i], historyLimit, now.Add(-historyMaxAge)); err != nil {
+			log.Printf("[cron] Failed to prune history of %q: %v", ss[i].ID, err)
+			if res == nil {
+				res = fmt.Errorf("prune history of %q: %v", ss[i].ID, err)
+			}
+		}
+	}
+	log.Printf("[cron] Pruned %d unread updates of %d subscribers", total, len(ss))
+	n, err := a.cleanupCustomFeeds(ctx, ss)
+	if err != nil {
+		return fmt.Errorf("cleanup custom feeds: %v", err)
+	}
+	log.Printf("[cron] Deleted %d custom feeds without subscribers", n)
+	return res
+}
 
-	f := fetcher.New(a.SubscriptionModel)
-	for _, feed := range feeds {
-		if err := f.Fetch(ctx, &feed, cat); err != nil {
-			log.Printf("fetch updates from %q: %v", feed.Title, err)
+// cleanupCustomFeeds deletes the feeds of the personal category none of the subscribers is subscribed to.
+//
+//	The subscribers could subscribe to a feed since they were loaded, so each feed is checked again before deleting.
+func (a *App) cleanupCustomFeeds(ctx context.Context, ss []model.Subscriber) (int, error) {
+	cats, err := a.CategoryModel.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	cat, ok := model.FindPersonalCategory(cats)
+	if !ok {
+		return 0, nil
+	}
+	feeds, err := a.FeedModel.GetAll(ctx, &cat)
+	if err != nil {
+		return 0, err
+	}
+	subscribed := make(map[string]bool)
+	for _, s := range ss {
+		for _, id := range s.Feeds {
+			subscribed[id] = true
+		}
+	}
+	deleted := 0
+	for i := range feeds {
+		if subscribed[feeds[i].ID] {
+			continue
+		}
+		if ok, err := a.SubscriberModel.HasFeedSubscribers(ctx, &feeds[i]); err != nil || ok {
+			if err != nil {
+				log.Printf("[cron] Failed to check subscribers of feed %q: %v", feeds[i].URL, err)
+			}
+			continue
 		}
-		if err := a.FeedModel.SetUpdated(ctx, &feed, time.Now().UTC()); err != nil {
-			log.Printf("update feed %q: %v", feed.Title, err)
+		feeds[i].Category = &cat
+		if err := a.FeedModel.Delete(ctx, &feeds[i]); err != nil {
+			log.Printf("[cron] Failed to delete feed %q: %v", feeds[i].URL, err)
+			continue
 		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// historyRetention returns the limits of the reading history, the ones not set fall back to the defaults.
+func (c Config) historyRetention() (int, time.Duration) {
+	limit, maxAge := c.HistoryLimit, c.HistoryMaxAge
+	if limit <= 0 {
+		limit = model.DefaultHistoryLimit
+	}
+	if maxAge <= 0 {
+		maxAge = model.DefaultHistoryMaxAge
 	}
+	return limit, maxAge
 }
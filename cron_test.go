@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestApp_authCron(t *testing.T) {
@@ -10,29 +14,68 @@ func TestApp_authCron(t *testing.T) {
 	testUrl := "/cron-endpoint"
 	tests := []struct {
 		name       string
+		config     Config
 		headers    map[string]string
 		wantStatus int
 		wantBody   string
 	}{
 		{
 			name:       "Unauthorized",
+			config:     Config{AppEngine: true, CronToken: "secret"},
 			headers:    map[string]string{},
 			wantStatus: http.StatusUnauthorized,
 			wantBody:   "",
 		},
 		{
-			name: "Authorized",
+			name:   "Authorized",
+			config: Config{AppEngine: true},
 			headers: map[string]string{
 				"X-Appengine-Cron": "true",
 			},
 			wantStatus: http.StatusOK,
 			wantBody:   "ok",
 		},
+		{
+			name:   "SpoofedAppEngineHeader",
+			config: Config{},
+			headers: map[string]string{
+				"X-Appengine-Cron": "true",
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   "",
+		},
+		{
+			name:   "BearerToken",
+			config: Config{CronToken: "secret"},
+			headers: map[string]string{
+				"Authorization": "Bearer secret",
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "ok",
+		},
+		{
+			name:   "InvalidBearerToken",
+			config: Config{CronToken: "secret"},
+			headers: map[string]string{
+				"Authorization": "Bearer wrong",
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   "",
+		},
+		{
+			name:   "NoTokenConfigured",
+			config: Config{},
+			headers: map[string]string{
+				"Authorization": "Bearer ",
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   "",
+		},
 	}
 
 	for _, testData := range tests {
 		t.Run(testData.name, func(t *testing.T) {
-			test := NewAppTest()
+			test := NewAppTestWithConfig(testData.config)
 			test.httpServer.AddRoute(testMethod, testUrl, test.app.authCron, func() string {
 				return testData.wantBody
 			})
@@ -51,3 +94,188 @@ func TestApp_authCron(t *testing.T) {
 		})
 	}
 }
+
+// testFeedModel keeps the feeds of a single category in memory.
+type testFeedModel struct {
+	model.FeedModel
+	feeds   []model.Feed
+	deleted []string
+}
+
+func (m *testFeedModel) GetAll(_ context.Context, _ *model.Category) ([]model.Feed, error) {
+	return m.feeds, nil
+}
+
+func (m *testFeedModel) GetCount(_ context.Context, cat *model.Category) (int, error) {
+	n := 0
+	for _, f := range m.feeds {
+		if f.Category != nil && f.Category.ID == cat.ID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *testFeedModel) Delete(_ context.Context, f *model.Feed) error {
+	m.deleted = append(m.deleted, f.ID)
+	return nil
+}
+
+type testSubscriberModel struct {
+	model.SubscriberModel
+	subscribers []model.Subscriber
+	deactivated map[string]string
+}
+
+func (m *testSubscriberModel) Get(_ context.Context, id string) (*model.Subscriber, error) {
+	for _, s := range m.subscribers {
+		if s.UserID == id {
+			return &s, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *testSubscriberModel) GetAll(_ context.Context) ([]model.Subscriber, error) {
+	return m.subscribers, nil
+}
+
+func (m *testSubscriberModel) Save(_ context.Context, s *model.Subscriber) error {
+	for i := range m.subscribers {
+		if m.subscribers[i].ID == s.ID {
+			m.subscribers[i] = *s
+			return nil
+		}
+	}
+	return model.ErrNotFound
+}
+
+func (m *testSubscriberModel) Delete(_ context.Context, s *model.Subscriber) error {
+	ss := make([]model.Subscriber, 0, len(m.subscribers))
+	for _, sub := range m.subscribers {
+		if sub.ID != s.ID {
+			ss = append(ss, sub)
+		}
+	}
+	m.subscribers = ss
+	return nil
+}
+
+func (m *testSubscriberModel) GetStats(_ context.Context, since time.Time) (*model.SubscriberStats, error) {
+	st := &model.SubscriberStats{Total: len(m.subscribers)}
+	for _, s := range m.subscribers {
+		if !s.Inactive {
+			st.Active++
+		}
+		if !s.Created.Before(since) {
+			st.Joined++
+		}
+		if !s.LastSeen.Before(since) {
+			st.Seen++
+		}
+	}
+	return st, nil
+}
+
+func (m *testSubscriberModel) HasFeedSubscribers(_ context.Context, f *model.Feed) (bool, error) {
+	for i := range m.subscribers {
+		if m.subscribers[i].HasFeed(*f) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *testSubscriberModel) Deactivate(_ context.Context, s *model.Subscriber, reason string) error {
+	if m.deactivated == nil {
+		m.deactivated = make(map[string]string)
+	}
+	m.deactivated[s.ID] = reason
+	s.Deactivate(reason)
+	return nil
+}
+
+// testSubscriptionModel fails to prune the updates of the subscribers listed in failing.
+type testSubscriptionModel struct {
+	model.SubscriptionModel
+	failing map[string]bool
+	pruned  []string
+	unread  int
+}
+
+func (m *testSubscriptionModel) GetUnreadCount(_ context.Context) (int, error) {
+	return m.unread, nil
+}
+
+func (m *testSubscriptionModel) Prune(_ context.Context, s *model.Subscriber, _ time.Time, _ int) (int, error) {
+	if m.failing[s.ID] {
+		return 0, errors.New("failed")
+	}
+	m.pruned = append(m.pruned, s.ID)
+	return 1, nil
+}
+
+type testHistoryModel struct {
+	model.HistoryModel
+}
+
+func (m *testHistoryModel) Prune(_ context.Context, _ *model.Subscriber, _ int, _ time.Time) error {
+	return nil
+}
+
+func TestApp_cleanupUpdates(t *testing.T) {
+	subscriptions := &testSubscriptionModel{failing: map[string]bool{"s1": true}}
+	a := &App{
+		SubscriberModel:   &testSubscriberModel{subscribers: []model.Subscriber{{ID: "s1"}, {ID: "s2"}}},
+		SubscriptionModel: subscriptions,
+		HistoryModel:      &testHistoryModel{},
+		CategoryModel:     &testCategoryModel{cats: map[string]*model.Category{}},
+		FeedModel:         &testFeedModel{},
+	}
+	if err := a.cleanupUpdates(context.Background()); err == nil {
+		t.Errorf("cleanupUpdates(): want the prune failure reported")
+	}
+	if len(subscriptions.pruned) != 1 || subscriptions.pruned[0] != "s2" {
+		t.Errorf("cleanupUpdates(): got pruned %v; want s2 pruned after s1 failed", subscriptions.pruned)
+	}
+}
+
+func TestApp_cleanupCustomFeeds(t *testing.T) {
+	feeds := &testFeedModel{feeds: []model.Feed{{ID: "kept"}, {ID: "orphan"}, {ID: "new"}}}
+	ss := []model.Subscriber{{ID: "s1", Feeds: []string{"kept"}}, {ID: "s2"}}
+	// s2 subscribes to the new feed after the subscribers were loaded
+	subscribers := &testSubscriberModel{subscribers: []model.Subscriber{ss[0], {ID: "s2", Feeds: []string{"new"}}}}
+	a := &App{
+		CategoryModel:   &testCategoryModel{cats: map[string]*model.Category{"my": {ID: "my", Personal: true}}},
+		FeedModel:       feeds,
+		SubscriberModel: subscribers,
+	}
+	n, err := a.cleanupCustomFeeds(context.Background(), ss)
+	if err != nil {
+		t.Fatalf("cleanupCustomFeeds(): %v", err)
+	}
+	if n != 1 || len(feeds.deleted) != 1 || feeds.deleted[0] != "orphan" {
+		t.Errorf("cleanupCustomFeeds(): got %d deleted %v; want orphan", n, feeds.deleted)
+	}
+}
+
+func TestConfig_historyRetention(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		wantLimit  int
+		wantMaxAge time.Duration
+	}{
+		{name: "Configured", config: Config{HistoryLimit: 10, HistoryMaxAge: time.Hour}, wantLimit: 10, wantMaxAge: time.Hour},
+		{name: "NotSet", config: Config{}, wantLimit: model.DefaultHistoryLimit, wantMaxAge: model.DefaultHistoryMaxAge},
+		{name: "Negative", config: Config{HistoryLimit: -1, HistoryMaxAge: -time.Hour}, wantLimit: model.DefaultHistoryLimit, wantMaxAge: model.DefaultHistoryMaxAge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, maxAge := tt.config.historyRetention()
+			if limit != tt.wantLimit || maxAge != tt.wantMaxAge {
+				t.Errorf("historyRetention(): got %d, %v; want %d, %v", limit, maxAge, tt.wantLimit, tt.wantMaxAge)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"time"
+)
+
+// subscriberExport is the data kept about a subscriber, handed over on the subscriber's request.
+type subscriberExport struct {
+	UserID     string                     `json:"user_id"`
+	Name       string                     `json:"name,omitempty"`
+	Language   string                     `json:"language,omitempty"`
+	Locale     string                     `json:"locale,omitempty"`
+	Created    time.Time                  `json:"created"`
+	LastSeen   time.Time                  `json:"last_seen"`
+	Categories []exportCategory           `json:"categories"`
+	Feeds      []string                   `json:"feeds"`
+	MutedFeeds []string                   `json:"muted_feeds"`
+	Filters    []model.SubscriptionFilter `json:"filters"`
+	Alerts     []string                   `json:"alerts"`
+	Bookmarks  []exportBookmark           `json:"bookmarks"`
+}
+
+type exportCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type exportBookmark struct {
+	Title    string    `json:"title"`
+	Category string    `json:"category,omitempty"`
+	URL      string    `json:"url"`
+	Date     time.Time `json:"date"`
+	Saved    time.Time `json:"saved"`
+}
+
+// exportSubscriber collects the data of the subscriber including the alerts and the bookmarks as JSON.
+func (a *App) exportSubscriber(ctx context.Context, s *model.Subscriber) ([]byte, error) {
+	alerts, err := a.AlertModel.GetForSubscriber(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("get alerts: %v", err)
+	}
+	bookmarks, err := a.BookmarkModel.GetForSubscriber(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("get bookmarks: %v", err)
+	}
+	exp := subscriberExport{
+		UserID:     s.UserID,
+		Name:       s.Name,
+		Language:   s.Language,
+		Locale:     s.Locale,
+		Created:    s.Created,
+		LastSeen:   s.LastSeen,
+		Categories: make([]exportCategory, len(s.Categories)),
+		Feeds:      s.Feeds,
+		MutedFeeds: s.MutedFeeds,
+		Filters:    s.Filters,
+		Alerts:     make([]string, len(alerts)),
+		Bookmarks:  make([]exportBookmark, len(bookmarks)),
+	}
+	for i, cat := range s.Categories {
+		exp.Categories[i] = exportCategory{ID: cat.ID, Name: cat.Name}
+	}
+	for i, alert := range alerts {
+		exp.Alerts[i] = alert.Phrase
+	}
+	for i, b := range bookmarks {
+		exp.Bookmarks[i] = exportBookmark{Title: b.Title, Category: b.Category, URL: b.URL, Date: b.Date, Saved: b.Saved}
+	}
+	return json.MarshalIndent(exp, "", "  ")
+}
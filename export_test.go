@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"testing"
+	"time"
+)
+
+type testAlertModel struct {
+	model.AlertModel
+	alerts []model.Alert
+}
+
+func (m *testAlertModel) GetForSubscriber(_ context.Context, s *model.Subscriber) ([]model.Alert, error) {
+	var alerts []model.Alert
+	for _, a := range m.alerts {
+		if a.Subscriber.ID == s.ID {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts, nil
+}
+
+type testBookmarkModel struct {
+	model.BookmarkModel
+	bookmarks []model.Bookmark
+}
+
+func (m *testBookmarkModel) GetForSubscriber(_ context.Context, s *model.Subscriber) ([]model.Bookmark, error) {
+	var bookmarks []model.Bookmark
+	for _, b := range m.bookmarks {
+		if b.Subscriber.ID == s.ID {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+	return bookmarks, nil
+}
+
+func TestApp_exportSubscriber(t *testing.T) {
+	s := &model.Subscriber{ID: "s1", UserID: "telegram:1", Categories: []model.Category{{ID: "world", Name: "World"}}}
+	other := &model.Subscriber{ID: "s2", UserID: "telegram:2"}
+	saved := time.Date(2000, 1, 1, 10, 0, 0, 0, time.UTC)
+	a := &App{
+		AlertModel: &testAlertModel{alerts: []model.Alert{
+			{Subscriber: s, Phrase: "elections"},
+			{Subscriber: other, Phrase: "sports"},
+		}},
+		BookmarkModel: &testBookmarkModel{bookmarks: []model.Bookmark{
+			{Subscriber: s, Title: "Story", Category: "World", URL: "https://example.com/story", Saved: saved},
+			{Subscriber: other, Title: "Other story", URL: "https://example.com/other"},
+		}},
+	}
+
+	data, err := a.exportSubscriber(context.Background(), s)
+	if err != nil {
+		t.Fatalf("exportSubscriber(): %v", err)
+	}
+	var got subscriberExport
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("exportSubscriber(): invalid JSON: %v", err)
+	}
+	if got.UserID != s.UserID || len(got.Categories) != 1 || got.Categories[0].ID != "world" {
+		t.Errorf("exportSubscriber(): got %+v; want the subscriber %q", got, s.UserID)
+	}
+	if len(got.Alerts) != 1 || got.Alerts[0] != "elections" {
+		t.Errorf("exportSubscriber(): got alerts %v; want only the subscriber's one", got.Alerts)
+	}
+	if len(got.Bookmarks) != 1 || got.Bookmarks[0].URL != "https://example.com/story" || !got.Bookmarks[0].Saved.Equal(saved) {
+		t.Errorf("exportSubscriber(): got bookmarks %+v; want only the subscriber's one", got.Bookmarks)
+	}
+}
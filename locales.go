@@ -0,0 +1,31 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/i18n"
+	"io/fs"
+)
+
+// locales are the message catalogs built into the bot.
+//
+//go:embed locales/*.json
+var locales embed.FS
+
+// loadLocales loads the built-in message catalogs, then the ones in dir replacing them unless dir is empty.
+func loadLocales(dir string) (*i18n.Bundle, error) {
+	bundle := i18n.NewBundle(i18n.DefaultLanguage)
+	catalogs, err := fs.Sub(locales, "locales")
+	if err != nil {
+		return nil, err
+	}
+	if err := bundle.LoadFS(catalogs); err != nil {
+		return nil, fmt.Errorf("built-in catalogs: %v", err)
+	}
+	if len(dir) > 0 {
+		if err := bundle.Load(dir); err != nil {
+			return nil, err
+		}
+	}
+	return bundle, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/d-ashesss/news-feed-bot/pkg/i18n"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// botMessageKeyRx matches string literals that look like keys of the message catalogs.
+var botMessageKeyRx = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z0-9_]+)+$`)
+
+func TestLocales(t *testing.T) {
+	bundle, err := loadLocales("")
+	if err != nil {
+		t.Fatalf("loadLocales(): %v", err)
+	}
+	if len(bundle.Languages()) == 0 || !bundle.Has(i18n.DefaultLanguage, BotLanguageNameKey) {
+		t.Fatalf("loadLocales(): got %v; want the default language", bundle.Languages())
+	}
+
+	t.Run("complete catalogs", func(t *testing.T) {
+		for lang, keys := range bundle.Missing() {
+			t.Errorf("%s catalog is missing %s", lang, strings.Join(keys, ", "))
+		}
+	})
+
+	t.Run("keys used by the bot", func(t *testing.T) {
+		files, err := filepath.Glob("*.go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fset := token.NewFileSet()
+		for _, f := range files {
+			if strings.HasSuffix(f, "_test.go") {
+				continue
+			}
+			file, err := parser.ParseFile(fset, f, nil, 0)
+			if err != nil {
+				t.Fatalf("ParseFile(%s): %v", f, err)
+			}
+			ast.Inspect(file, func(n ast.Node) bool {
+				lit, ok := n.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				key, err := strconv.Unquote(lit.Value)
+				if err != nil || !botMessageKeyRx.MatchString(key) {
+					return true
+				}
+				for _, lang := range bundle.Languages() {
+					if !bundle.Has(lang, key) {
+						t.Errorf("%s: %q is missing in %s catalog", fset.Position(lit.Pos()), key, lang)
+					}
+				}
+				return true
+			})
+		}
+	})
+}
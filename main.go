@@ -31,6 +31,9 @@ func main() {
 	}
 
 	config := loadConfig(ctx, projectID, secretManager)
+	if config.AppEngine && !config.SingleInstance {
+		log.Fatalf("[main] The search index is local to the instance: limit the service to max_instances: 1 and set SINGLE_INSTANCE")
+	}
 
 	fstore, err := firestore.NewClient(ctx, projectID)
 	if err != nil {
@@ -43,10 +46,16 @@ func main() {
 	feedModel := firestoreDb.NewFeedModel(fstore)
 	categoryModel := firestoreDb.NewCategoryModel(fstore)
 	updateModel := firestoreDb.NewUpdateModel(fstore)
-	subscriberModel := firestoreDb.NewSubscriberModel(fstore, updateModel)
+	alertModel := firestoreDb.NewAlertModel(fstore)
+	bookmarkModel := firestoreDb.NewBookmarkModel(fstore)
+	historyModel := firestoreDb.NewHistoryModel(fstore)
+	suggestionModel := firestoreDb.NewSuggestionModel(fstore)
+	subscriberModel := firestoreDb.NewSubscriberModel(fstore, updateModel, alertModel, bookmarkModel, historyModel, suggestionModel)
 	subscriptionModel := firestoreDb.NewSubscriptionModel(fstore, categoryModel, subscriberModel, updateModel)
+	leaseModel := firestoreDb.NewLeaseModel(fstore)
+	broadcastModel := firestoreDb.NewBroadcastModel(fstore)
 
-	app := NewApp(config, httpServer, feedModel, categoryModel, subscriberModel, subscriptionModel)
+	app := NewApp(config, httpServer, feedModel, categoryModel, subscriberModel, subscriptionModel, leaseModel, alertModel, bookmarkModel, historyModel, suggestionModel, broadcastModel)
 
 	b, err := bot.New(config.TelegramToken)
 	if err != nil {
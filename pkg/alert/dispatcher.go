@@ -0,0 +1,156 @@
+package alert
+
+import (
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"log"
+	"sync"
+	"time"
+)
+
+// Default rate limits of a single alert.
+const (
+	DefaultRateCap    = 5
+	DefaultRateWindow = time.Hour
+)
+
+// refreshInterval is how long the loaded alerts are used before being reloaded from the DB.
+const refreshInterval = time.Minute
+
+// Notifier delivers alert notifications.
+type Notifier interface {
+	// NotifyAlert sends the update to the subscriber highlighting the matched phrases.
+	NotifyAlert(ctx context.Context, s *model.Subscriber, up model.Update, phrases []string) error
+}
+
+// Dispatcher matches ingested updates against alerts of all subscribers and notifies them about matches.
+type Dispatcher struct {
+	alertModel model.AlertModel
+	notifier   Notifier
+
+	RateCap    int           // RateCap is the number of notifications a single alert can send within RateWindow.
+	RateWindow time.Duration // RateWindow is the period of the rate limit.
+
+	mu      sync.Mutex
+	alerts  []model.Alert
+	matcher *Matcher
+	loaded  time.Time
+	now     func() time.Time
+}
+
+// NewDispatcher initializes new Dispatcher.
+func NewDispatcher(alertModel model.AlertModel, notifier Notifier) *Dispatcher {
+	return &Dispatcher{
+		alertModel: alertModel,
+		notifier:   notifier,
+		RateCap:    DefaultRateCap,
+		RateWindow: DefaultRateWindow,
+		now:        time.Now,
+	}
+}
+
+// Invalidate makes the Dispatcher reload the alerts on the next update.
+func (d *Dispatcher) Invalidate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.loaded = time.Time{}
+}
+
+// notification is a message to a subscriber about the alerts matching an update.
+type notification struct {
+	subscriber *model.Subscriber
+	phrases    []string
+	alerts     []model.Alert // alerts are the copies of the matched alerts with the notification counted in.
+}
+
+// OnUpdate notifies subscribers whose alerts match the update, one message per subscriber.
+//
+//	The notifications are counted against the rate limits under the lock and sent after it is released,
+//	so a slow delivery does not hold up the other updates.
+func (d *Dispatcher) OnUpdate(ctx context.Context, up model.Update) {
+	for _, n := range d.match(ctx, up) {
+		if err := d.notifier.NotifyAlert(ctx, n.subscriber, up, n.phrases); err != nil {
+			log.Printf("[alert] notify %q: %v", n.subscriber.UserID, err)
+			d.uncount(n.alerts)
+			continue
+		}
+		for i := range n.alerts {
+			a := &n.alerts[i]
+			if err := d.alertModel.SetSent(ctx, a, a.Sent, a.WindowStart); err != nil {
+				log.Printf("[alert] save alert %q: %v", a.Phrase, err)
+			}
+		}
+	}
+}
+
+// match prepares the notifications about the update counting them against the rate limits of the alerts.
+func (d *Dispatcher) match(ctx context.Context, up model.Update) []notification {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.load(ctx); err != nil {
+		log.Printf("[alert] load alerts: %v", err)
+		return nil
+	}
+
+	var ns []notification
+	bySubscriber := make(map[string]int)
+	for _, i := range d.matcher.Match(up.Title + "\n" + up.Summary) {
+		a := &d.alerts[i]
+		if a.Subscriber == nil || a.Subscriber.Inactive || !d.allow(a) {
+			continue
+		}
+		a.Sent++
+		j, ok := bySubscriber[a.Subscriber.ID]
+		if !ok {
+			j = len(ns)
+			bySubscriber[a.Subscriber.ID] = j
+			ns = append(ns, notification{subscriber: a.Subscriber})
+		}
+		ns[j].phrases = append(ns[j].phrases, a.Phrase)
+		ns[j].alerts = append(ns[j].alerts, *a)
+	}
+	return ns
+}
+
+// uncount gives the notifications failed to be sent back to the rate limits of the alerts.
+func (d *Dispatcher) uncount(alerts []model.Alert) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, failed := range alerts {
+		for i := range d.alerts {
+			a := &d.alerts[i]
+			if a.ID == failed.ID && a.WindowStart.Equal(failed.WindowStart) && a.Sent > 0 {
+				a.Sent--
+			}
+		}
+	}
+}
+
+// allow checks the rate limit of the alert starting a new window if the current one is over.
+func (d *Dispatcher) allow(a *model.Alert) bool {
+	now := d.now().UTC()
+	if now.Sub(a.WindowStart) >= d.RateWindow {
+		a.WindowStart = now
+		a.Sent = 0
+	}
+	return a.Sent < d.RateCap
+}
+
+// load reloads the alerts and rebuilds the matcher if they are outdated.
+func (d *Dispatcher) load(ctx context.Context) error {
+	if d.matcher != nil && d.now().Sub(d.loaded) < refreshInterval {
+		return nil
+	}
+	alerts, err := d.alertModel.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	phrases := make([]string, len(alerts))
+	for i, a := range alerts {
+		phrases[i] = a.Phrase
+	}
+	d.alerts = alerts
+	d.matcher = NewMatcher(phrases)
+	d.loaded = d.now()
+	return nil
+}
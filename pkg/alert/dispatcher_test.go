@@ -0,0 +1,131 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"testing"
+	"time"
+)
+
+type testAlertModel struct {
+	model.AlertModel
+	alerts []model.Alert
+}
+
+func (m *testAlertModel) GetAll(_ context.Context) ([]model.Alert, error) {
+	alerts := make([]model.Alert, len(m.alerts))
+	copy(alerts, m.alerts)
+	return alerts, nil
+}
+
+func (m *testAlertModel) SetSent(_ context.Context, a *model.Alert, sent int, windowStart time.Time) error {
+	a.Sent = sent
+	a.WindowStart = windowStart
+	return nil
+}
+
+type testNotification struct {
+	userID  string
+	phrases []string
+}
+
+type testNotifier struct {
+	sent     []testNotification
+	err      error
+	onNotify func()
+}
+
+func (n *testNotifier) NotifyAlert(_ context.Context, s *model.Subscriber, _ model.Update, phrases []string) error {
+	if n.onNotify != nil {
+		n.onNotify()
+	}
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, testNotification{userID: s.UserID, phrases: phrases})
+	return nil
+}
+
+func TestDispatcher_OnUpdate(t *testing.T) {
+	ctx := context.Background()
+	s1 := &model.Subscriber{ID: "s1", UserID: "U1"}
+	s2 := &model.Subscriber{ID: "s2", UserID: "U2"}
+	alertModel := &testAlertModel{alerts: []model.Alert{
+		{ID: "a1", Subscriber: s1, Phrase: "mars"},
+		{ID: "a2", Subscriber: s1, Phrase: "rover"},
+		{ID: "a3", Subscriber: s2, Phrase: "rover"},
+		{ID: "a4", Subscriber: s2, Phrase: "moon"},
+	}}
+	notifier := &testNotifier{}
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := NewDispatcher(alertModel, notifier)
+	d.RateCap = 2
+	d.now = func() time.Time { return now }
+
+	d.OnUpdate(ctx, model.Update{Title: "Mars rover lands", Summary: "NASA says"})
+	if len(notifier.sent) != 2 {
+		t.Fatalf("OnUpdate(): got %d notifications; want 2", len(notifier.sent))
+	}
+	if n := notifier.sent[0]; n.userID != "U1" || len(n.phrases) != 2 {
+		t.Errorf("OnUpdate(): got %v; want both phrases for U1", n)
+	}
+	if n := notifier.sent[1]; n.userID != "U2" || len(n.phrases) != 1 || n.phrases[0] != "rover" {
+		t.Errorf("OnUpdate(): got %v; want rover for U2", n)
+	}
+
+	t.Run("rate cap", func(t *testing.T) {
+		notifier.sent = nil
+		d.OnUpdate(ctx, model.Update{Title: "Mars again"})
+		d.OnUpdate(ctx, model.Update{Title: "Mars once more"})
+		if len(notifier.sent) != 1 {
+			t.Errorf("OnUpdate(): got %d notifications; want 1 within the cap", len(notifier.sent))
+		}
+	})
+
+	t.Run("new window", func(t *testing.T) {
+		notifier.sent = nil
+		now = now.Add(DefaultRateWindow)
+		d.OnUpdate(ctx, model.Update{Title: "Mars after an hour"})
+		if len(notifier.sent) != 1 {
+			t.Errorf("OnUpdate(): got %d notifications; want 1 in the new window", len(notifier.sent))
+		}
+	})
+
+	t.Run("inactive subscriber", func(t *testing.T) {
+		notifier.sent = nil
+		now = now.Add(DefaultRateWindow)
+		s2.Inactive = true
+		defer func() { s2.Inactive = false }()
+		d.OnUpdate(ctx, model.Update{Title: "Moon rover"})
+		if len(notifier.sent) != 1 || notifier.sent[0].userID != "U1" {
+			t.Errorf("OnUpdate(): got %v; want only U1 notified", notifier.sent)
+		}
+	})
+
+	t.Run("failed notification", func(t *testing.T) {
+		notifier.sent = nil
+		now = now.Add(DefaultRateWindow)
+		notifier.err = errors.New("failed")
+		d.OnUpdate(ctx, model.Update{Title: "Moon"})
+		d.OnUpdate(ctx, model.Update{Title: "Moon"})
+		notifier.err = nil
+		d.OnUpdate(ctx, model.Update{Title: "Moon"})
+		d.OnUpdate(ctx, model.Update{Title: "Moon"})
+		if len(notifier.sent) != 2 {
+			t.Errorf("OnUpdate(): got %d notifications; want the failed ones not counted against the cap", len(notifier.sent))
+		}
+	})
+
+	t.Run("notify without lock", func(t *testing.T) {
+		notifier.sent = nil
+		now = now.Add(DefaultRateWindow)
+		// the dispatcher would deadlock if the notifier was called under its lock
+		notifier.onNotify = d.Invalidate
+		defer func() { notifier.onNotify = nil }()
+		d.OnUpdate(ctx, model.Update{Title: "Mars"})
+		if len(notifier.sent) != 1 {
+			t.Errorf("OnUpdate(): got %d notifications; want 1", len(notifier.sent))
+		}
+	})
+}
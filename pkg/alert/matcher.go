@@ -0,0 +1,116 @@
+package alert
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Matcher finds all of its phrases in a text in a single pass using Aho-Corasick automaton.
+//
+//	Matching ignores case and only whole words are matched, so "art" does not match "start".
+type Matcher struct {
+	phrases []string
+	nodes   []matcherNode
+}
+
+type matcherNode struct {
+	next map[byte]int // next are the transitions by the next byte of a phrase.
+	fail int          // fail is the node of the longest proper suffix present in the automaton.
+	out  []int        // out are the phrases ending in this node, including the ones reachable by fail links.
+}
+
+// NewMatcher builds the automaton for the phrases.
+func NewMatcher(phrases []string) *Matcher {
+	m := &Matcher{
+		phrases: make([]string, len(phrases)),
+		nodes:   []matcherNode{{next: map[byte]int{}}},
+	}
+	for i, p := range phrases {
+		p = strings.ToLower(strings.TrimSpace(p))
+		m.phrases[i] = p
+		if len(p) == 0 {
+			continue
+		}
+		cur := 0
+		for j := 0; j < len(p); j++ {
+			nxt, ok := m.nodes[cur].next[p[j]]
+			if !ok {
+				m.nodes = append(m.nodes, matcherNode{next: map[byte]int{}})
+				nxt = len(m.nodes) - 1
+				m.nodes[cur].next[p[j]] = nxt
+			}
+			cur = nxt
+		}
+		m.nodes[cur].out = append(m.nodes[cur].out, i)
+	}
+
+	queue := make([]int, 0, len(m.nodes))
+	for _, n := range m.nodes[0].next {
+		queue = append(queue, n)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for c, nxt := range m.nodes[cur].next {
+			m.nodes[nxt].fail = m.step(m.nodes[cur].fail, c)
+			m.nodes[nxt].out = append(m.nodes[nxt].out, m.nodes[m.nodes[nxt].fail].out...)
+			queue = append(queue, nxt)
+		}
+	}
+	return m
+}
+
+// step follows the transition by byte c from the node falling back by fail links.
+func (m *Matcher) step(cur int, c byte) int {
+	for {
+		if nxt, ok := m.nodes[cur].next[c]; ok {
+			return nxt
+		}
+		if cur == 0 {
+			return 0
+		}
+		cur = m.nodes[cur].fail
+	}
+}
+
+// Match returns sorted indices of the phrases found in the text.
+func (m *Matcher) Match(text string) []int {
+	t := strings.ToLower(text)
+	found := make(map[int]bool)
+	cur := 0
+	for i := 0; i < len(t); i++ {
+		cur = m.step(cur, t[i])
+		for _, p := range m.nodes[cur].out {
+			if !found[p] && isWholeWord(t, i+1-len(m.phrases[p]), i+1) {
+				found[p] = true
+			}
+		}
+	}
+	matches := make([]int, 0, len(found))
+	for p := range found {
+		matches = append(matches, p)
+	}
+	sort.Ints(matches)
+	return matches
+}
+
+// isWholeWord checks that the text[start:end] is not surrounded by letters or digits.
+func isWholeWord(text string, start, end int) bool {
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(text[:start]); isWordRune(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		if r, _ := utf8.DecodeRuneInString(text[end:]); isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,29 @@
+package alert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	m := NewMatcher([]string{"he", "she", "his", "hers", "Climate Change", "art", "ЕС", ""})
+	tests := []struct {
+		name string
+		text string
+		want []int
+	}{
+		{name: "no match", text: "nothing to see", want: []int{}},
+		{name: "whole words", text: "she said he was his", want: []int{0, 1, 2}},
+		{name: "inside words", text: "ushers start", want: []int{}},
+		{name: "ignore case", text: "CLIMATE change summit", want: []int{4}},
+		{name: "punctuation", text: "hers. (art)", want: []int{3, 5}},
+		{name: "unicode", text: "Саммит ЕС.", want: []int{6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Match(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Match(%q): got %v; want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
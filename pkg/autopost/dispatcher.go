@@ -0,0 +1,158 @@
+package autopost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/broadcast"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"log"
+	"time"
+)
+
+// Default limits of posting to a channel.
+const (
+	DefaultLimit    = 10
+	DefaultInterval = time.Second
+	DefaultLeaseTTL = 2 * time.Minute
+)
+
+// ErrPostponed is returned by the Poster when the channel can not be posted to at the moment,
+// the update is kept to be posted on the next run.
+var ErrPostponed = errors.New("posting is postponed")
+
+// Poster publishes updates to the channels.
+type Poster interface {
+	// PostUpdate posts the update to the channel formatted with the template, or the default format if it is nil.
+	PostUpdate(ctx context.Context, s *model.Subscriber, up model.Update, t *Template) error
+}
+
+// Dispatcher posts the unread updates of the channels in auto-post mode to the channels.
+//
+//	The unread updates of a channel are the queue of the posts, an update is marked read once posted,
+//	so the updates left after an interrupted run are posted on the next one.
+type Dispatcher struct {
+	subscriberModel   model.SubscriberModel
+	subscriptionModel model.SubscriptionModel
+	leaseModel        model.LeaseModel
+	poster            Poster
+	holder            string
+
+	Limit    int           // Limit is the number of updates loaded at a time, the queue is posted in batches.
+	Interval time.Duration // Interval is the time between two posts.
+	LeaseTTL time.Duration // LeaseTTL is the time a channel stays locked by the runner, the posting stops at half of it.
+
+	wait func(ctx context.Context, d time.Duration) error
+}
+
+// NewDispatcher initializes new Dispatcher, holder identifies the runner in the leases of the channels.
+func NewDispatcher(
+	subscriberModel model.SubscriberModel,
+	subscriptionModel model.SubscriptionModel,
+	leaseModel model.LeaseModel,
+	poster Poster,
+	holder string,
+) *Dispatcher {
+	return &Dispatcher{
+		subscriberModel:   subscriberModel,
+		subscriptionModel: subscriptionModel,
+		leaseModel:        leaseModel,
+		poster:            poster,
+		holder:            holder,
+		Limit:             DefaultLimit,
+		Interval:          DefaultInterval,
+		LeaseTTL:          DefaultLeaseTTL,
+		wait:              broadcast.Wait,
+	}
+}
+
+// RunAll posts the unread updates to all the channels in auto-post mode.
+//
+//	The channels posted to by another runner are skipped.
+func (d *Dispatcher) RunAll(ctx context.Context) error {
+	ss, err := d.subscriberModel.GetAutoPosting(ctx)
+	if err != nil {
+		return fmt.Errorf("get subscribers: %v", err)
+	}
+	channels, total := 0, 0
+	for i := range ss {
+		if !ss[i].IsAutoPosting() {
+			continue
+		}
+		n, err := d.Run(ctx, &ss[i])
+		if err == model.ErrLeaseTaken {
+			continue
+		}
+		if err != nil {
+			log.Printf("[autopost] post to %q: %v", ss[i].UserID, err)
+		}
+		channels++
+		total += n
+	}
+	log.Printf("[autopost] Posted %d updates to %d channels", total, channels)
+	return nil
+}
+
+// Run posts the oldest unread updates to the channel under its lease, returns the number of posted updates.
+//
+//	The queue is posted in batches of Limit updates until it is empty or half of the LeaseTTL has passed,
+//	the rest is left for the next run. The updates the Poster fails to post for good are skipped,
+//	so they do not hold up the rest. Returns model.ErrLeaseTaken if the channel is being posted to by another runner.
+func (d *Dispatcher) Run(ctx context.Context, s *model.Subscriber) (int, error) {
+	start := time.Now()
+	lease, err := d.leaseModel.Acquire(ctx, "autopost:"+s.ID, d.holder, d.LeaseTTL)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := d.leaseModel.Release(ctx, lease); err != nil {
+			log.Printf("[autopost] release lease for %q: %v", s.ID, err)
+		}
+	}()
+
+	var t *Template
+	if len(s.Template) > 0 {
+		// the posts fall back to the default format
+		if t, err = ParseTemplate(s.Template); err != nil {
+			log.Printf("[autopost] parse template of %q: %v", s.UserID, err)
+		}
+	}
+	posted := 0
+	for {
+		ups, err := d.subscriptionModel.GetOldestUnread(ctx, s, d.limit())
+		if err != nil {
+			return posted, fmt.Errorf("get unread updates: %v", err)
+		}
+		for i := range ups {
+			if posted > 0 {
+				if err := d.wait(ctx, d.Interval); err != nil {
+					return posted, err
+				}
+			}
+			err := d.poster.PostUpdate(ctx, s, ups[i], t)
+			if errors.Is(err, ErrPostponed) {
+				return posted, err
+			}
+			if err != nil {
+				log.Printf("[autopost] skip update %q of %q: %v", ups[i].ID, s.UserID, err)
+			} else {
+				posted++
+			}
+			if err := d.subscriptionModel.MarkUpdatesRead(ctx, s, ups[i:i+1]); err != nil {
+				return posted, fmt.Errorf("mark update read: %v", err)
+			}
+		}
+		// the other half of the lease is left for the post in progress
+		if len(ups) < d.limit() || time.Since(start) >= d.LeaseTTL/2 {
+			return posted, nil
+		}
+	}
+}
+
+// limit returns the number of updates posted to a channel per run.
+func (d *Dispatcher) limit() int {
+	if d.Limit <= 0 {
+		return DefaultLimit
+	}
+	return d.Limit
+}
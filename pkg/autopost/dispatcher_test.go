@@ -0,0 +1,197 @@
+package autopost
+
+import (
+	"context"
+	"errors"
+	"github.com/d-ashesss/news-feed-bot/pkg/db/memory"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"sort"
+	"testing"
+	"time"
+)
+
+type testSubscriberModel struct {
+	model.SubscriberModel
+	subscribers []model.Subscriber
+}
+
+func (m *testSubscriberModel) GetAutoPosting(_ context.Context) ([]model.Subscriber, error) {
+	var ss []model.Subscriber
+	for _, s := range m.subscribers {
+		if s.AutoPost {
+			ss = append(ss, s)
+		}
+	}
+	return ss, nil
+}
+
+// testSubscriptionModel keeps the unread updates of the subscribers by their UserID.
+type testSubscriptionModel struct {
+	model.SubscriptionModel
+	unread map[string][]model.Update
+}
+
+func (m *testSubscriptionModel) GetOldestUnread(_ context.Context, s *model.Subscriber, limit int) ([]model.Update, error) {
+	ups := make([]model.Update, len(m.unread[s.UserID]))
+	copy(ups, m.unread[s.UserID])
+	sort.Slice(ups, func(i, j int) bool {
+		return ups[i].Date.Before(ups[j].Date)
+	})
+	if len(ups) > limit {
+		ups = ups[:limit]
+	}
+	return ups, nil
+}
+
+func (m *testSubscriptionModel) MarkUpdatesRead(_ context.Context, s *model.Subscriber, read []model.Update) error {
+	var ups []model.Update
+	for _, up := range m.unread[s.UserID] {
+		if up.ID != read[0].ID {
+			ups = append(ups, up)
+		}
+	}
+	m.unread[s.UserID] = ups
+	return nil
+}
+
+// testPoster fails to post the updates by their ID and records the ones posted along with the templates.
+type testPoster struct {
+	errors    map[string]error
+	posted    []string
+	templates []*Template
+}
+
+func (p *testPoster) PostUpdate(_ context.Context, _ *model.Subscriber, up model.Update, t *Template) error {
+	p.templates = append(p.templates, t)
+	if err := p.errors[up.ID]; err != nil {
+		return err
+	}
+	p.posted = append(p.posted, up.ID)
+	return nil
+}
+
+func newTestDispatcher(poster *testPoster) (*Dispatcher, *testSubscriptionModel) {
+	world, europe := &model.Category{ID: "world"}, &model.Category{ID: "europe"}
+	date := func(h int) time.Time {
+		return time.Date(2000, 1, 1, h, 0, 0, 0, time.UTC)
+	}
+	subscriptionModel := &testSubscriptionModel{unread: map[string][]model.Update{
+		"C1": {
+			{ID: "u3", Category: world, Date: date(3)},
+			{ID: "u1", Category: world, Date: date(1)},
+			{ID: "u2", Category: europe, Date: date(2)},
+		},
+		"C2": {{ID: "u4", Category: world, Date: date(4)}},
+		"C3": {{ID: "u5", Category: world, Date: date(5)}},
+	}}
+	subscriberModel := &testSubscriberModel{subscribers: []model.Subscriber{
+		{ID: "c1", UserID: "C1", Chat: model.ChatChannel, AutoPost: true},
+		{ID: "c2", UserID: "C2", Chat: model.ChatChannel},
+		{ID: "c3", UserID: "C3", Chat: model.ChatGroup, AutoPost: true},
+	}}
+	d := NewDispatcher(subscriberModel, subscriptionModel, memory.NewLeaseModel(), poster, "test")
+	d.wait = func(ctx context.Context, _ time.Duration) error { return ctx.Err() }
+	return d, subscriptionModel
+}
+
+func TestDispatcher_RunAll(t *testing.T) {
+	poster := &testPoster{}
+	d, subscriptionModel := newTestDispatcher(poster)
+	if err := d.RunAll(context.Background()); err != nil {
+		t.Fatalf("RunAll(): %v", err)
+	}
+	if len(poster.posted) != 3 || poster.posted[0] != "u1" || poster.posted[1] != "u2" || poster.posted[2] != "u3" {
+		t.Errorf("RunAll(): got posted %v; want u1, u2, u3 of the auto-posting channel, the oldest first", poster.posted)
+	}
+	if n := len(subscriptionModel.unread["C1"]); n != 0 {
+		t.Errorf("RunAll(): got %d unread updates left; want all posted updates marked read", n)
+	}
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	ctx := context.Background()
+	channel := &model.Subscriber{ID: "c1", UserID: "C1", Chat: model.ChatChannel, AutoPost: true}
+
+	t.Run("batches", func(t *testing.T) {
+		poster := &testPoster{}
+		d, subscriptionModel := newTestDispatcher(poster)
+		d.Limit = 2
+		if n, err := d.Run(ctx, channel); err != nil || n != 3 {
+			t.Fatalf("Run(): got %d, %v; want all 3 posted", n, err)
+		}
+		if left := subscriptionModel.unread["C1"]; len(left) != 0 {
+			t.Errorf("Run(): got unread %v; want the queue drained", left)
+		}
+	})
+
+	t.Run("lease time", func(t *testing.T) {
+		poster := &testPoster{}
+		d, subscriptionModel := newTestDispatcher(poster)
+		d.Limit = 2
+		d.LeaseTTL = time.Nanosecond
+		if n, err := d.Run(ctx, channel); err != nil || n != 2 {
+			t.Fatalf("Run(): got %d, %v; want 2 posted", n, err)
+		}
+		if left := subscriptionModel.unread["C1"]; len(left) != 1 || left[0].ID != "u3" {
+			t.Errorf("Run(): got unread %v; want u3 left for the next run", left)
+		}
+	})
+
+	t.Run("postponed", func(t *testing.T) {
+		poster := &testPoster{errors: map[string]error{"u2": ErrPostponed}}
+		d, subscriptionModel := newTestDispatcher(poster)
+		if n, err := d.Run(ctx, channel); !errors.Is(err, ErrPostponed) || n != 1 {
+			t.Fatalf("Run(): got %d, %v; want 1 posted, ErrPostponed", n, err)
+		}
+		if left := subscriptionModel.unread["C1"]; len(left) != 2 {
+			t.Errorf("Run(): got unread %v; want u2 and u3 kept", left)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		poster := &testPoster{errors: map[string]error{"u2": errors.New("bad request")}}
+		d, subscriptionModel := newTestDispatcher(poster)
+		if n, err := d.Run(ctx, channel); err != nil || n != 2 {
+			t.Fatalf("Run(): got %d, %v; want 2 posted", n, err)
+		}
+		if left := subscriptionModel.unread["C1"]; len(left) != 0 {
+			t.Errorf("Run(): got unread %v; want the failed update skipped", left)
+		}
+	})
+
+	t.Run("template", func(t *testing.T) {
+		poster := &testPoster{}
+		d, _ := newTestDispatcher(poster)
+		s := *channel
+		s.Template = "<b>{{.Title}}</b>"
+		if _, err := d.Run(ctx, &s); err != nil {
+			t.Fatalf("Run(): %v", err)
+		}
+		if len(poster.templates) != 3 || poster.templates[0] == nil || poster.templates[1] != poster.templates[0] || poster.templates[2] != poster.templates[0] {
+			t.Errorf("Run(): got templates %v; want the template parsed once for all the posts", poster.templates)
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		poster := &testPoster{}
+		d, _ := newTestDispatcher(poster)
+		s := *channel
+		s.Template = "{{.Author}}"
+		if n, err := d.Run(ctx, &s); err != nil || n != 3 {
+			t.Fatalf("Run(): got %d, %v; want 3 posted", n, err)
+		}
+		if poster.templates[0] != nil {
+			t.Errorf("Run(): got a template; want the default format")
+		}
+	})
+
+	t.Run("lease taken", func(t *testing.T) {
+		d, _ := newTestDispatcher(&testPoster{})
+		if _, err := d.leaseModel.Acquire(ctx, "autopost:c1", "other", time.Minute); err != nil {
+			t.Fatalf("Acquire(): %v", err)
+		}
+		if _, err := d.Run(ctx, channel); err != model.ErrLeaseTaken {
+			t.Errorf("Run(): got %v; want ErrLeaseTaken", err)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package autopost
+
+import (
+	"errors"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxTemplateLength limits the length of the template of a channel.
+const MaxTemplateLength = 1024
+
+// ErrInvalidTemplate is returned when the template of a channel can not format the updates.
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// Post is an update as seen by the template of a channel.
+type Post struct {
+	Title    string // Title is the title of the update.
+	Summary  string // Summary is the short description of the update.
+	URL      string // URL is the HTTP link to the publication.
+	Image    string // Image is the HTTP link to the illustration of the publication.
+	Category string // Category is the name of the category of the update in the language of the channel.
+	Date     string // Date is the date when the update was published.
+}
+
+// NewPost prepares the update to be formatted by the template, lang is the language of the channel.
+func NewPost(up model.Update, lang string) Post {
+	p := Post{
+		Title:   up.Title,
+		Summary: up.Summary,
+		URL:     up.URL,
+		Image:   up.Image,
+		Date:    up.Date.Format(time.RFC1123),
+	}
+	if up.Category != nil {
+		p.Category = up.Category.LocalName(lang)
+	}
+	return p
+}
+
+// Template formats the updates posted to a channel.
+type Template struct {
+	t *template.Template
+}
+
+// ParseTemplate checks the template of a channel, the fields of Post are available to it as {{.Title}} and so on.
+//
+//	The template produces Telegram HTML markup, the fields are escaped.
+func ParseTemplate(text string) (*Template, error) {
+	if len(strings.TrimSpace(text)) == 0 || utf8.RuneCountInString(text) > MaxTemplateLength {
+		return nil, ErrInvalidTemplate
+	}
+	t, err := template.New("post").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	// references to the unknown fields are only found when the template is executed
+	if err := t.Execute(io.Discard, Post{}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return &Template{t: t}, nil
+}
+
+// Render formats the update, lang is the language of the channel.
+func (t *Template) Render(up model.Update, lang string) (string, error) {
+	var b strings.Builder
+	if err := t.t.Execute(&b, NewPost(up, lang)); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
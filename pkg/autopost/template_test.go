@@ -0,0 +1,56 @@
+package autopost
+
+import (
+	"errors"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		ok   bool
+	}{
+		{name: "fields", text: "<b>{{.Title}}</b>\n{{.URL}}", ok: true},
+		{name: "condition", text: "{{.Title}}{{if .Summary}}\n{{.Summary}}{{end}}", ok: true},
+		{name: "empty", text: " ", ok: false},
+		{name: "too long", text: strings.Repeat("a", MaxTemplateLength+1), ok: false},
+		{name: "syntax", text: "{{.Title", ok: false},
+		{name: "unknown field", text: "{{.Author}}", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate(tt.text)
+			if tt.ok && err != nil {
+				t.Errorf("ParseTemplate(%q): %v", tt.text, err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidTemplate) {
+				t.Errorf("ParseTemplate(%q): got %v; want ErrInvalidTemplate", tt.text, err)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	up := model.Update{
+		Title:    "Fish & Chips <3",
+		URL:      "https://example.com/?a=1&b=2",
+		Date:     time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Category: &model.Category{Name: "Food", Translations: []model.CategoryTranslation{{Lang: "ru", Name: "Еда"}}},
+	}
+	tmpl, err := ParseTemplate("<b>{{.Title}}</b> | {{.Category}}\n{{.URL}}\n")
+	if err != nil {
+		t.Fatalf("ParseTemplate(): %v", err)
+	}
+	got, err := tmpl.Render(up, "ru")
+	if err != nil {
+		t.Fatalf("Render(): %v", err)
+	}
+	want := "<b>Fish &amp; Chips &lt;3</b> | Еда\nhttps://example.com/?a=1&amp;b=2"
+	if got != want {
+		t.Errorf("Render(): got %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,269 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"log"
+	"sort"
+	"time"
+)
+
+// Default delivery settings.
+const (
+	// DefaultRate is the number of messages sent per second, Telegram allows about 30 for bulk notifications.
+	DefaultRate = 25
+	// DefaultLeaseTTL is the time a broadcast stays locked by the runner delivering it between renewals of the lease.
+	DefaultLeaseTTL = 2 * time.Minute
+	// DefaultProgressInterval is the number of subscribers processed between saving the progress.
+	DefaultProgressInterval = 50
+)
+
+// maxRetries limits the attempts to send the message to a subscriber while the sender is rate limited.
+const maxRetries = 3
+
+// ErrBlocked is returned by a Sender when the subscriber can't be reached anymore.
+var ErrBlocked = errors.New("subscriber is unreachable")
+
+// RetryError is returned by a Sender when the message has to be sent again after a while.
+type RetryError struct {
+	After time.Duration // After is the time to wait before the next attempt.
+}
+
+func (e RetryError) Error() string {
+	return fmt.Sprintf("retry after %s", e.After)
+}
+
+// Sender delivers broadcast messages.
+type Sender interface {
+	// SendBroadcast sends the text to the subscriber.
+	SendBroadcast(ctx context.Context, s *model.Subscriber, text string) error
+}
+
+// Dispatcher delivers broadcasts to the subscribers.
+//
+//	The progress is saved every ProgressInterval subscribers, so a delivery interrupted by a restart
+//	is resumed by the next Run without sending the message twice to the most of the subscribers.
+type Dispatcher struct {
+	broadcastModel  model.BroadcastModel
+	subscriberModel model.SubscriberModel
+	categoryModel   model.CategoryModel
+	leaseModel      model.LeaseModel
+	sender          Sender
+	holder          string
+
+	Rate             int           // Rate is the number of messages sent per second.
+	LeaseTTL         time.Duration // LeaseTTL is the time a broadcast stays locked by the runner, the lease is renewed once half of it has passed.
+	ProgressInterval int           // ProgressInterval is the number of subscribers processed between saving the progress.
+
+	now  func() time.Time
+	wait func(ctx context.Context, d time.Duration) error
+}
+
+// NewDispatcher initializes new Dispatcher, holder identifies the runner in the leases of the broadcasts.
+func NewDispatcher(
+	broadcastModel model.BroadcastModel,
+	subscriberModel model.SubscriberModel,
+	categoryModel model.CategoryModel,
+	leaseModel model.LeaseModel,
+	sender Sender,
+	holder string,
+) *Dispatcher {
+	return &Dispatcher{
+		broadcastModel:   broadcastModel,
+		subscriberModel:  subscriberModel,
+		categoryModel:    categoryModel,
+		leaseModel:       leaseModel,
+		sender:           sender,
+		holder:           holder,
+		Rate:             DefaultRate,
+		LeaseTTL:         DefaultLeaseTTL,
+		ProgressInterval: DefaultProgressInterval,
+		now:              time.Now,
+		wait:             Wait,
+	}
+}
+
+// Recipients returns the subscribers the broadcast is yet to be sent to, in the order of their IDs.
+func (d *Dispatcher) Recipients(ctx context.Context, b model.Broadcast) ([]model.Subscriber, error) {
+	ss, err := d.subscriberModel.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get subscribers: %v", err)
+	}
+	var cats []model.Category
+	if len(b.Category) > 0 {
+		if cats, err = d.categoryModel.GetAll(ctx); err != nil {
+			return nil, fmt.Errorf("get categories: %v", err)
+		}
+	}
+	recipients := make([]model.Subscriber, 0, len(ss))
+	for _, s := range ss {
+		if s.ID > b.Cursor && b.Matches(s, cats) {
+			recipients = append(recipients, s)
+		}
+	}
+	sort.Slice(recipients, func(i, j int) bool {
+		return recipients[i].ID < recipients[j].ID
+	})
+	return recipients, nil
+}
+
+// RunAll resumes the delivery of all the broadcasts started but not finished, the oldest first.
+//
+//	The broadcasts delivered by another runner are skipped.
+func (d *Dispatcher) RunAll(ctx context.Context) error {
+	bs, err := d.broadcastModel.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("get broadcasts: %v", err)
+	}
+	var res error
+	for i := len(bs) - 1; i >= 0; i-- {
+		if !bs[i].IsStarted() || bs[i].IsFinished() {
+			continue
+		}
+		if err := d.Run(ctx, &bs[i]); err == model.ErrLeaseTaken {
+			log.Printf("[broadcast] %q is being delivered by another runner", bs[i].ID)
+		} else if err != nil && res == nil {
+			res = fmt.Errorf("deliver %q: %v", bs[i].ID, err)
+		}
+	}
+	return res
+}
+
+// Run delivers the broadcast to the subscribers it is yet to be sent to under its lease.
+//
+//	Returns model.ErrLeaseTaken if the broadcast is being delivered by another runner.
+func (d *Dispatcher) Run(ctx context.Context, b *model.Broadcast) error {
+	if !b.IsStarted() {
+		return model.ErrInvalidBroadcast
+	}
+	lease, err := d.leaseModel.Acquire(ctx, "broadcast:"+b.ID, d.holder, d.LeaseTTL)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := d.leaseModel.Release(ctx, lease); err != nil {
+			log.Printf("[broadcast] release lease for %q: %v", b.ID, err)
+		}
+	}()
+
+	// the broadcast could have made progress with another runner since it was loaded
+	fresh, err := d.broadcastModel.Get(ctx, b.ID)
+	if err != nil {
+		return fmt.Errorf("reload broadcast: %v", err)
+	}
+	*b = *fresh
+	if b.IsFinished() {
+		return nil
+	}
+	recipients, err := d.Recipients(ctx, *b)
+	if err != nil {
+		return err
+	}
+	log.Printf("[broadcast] Delivering %q to %d subscribers", b.ID, len(recipients))
+
+	renewed := d.now()
+	keep := func() error {
+		return d.renewLease(ctx, lease, &renewed)
+	}
+	for i := range recipients {
+		if err := ctx.Err(); err != nil {
+			d.saveInterrupted(b)
+			return err
+		}
+		if err := d.deliver(ctx, b, &recipients[i], keep); err != nil {
+			// the subscriber is left to the runner taking over the broadcast
+			d.saveInterrupted(b)
+			return err
+		}
+		b.Cursor = recipients[i].ID
+		if (i+1)%d.progressInterval() == 0 {
+			if err := d.broadcastModel.Save(ctx, b); err != nil {
+				return fmt.Errorf("save progress: %v", err)
+			}
+		}
+		if err := d.wait(ctx, d.interval()); err != nil {
+			d.saveInterrupted(b)
+			return err
+		}
+	}
+	b.Finished = d.now().UTC()
+	log.Printf("[broadcast] Delivered %q: %d delivered, %d blocked, %d failed", b.ID, b.Delivered, b.Blocked, b.Failed)
+	return d.broadcastModel.Save(ctx, b)
+}
+
+// deliver sends the broadcast to the subscriber counting the outcome, the message is sent again while rate limited.
+//
+//	The lease is kept before every attempt, the error of keeping it is returned without sending the message.
+func (d *Dispatcher) deliver(ctx context.Context, b *model.Broadcast, s *model.Subscriber, keep func() error) error {
+	for attempt := 0; ; attempt++ {
+		if err := keep(); err != nil {
+			return err
+		}
+		err := d.sender.SendBroadcast(ctx, s, b.Text)
+		var retry RetryError
+		switch {
+		case err == nil:
+			b.Delivered++
+			return nil
+		case errors.Is(err, ErrBlocked):
+			b.Blocked++
+			return nil
+		case errors.As(err, &retry) && attempt < maxRetries:
+			if d.wait(ctx, retry.After) == nil {
+				continue
+			}
+		}
+		log.Printf("[broadcast] send %q to %q: %v", b.ID, s.UserID, err)
+		b.Failed++
+		return nil
+	}
+}
+
+// renewLease renews the lease once half of its TTL has passed since it was renewed at the time of renewed.
+func (d *Dispatcher) renewLease(ctx context.Context, lease *model.Lease, renewed *time.Time) error {
+	if d.now().Sub(*renewed) < d.LeaseTTL/2 {
+		return nil
+	}
+	if err := d.leaseModel.Renew(ctx, lease, d.LeaseTTL); err != nil {
+		return err
+	}
+	*renewed = d.now()
+	return nil
+}
+
+// saveInterrupted saves the progress of the broadcast whose delivery was cancelled.
+func (d *Dispatcher) saveInterrupted(b *model.Broadcast) {
+	if err := d.broadcastModel.Save(context.Background(), b); err != nil {
+		log.Printf("[broadcast] save progress of %q: %v", b.ID, err)
+	}
+}
+
+// interval returns the time between two messages.
+func (d *Dispatcher) interval() time.Duration {
+	if d.Rate <= 0 {
+		return time.Second / DefaultRate
+	}
+	return time.Second / time.Duration(d.Rate)
+}
+
+// progressInterval returns the number of subscribers processed between saving the progress.
+func (d *Dispatcher) progressInterval() int {
+	if d.ProgressInterval <= 0 {
+		return DefaultProgressInterval
+	}
+	return d.ProgressInterval
+}
+
+// Wait pauses for the duration unless the context is done.
+func Wait(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
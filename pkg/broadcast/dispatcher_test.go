@@ -0,0 +1,235 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"github.com/d-ashesss/news-feed-bot/pkg/db/memory"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"testing"
+	"time"
+)
+
+type testBroadcastModel struct {
+	model.BroadcastModel
+	broadcasts map[string]model.Broadcast
+	saves      int
+}
+
+func (m *testBroadcastModel) Get(_ context.Context, id string) (*model.Broadcast, error) {
+	b, ok := m.broadcasts[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &b, nil
+}
+
+func (m *testBroadcastModel) GetAll(_ context.Context) ([]model.Broadcast, error) {
+	bs := make([]model.Broadcast, 0, len(m.broadcasts))
+	for _, b := range m.broadcasts {
+		bs = append(bs, b)
+	}
+	return bs, nil
+}
+
+func (m *testBroadcastModel) Save(_ context.Context, b *model.Broadcast) error {
+	m.broadcasts[b.ID] = *b
+	m.saves++
+	return nil
+}
+
+type testSubscriberModel struct {
+	model.SubscriberModel
+	subscribers []model.Subscriber
+}
+
+func (m *testSubscriberModel) GetAll(_ context.Context) ([]model.Subscriber, error) {
+	ss := make([]model.Subscriber, len(m.subscribers))
+	copy(ss, m.subscribers)
+	return ss, nil
+}
+
+type testCategoryModel struct {
+	model.CategoryModel
+}
+
+func (m *testCategoryModel) GetAll(_ context.Context) ([]model.Category, error) {
+	return []model.Category{{ID: "world"}, {ID: "europe", ParentID: "world"}}, nil
+}
+
+// testLeaseModel counts the renewals of the leases and fails them once lost is set.
+type testLeaseModel struct {
+	model.LeaseModel
+	renewed int
+	lost    bool
+}
+
+func (m *testLeaseModel) Renew(ctx context.Context, l *model.Lease, ttl time.Duration) error {
+	if m.lost {
+		return model.ErrLeaseLost
+	}
+	m.renewed++
+	return m.LeaseModel.Renew(ctx, l, ttl)
+}
+
+// testSender fails to send to the subscribers by their UserID and records the ones the message was sent to.
+type testSender struct {
+	errors map[string][]error
+	sent   []string
+	cancel func()
+	onSend func() // onSend is called on every attempt to send the message.
+}
+
+func (s *testSender) SendBroadcast(_ context.Context, sub *model.Subscriber, _ string) error {
+	if s.onSend != nil {
+		s.onSend()
+	}
+	if errs := s.errors[sub.UserID]; len(errs) > 0 {
+		s.errors[sub.UserID] = errs[1:]
+		return errs[0]
+	}
+	s.sent = append(s.sent, sub.UserID)
+	if s.cancel != nil && len(s.sent) == 2 {
+		s.cancel()
+	}
+	return nil
+}
+
+func newTestDispatcher(b model.Broadcast, sender *testSender) (*Dispatcher, *testBroadcastModel) {
+	broadcastModel := &testBroadcastModel{broadcasts: map[string]model.Broadcast{b.ID: b}}
+	subscriberModel := &testSubscriberModel{subscribers: []model.Subscriber{
+		{ID: "s4", UserID: "U4", Categories: []model.Category{{ID: "europe"}}},
+		{ID: "s1", UserID: "U1", Categories: []model.Category{{ID: "world"}}},
+		{ID: "s3", UserID: "U3", Inactive: true},
+		{ID: "s2", UserID: "U2"},
+		{ID: "s5", UserID: "U5", Language: "ru"},
+	}}
+	d := NewDispatcher(broadcastModel, subscriberModel, &testCategoryModel{}, memory.NewLeaseModel(), sender, "test")
+	d.ProgressInterval = 2
+	d.wait = func(ctx context.Context, _ time.Duration) error { return ctx.Err() }
+	return d, broadcastModel
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	ctx := context.Background()
+	started := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("report", func(t *testing.T) {
+		sender := &testSender{errors: map[string][]error{
+			"U2": {RetryError{After: time.Second}},
+			"U4": {ErrBlocked},
+			"U5": {errors.New("bad request")},
+		}}
+		b := model.Broadcast{ID: "b", Text: "hello", Started: started}
+		d, broadcastModel := newTestDispatcher(b, sender)
+		if err := d.Run(ctx, &b); err != nil {
+			t.Fatalf("Run(): %v", err)
+		}
+		if b.Delivered != 2 || b.Blocked != 1 || b.Failed != 1 {
+			t.Errorf("Run(): got %d delivered, %d blocked, %d failed; want 2, 1, 1", b.Delivered, b.Blocked, b.Failed)
+		}
+		if len(sender.sent) != 2 || sender.sent[0] != "U1" || sender.sent[1] != "U2" {
+			t.Errorf("Run(): got sent to %v; want U1 and U2 in the order of IDs", sender.sent)
+		}
+		if saved := broadcastModel.broadcasts["b"]; !saved.IsFinished() || saved.Cursor != "s5" {
+			t.Errorf("Run(): got saved %+v; want finished at s5", saved)
+		}
+	})
+
+	t.Run("segment", func(t *testing.T) {
+		sender := &testSender{}
+		b := model.Broadcast{ID: "b", Text: "hello", Category: "europe", Started: started}
+		d, _ := newTestDispatcher(b, sender)
+		if err := d.Run(ctx, &b); err != nil {
+			t.Fatalf("Run(): %v", err)
+		}
+		if len(sender.sent) != 2 || sender.sent[0] != "U1" || sender.sent[1] != "U4" {
+			t.Errorf("Run(): got sent to %v; want U1 and U4", sender.sent)
+		}
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		sender := &testSender{cancel: cancel}
+		b := model.Broadcast{ID: "b", Text: "hello", Started: started}
+		d, broadcastModel := newTestDispatcher(b, sender)
+		if err := d.Run(cctx, &b); err != context.Canceled {
+			t.Fatalf("Run(): got %v; want context.Canceled", err)
+		}
+		saved := broadcastModel.broadcasts["b"]
+		if saved.IsFinished() || saved.Cursor != "s2" || saved.Delivered != 2 {
+			t.Fatalf("Run(): got saved %+v; want interrupted at s2", saved)
+		}
+
+		if err := d.RunAll(ctx); err != nil {
+			t.Fatalf("RunAll(): %v", err)
+		}
+		if len(sender.sent) != 4 || sender.sent[2] != "U4" {
+			t.Errorf("RunAll(): got sent to %v; want resumed from U4", sender.sent)
+		}
+		if saved := broadcastModel.broadcasts["b"]; !saved.IsFinished() || saved.Delivered != 4 {
+			t.Errorf("RunAll(): got saved %+v; want finished with 4 delivered", saved)
+		}
+	})
+
+	t.Run("renew", func(t *testing.T) {
+		now := started
+		// sending takes 40s, so the lease of 2m is renewed before every second message
+		sender := &testSender{onSend: func() { now = now.Add(40 * time.Second) }}
+		b := model.Broadcast{ID: "b", Text: "hello", Started: started}
+		d, _ := newTestDispatcher(b, sender)
+		leaseModel := &testLeaseModel{LeaseModel: d.leaseModel}
+		d.leaseModel = leaseModel
+		d.ProgressInterval = 100
+		d.now = func() time.Time { return now }
+		if err := d.Run(ctx, &b); err != nil {
+			t.Fatalf("Run(): %v", err)
+		}
+		if leaseModel.renewed != 1 {
+			t.Errorf("Run(): got %d renewals; want 1 for 4 messages taking 2m40s", leaseModel.renewed)
+		}
+	})
+
+	t.Run("lease lost", func(t *testing.T) {
+		now := started
+		b := model.Broadcast{ID: "b", Text: "hello", Started: started}
+		leaseModel := &testLeaseModel{}
+		sender := &testSender{onSend: func() {
+			now = now.Add(40 * time.Second)
+			// another runner takes over while the second message is sent
+			leaseModel.lost = now.Sub(started) > time.Minute
+		}}
+		d, broadcastModel := newTestDispatcher(b, sender)
+		leaseModel.LeaseModel = d.leaseModel
+		d.leaseModel = leaseModel
+		d.ProgressInterval = 100
+		d.now = func() time.Time { return now }
+		if err := d.Run(ctx, &b); err != model.ErrLeaseLost {
+			t.Fatalf("Run(): got %v; want ErrLeaseLost", err)
+		}
+		if len(sender.sent) != 2 {
+			t.Errorf("Run(): got sent to %v; want stopped after U2", sender.sent)
+		}
+		if saved := broadcastModel.broadcasts["b"]; saved.IsFinished() || saved.Cursor != "s2" || saved.Delivered != 2 {
+			t.Errorf("Run(): got saved %+v; want interrupted at s2", saved)
+		}
+	})
+
+	t.Run("not started", func(t *testing.T) {
+		b := model.Broadcast{ID: "b", Text: "hello"}
+		d, _ := newTestDispatcher(b, &testSender{})
+		if err := d.Run(ctx, &b); err != model.ErrInvalidBroadcast {
+			t.Errorf("Run(): got %v; want ErrInvalidBroadcast", err)
+		}
+	})
+
+	t.Run("lease taken", func(t *testing.T) {
+		b := model.Broadcast{ID: "b", Text: "hello", Started: started}
+		d, _ := newTestDispatcher(b, &testSender{})
+		if _, err := d.leaseModel.Acquire(ctx, "broadcast:b", "other", time.Minute); err != nil {
+			t.Fatalf("Acquire(): %v", err)
+		}
+		if err := d.Run(ctx, &b); err != model.ErrLeaseTaken {
+			t.Errorf("Run(): got %v; want ErrLeaseTaken", err)
+		}
+	})
+}
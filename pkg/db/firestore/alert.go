@@ -0,0 +1,85 @@
+package firestore
+
+import (
+	fst "cloud.google.com/go/firestore"
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/jschoedt/go-firestorm"
+	"time"
+)
+
+// alertModel is a Firestore implementation of model.AlertModel.
+type alertModel struct {
+	fsc *firestorm.FSClient // fsc is a Firestore client.
+}
+
+// NewAlertModel initializes Firestore implementation of model.AlertModel.
+func NewAlertModel(c *fst.Client) model.AlertModel {
+	return alertModel{fsc: firestorm.New(c, "ID", "")}
+}
+
+func (m alertModel) Create(ctx context.Context, a *model.Alert) (string, error) {
+	if a == nil || len(a.Phrase) == 0 {
+		return "", model.ErrInvalidAlert
+	}
+	if a.Subscriber == nil || len(a.Subscriber.ID) == 0 {
+		return "", model.ErrInvalidSubscriber
+	}
+	if err := m.req().CreateEntities(ctx, a)(); err != nil {
+		return "", err
+	}
+	return m.req().GetID(a), nil
+}
+
+func (m alertModel) GetAll(ctx context.Context) ([]model.Alert, error) {
+	var as []model.Alert
+	q := m.req().ToCollection(model.Alert{}).Query
+	if err := m.req().SetLoadPaths(firestorm.AllEntities).QueryEntities(ctx, q, &as)(); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+func (m alertModel) GetForSubscriber(ctx context.Context, s *model.Subscriber) ([]model.Alert, error) {
+	if s == nil || len(s.ID) == 0 {
+		return nil, model.ErrInvalidSubscriber
+	}
+	var as []model.Alert
+	q := m.req().ToCollection(model.Alert{}).Where("subscriber", "==", m.req().ToRef(s))
+	if err := m.req().QueryEntities(ctx, q, &as)(); err != nil {
+		return nil, err
+	}
+	for i := range as {
+		as[i].Subscriber = s
+	}
+	return as, nil
+}
+
+func (m alertModel) SetSent(ctx context.Context, a *model.Alert, sent int, windowStart time.Time) error {
+	if a == nil || len(a.ID) == 0 {
+		return model.ErrInvalidAlert
+	}
+	a.Sent = sent
+	a.WindowStart = windowStart
+	return m.req().UpdateEntities(ctx, a)()
+}
+
+func (m alertModel) Delete(ctx context.Context, a *model.Alert) error {
+	if a == nil || len(a.ID) == 0 {
+		return model.ErrInvalidAlert
+	}
+	return m.req().DeleteEntities(ctx, a)()
+}
+
+func (m alertModel) DeleteForSubscriber(ctx context.Context, s *model.Subscriber) error {
+	as, err := m.GetForSubscriber(ctx, s)
+	if err != nil {
+		return err
+	}
+	return m.req().DeleteEntities(ctx, as)()
+}
+
+// req is a shortcut to firestorm.FSClient.NewRequest().
+func (m alertModel) req() *firestorm.Request {
+	return m.fsc.NewRequest()
+}
@@ -0,0 +1,70 @@
+package firestore
+
+import (
+	fst "cloud.google.com/go/firestore"
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/jschoedt/go-firestorm"
+	"sort"
+)
+
+// bookmarkModel is a Firestore implementation of model.BookmarkModel.
+type bookmarkModel struct {
+	fsc *firestorm.FSClient // fsc is a Firestore client.
+}
+
+// NewBookmarkModel initializes Firestore implementation of model.BookmarkModel.
+func NewBookmarkModel(c *fst.Client) model.BookmarkModel {
+	return bookmarkModel{fsc: firestorm.New(c, "ID", "")}
+}
+
+func (m bookmarkModel) Create(ctx context.Context, b *model.Bookmark) (string, error) {
+	if b == nil || len(b.URL) == 0 {
+		return "", model.ErrInvalidBookmark
+	}
+	if b.Subscriber == nil || len(b.Subscriber.ID) == 0 {
+		return "", model.ErrInvalidSubscriber
+	}
+	if err := m.req().CreateEntities(ctx, b)(); err != nil {
+		return "", err
+	}
+	return m.req().GetID(b), nil
+}
+
+func (m bookmarkModel) GetForSubscriber(ctx context.Context, s *model.Subscriber) ([]model.Bookmark, error) {
+	if s == nil || len(s.ID) == 0 {
+		return nil, model.ErrInvalidSubscriber
+	}
+	var bs []model.Bookmark
+	q := m.req().ToCollection(model.Bookmark{}).Where("subscriber", "==", m.req().ToRef(s))
+	if err := m.req().QueryEntities(ctx, q, &bs)(); err != nil {
+		return nil, err
+	}
+	for i := range bs {
+		bs[i].Subscriber = s
+	}
+	sort.Slice(bs, func(i, j int) bool {
+		return bs[i].Saved.After(bs[j].Saved)
+	})
+	return bs, nil
+}
+
+func (m bookmarkModel) Delete(ctx context.Context, b *model.Bookmark) error {
+	if b == nil || len(b.ID) == 0 {
+		return model.ErrInvalidBookmark
+	}
+	return m.req().DeleteEntities(ctx, b)()
+}
+
+func (m bookmarkModel) DeleteForSubscriber(ctx context.Context, s *model.Subscriber) error {
+	bs, err := m.GetForSubscriber(ctx, s)
+	if err != nil {
+		return err
+	}
+	return m.req().DeleteEntities(ctx, bs)()
+}
+
+// req is a shortcut to firestorm.FSClient.NewRequest().
+func (m bookmarkModel) req() *firestorm.Request {
+	return m.fsc.NewRequest()
+}
@@ -0,0 +1,75 @@
+package firestore
+
+import (
+	fst "cloud.google.com/go/firestore"
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/jschoedt/go-firestorm"
+	"sort"
+)
+
+// broadcastModel is a Firestore implementation of model.BroadcastModel.
+type broadcastModel struct {
+	fsc *firestorm.FSClient // fsc is a Firestore client.
+}
+
+// NewBroadcastModel initializes Firestore implementation of model.BroadcastModel.
+func NewBroadcastModel(c *fst.Client) model.BroadcastModel {
+	return broadcastModel{fsc: firestorm.New(c, "ID", "")}
+}
+
+func (m broadcastModel) Create(ctx context.Context, b *model.Broadcast) (string, error) {
+	if b == nil || model.ValidateBroadcastText(b.Text) != nil {
+		return "", model.ErrInvalidBroadcast
+	}
+	if err := m.req().CreateEntities(ctx, b)(); err != nil {
+		return "", err
+	}
+	return m.req().GetID(b), nil
+}
+
+func (m broadcastModel) Get(ctx context.Context, id string) (*model.Broadcast, error) {
+	if id == "" {
+		return nil, model.ErrNotFound
+	}
+	b := &model.Broadcast{ID: id}
+	_, err := m.req().GetEntities(ctx, b)()
+	if _, ok := err.(firestorm.NotFoundError); ok {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (m broadcastModel) GetAll(ctx context.Context) ([]model.Broadcast, error) {
+	var bs []model.Broadcast
+	q := m.req().ToCollection(model.Broadcast{}).Query
+	if err := m.req().QueryEntities(ctx, q, &bs)(); err != nil {
+		return nil, err
+	}
+	sort.Slice(bs, func(i, j int) bool {
+		return bs[i].Created.After(bs[j].Created)
+	})
+	return bs, nil
+}
+
+func (m broadcastModel) Save(ctx context.Context, b *model.Broadcast) error {
+	if b == nil || len(b.ID) == 0 {
+		return model.ErrInvalidBroadcast
+	}
+	return m.req().UpdateEntities(ctx, b)()
+}
+
+func (m broadcastModel) Delete(ctx context.Context, b *model.Broadcast) error {
+	if b == nil || len(b.ID) == 0 {
+		return model.ErrInvalidBroadcast
+	}
+	return m.req().DeleteEntities(ctx, b)()
+}
+
+// req is a shortcut to firestorm.FSClient.NewRequest().
+func (m broadcastModel) req() *firestorm.Request {
+	return m.fsc.NewRequest()
+}
@@ -55,6 +55,24 @@ func (m categoryModel) GetAll(ctx context.Context) ([]model.Category, error) {
 	return cats, nil
 }
 
+func (m categoryModel) Save(ctx context.Context, c *model.Category) error {
+	if c == nil || c.ID == "" {
+		return model.ErrInvalidCategory
+	}
+	if len(c.Name) == 0 {
+		return model.ErrInvalidCategoryName
+	}
+	return m.req().UpdateEntities(ctx, c)()
+}
+
+func (m categoryModel) SetFilter(ctx context.Context, c *model.Category, filter model.Filter) error {
+	if c == nil || c.ID == "" {
+		return model.ErrInvalidCategory
+	}
+	c.Filter = filter
+	return m.req().UpdateEntities(ctx, c)()
+}
+
 func (m categoryModel) Delete(ctx context.Context, c *model.Category) error {
 	if c == nil || c.ID == "" {
 		return model.ErrInvalidCategory
@@ -31,20 +31,50 @@ func (m FeedModel) Create(ctx context.Context, f *model.Feed) (string, error) {
 	return m.req().GetID(f), nil
 }
 
-func (m FeedModel) SetUpdated(ctx context.Context, f *model.Feed, u time.Time) error {
+func (m FeedModel) SetUpdated(ctx context.Context, f *model.Feed, u time.Time, seen []string) error {
 	if f == nil {
 		return model.ErrInvalidFeed
 	}
 	if f.Category == nil || len(f.Category.ID) == 0 {
 		return model.ErrInvalidCategory
 	}
+	if len(seen) > model.MaxSeenGUIDs {
+		seen = seen[:model.MaxSeenGUIDs]
+	}
 	f.LastUpdate = u
+	f.SeenGUIDs = seen
+	if err := m.req().UpdateEntities(ctx, f)(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m FeedModel) SetSchedule(ctx context.Context, f *model.Feed, interval time.Duration, next time.Time) error {
+	if f == nil {
+		return model.ErrInvalidFeed
+	}
+	if f.Category == nil || len(f.Category.ID) == 0 {
+		return model.ErrInvalidCategory
+	}
+	f.Interval = interval
+	f.NextFetch = next
 	if err := m.req().UpdateEntities(ctx, f)(); err != nil {
 		return err
 	}
 	return nil
 }
 
+func (m FeedModel) SetFilter(ctx context.Context, f *model.Feed, filter model.Filter) error {
+	if f == nil {
+		return model.ErrInvalidFeed
+	}
+	if f.Category == nil || len(f.Category.ID) == 0 {
+		return model.ErrInvalidCategory
+	}
+	f.Filter = filter
+	return m.req().UpdateEntities(ctx, f)()
+}
+
 func (m FeedModel) Get(ctx context.Context, cat *model.Category, id string) (*model.Feed, error) {
 	if id == "" {
 		return nil, model.ErrNotFound
@@ -75,6 +105,13 @@ func (m FeedModel) GetAll(ctx context.Context, cat *model.Category) ([]model.Fee
 	return feeds, nil
 }
 
+func (m FeedModel) GetCount(ctx context.Context, cat *model.Category) (int, error) {
+	if cat == nil || len(cat.ID) == 0 {
+		return 0, model.ErrInvalidCategory
+	}
+	return count(ctx, m.req().ToCollection(model.Feed{Category: cat}).Query)
+}
+
 func (m FeedModel) Delete(ctx context.Context, f *model.Feed) error {
 	if f == nil {
 		return model.ErrInvalidFeed
@@ -0,0 +1,101 @@
+package firestore
+
+import (
+	fst "cloud.google.com/go/firestore"
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/jschoedt/go-firestorm"
+	"sort"
+	"time"
+)
+
+// historyModel is a Firestore implementation of model.HistoryModel.
+type historyModel struct {
+	fsc *firestorm.FSClient // fsc is a Firestore client.
+}
+
+// NewHistoryModel initializes Firestore implementation of model.HistoryModel.
+func NewHistoryModel(c *fst.Client) model.HistoryModel {
+	return historyModel{fsc: firestorm.New(c, "ID", "")}
+}
+
+func (m historyModel) Add(ctx context.Context, h *model.HistoryItem) error {
+	if h == nil {
+		return model.ErrInvalidUpdate
+	}
+	if h.Subscriber == nil || len(h.Subscriber.ID) == 0 {
+		return model.ErrInvalidSubscriber
+	}
+	return m.req().CreateEntities(ctx, h)()
+}
+
+func (m historyModel) Get(ctx context.Context, s *model.Subscriber, id string) (*model.HistoryItem, error) {
+	if s == nil || len(s.ID) == 0 {
+		return nil, model.ErrInvalidSubscriber
+	}
+	if len(id) == 0 {
+		return nil, model.ErrNotFound
+	}
+	h := &model.HistoryItem{ID: id}
+	_, err := m.req().SetLoadPaths(firestorm.AllEntities).GetEntities(ctx, h)()
+	if _, ok := err.(firestorm.NotFoundError); ok {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the records of the other subscribers are hidden
+	if h.Subscriber == nil || h.Subscriber.ID != s.ID {
+		return nil, model.ErrNotFound
+	}
+	h.Subscriber = s
+	return h, nil
+}
+
+func (m historyModel) GetForSubscriber(ctx context.Context, s *model.Subscriber) ([]model.HistoryItem, error) {
+	if s == nil || len(s.ID) == 0 {
+		return nil, model.ErrInvalidSubscriber
+	}
+	var hs []model.HistoryItem
+	q := m.req().ToCollection(model.HistoryItem{}).Where("subscriber", "==", m.req().ToRef(s))
+	if err := m.req().QueryEntities(ctx, q, &hs)(); err != nil {
+		return nil, err
+	}
+	for i := range hs {
+		hs[i].Subscriber = s
+	}
+	sort.Slice(hs, func(i, j int) bool {
+		return hs[i].Read.After(hs[j].Read)
+	})
+	return hs, nil
+}
+
+func (m historyModel) Prune(ctx context.Context, s *model.Subscriber, keep int, after time.Time) error {
+	hs, err := m.GetForSubscriber(ctx, s)
+	if err != nil {
+		return err
+	}
+	var pruned []model.HistoryItem
+	for i, h := range hs {
+		if i >= keep || !h.Read.After(after) {
+			pruned = append(pruned, h)
+		}
+	}
+	if len(pruned) == 0 {
+		return nil
+	}
+	return m.req().DeleteEntities(ctx, pruned)()
+}
+
+func (m historyModel) DeleteForSubscriber(ctx context.Context, s *model.Subscriber) error {
+	hs, err := m.GetForSubscriber(ctx, s)
+	if err != nil {
+		return err
+	}
+	return m.req().DeleteEntities(ctx, hs)()
+}
+
+// req is a shortcut to firestorm.FSClient.NewRequest().
+func (m historyModel) req() *firestorm.Request {
+	return m.fsc.NewRequest()
+}
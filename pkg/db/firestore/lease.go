@@ -0,0 +1,123 @@
+package firestore
+
+import (
+	fst "cloud.google.com/go/firestore"
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"time"
+)
+
+// leaseModel is a Firestore implementation of model.LeaseModel.
+//
+//	Leases are read and written in transactions, so they are not mapped with firestorm.
+type leaseModel struct {
+	c *fst.Client // c is a Firestore client.
+}
+
+// leaseDoc is a stored form of model.Lease.
+type leaseDoc struct {
+	Holder  string    `firestore:"holder"`
+	Token   int64     `firestore:"token"`
+	Expires time.Time `firestore:"expires"`
+}
+
+// NewLeaseModel initializes Firestore implementation of model.LeaseModel.
+func NewLeaseModel(c *fst.Client) model.LeaseModel {
+	return leaseModel{c: c}
+}
+
+func (m leaseModel) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (*model.Lease, error) {
+	var l *model.Lease
+	err := m.c.RunTransaction(ctx, func(ctx context.Context, tx *fst.Transaction) error {
+		cur, err := m.get(tx, name)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if cur.Holder != holder && cur.Expires.After(now) {
+			return model.ErrLeaseTaken
+		}
+		next := leaseDoc{Holder: holder, Token: cur.Token + 1, Expires: now.Add(ttl)}
+		if err := tx.Set(m.ref(name), next); err != nil {
+			return err
+		}
+		l = &model.Lease{Name: name, Holder: next.Holder, Token: next.Token, Expires: next.Expires}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (m leaseModel) Renew(ctx context.Context, l *model.Lease, ttl time.Duration) error {
+	expires := time.Now().UTC().Add(ttl)
+	err := m.c.RunTransaction(ctx, func(ctx context.Context, tx *fst.Transaction) error {
+		cur, err := m.held(tx, l)
+		if err != nil {
+			return err
+		}
+		cur.Expires = expires
+		return tx.Set(m.ref(l.Name), cur)
+	})
+	if err != nil {
+		return err
+	}
+	l.Expires = expires
+	return nil
+}
+
+func (m leaseModel) Check(ctx context.Context, l *model.Lease) error {
+	return m.c.RunTransaction(ctx, func(ctx context.Context, tx *fst.Transaction) error {
+		_, err := m.held(tx, l)
+		return err
+	}, fst.ReadOnly)
+}
+
+func (m leaseModel) Release(ctx context.Context, l *model.Lease) error {
+	return m.c.RunTransaction(ctx, func(ctx context.Context, tx *fst.Transaction) error {
+		cur, err := m.held(tx, l)
+		if err != nil {
+			return err
+		}
+		// the document is kept for the fencing token to keep growing
+		cur.Expires = time.Time{}
+		return tx.Set(m.ref(l.Name), cur)
+	})
+}
+
+// held returns the stored lease if it is still held by the owner of l.
+func (m leaseModel) held(tx *fst.Transaction, l *model.Lease) (leaseDoc, error) {
+	if l == nil {
+		return leaseDoc{}, model.ErrLeaseLost
+	}
+	cur, err := m.get(tx, l.Name)
+	if err != nil {
+		return leaseDoc{}, err
+	}
+	if cur.Holder != l.Holder || cur.Token != l.Token || !cur.Expires.After(time.Now().UTC()) {
+		return leaseDoc{}, model.ErrLeaseLost
+	}
+	return cur, nil
+}
+
+// get reads the lease document returning an empty one if it does not exist.
+func (m leaseModel) get(tx *fst.Transaction, name string) (leaseDoc, error) {
+	var cur leaseDoc
+	snaps, err := tx.GetAll([]*fst.DocumentRef{m.ref(name)})
+	if err != nil {
+		return cur, err
+	}
+	if len(snaps) == 0 || !snaps[0].Exists() {
+		return cur, nil
+	}
+	if err := snaps[0].DataTo(&cur); err != nil {
+		return cur, err
+	}
+	return cur, nil
+}
+
+// ref is a reference to the lease document.
+func (m leaseModel) ref(name string) *fst.DocumentRef {
+	return m.c.Collection("Lease").Doc(name)
+}
@@ -5,19 +5,22 @@ import (
 	"context"
 	"github.com/d-ashesss/news-feed-bot/pkg/model"
 	"github.com/jschoedt/go-firestorm"
+	"time"
 )
 
 // subscriberModel is a Firestore implementation of model.SubscriberModel.
 type subscriberModel struct {
-	fsc         *firestorm.FSClient // fsc is a Firestore client.
-	updateModel model.UpdateModel   // updateModel is an implementation of model.UpdateModel.
+	fsc         *firestorm.FSClient         // fsc is a Firestore client.
+	updateModel model.UpdateModel           // updateModel is an implementation of model.UpdateModel.
+	dataModels  []model.SubscriberDataModel // dataModels keep other entities to delete along with the subscriber.
 }
 
 // NewSubscriberModel initializes Firestore implementation of model.SubscriberModel.
-func NewSubscriberModel(c *fst.Client, updateModel model.UpdateModel) model.SubscriberModel {
+func NewSubscriberModel(c *fst.Client, updateModel model.UpdateModel, dataModels ...model.SubscriberDataModel) model.SubscriberModel {
 	return subscriberModel{
 		fsc:         firestorm.New(c, "ID", ""),
 		updateModel: updateModel,
+		dataModels:  dataModels,
 	}
 }
 
@@ -46,6 +49,136 @@ func (m subscriberModel) Get(ctx context.Context, id string) (*model.Subscriber,
 	return &ss[0], nil
 }
 
+func (m subscriberModel) GetAll(ctx context.Context) ([]model.Subscriber, error) {
+	var ss []model.Subscriber
+	q := m.req().ToCollection(model.Subscriber{}).Query
+	if err := m.req().SetLoadPaths(firestorm.AllEntities).QueryEntities(ctx, q, &ss)(); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (m subscriberModel) GetAutoPosting(ctx context.Context) ([]model.Subscriber, error) {
+	var ss []model.Subscriber
+	q := m.req().ToCollection(model.Subscriber{}).Where("autopost", "==", true)
+	if err := m.req().SetLoadPaths(firestorm.AllEntities).QueryEntities(ctx, q, &ss)(); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (m subscriberModel) GetStats(ctx context.Context, since time.Time) (*model.SubscriberStats, error) {
+	c := m.req().ToCollection(model.Subscriber{})
+	var st model.SubscriberStats
+	var inactive int
+	for _, q := range []struct {
+		n *int
+		q fst.Query
+	}{
+		{n: &st.Total, q: c.Query},
+		{n: &inactive, q: c.Where("inactive", "==", true)},
+		{n: &st.Joined, q: c.Where("created", ">=", since)},
+		{n: &st.Seen, q: c.Where("lastseen", ">=", since)},
+	} {
+		n, err := count(ctx, q.q)
+		if err != nil {
+			return nil, err
+		}
+		*q.n = n
+	}
+	// the subscribers saved before the inactive state was introduced do not have the field
+	st.Active = st.Total - inactive
+	return &st, nil
+}
+
+func (m subscriberModel) Save(ctx context.Context, s *model.Subscriber) error {
+	if s == nil || s.ID == "" {
+		return model.ErrInvalidSubscriber
+	}
+	return m.req().UpdateEntities(ctx, s)()
+}
+
+func (m subscriberModel) HasFeedSubscribers(ctx context.Context, f *model.Feed) (bool, error) {
+	if f == nil || f.ID == "" {
+		return false, model.ErrInvalidFeed
+	}
+	q := m.req().ToCollection(model.Subscriber{}).Where("feeds", "array-contains", f.ID).Select().Limit(1)
+	docs, err := q.Documents(ctx).GetAll()
+	if err != nil {
+		return false, err
+	}
+	return len(docs) > 0, nil
+}
+
+func (m subscriberModel) ReplaceFeed(ctx context.Context, from, to *model.Feed) error {
+	if from == nil || from.ID == "" || to == nil || to.ID == "" {
+		return model.ErrInvalidFeed
+	}
+	q := m.req().ToCollection(model.Subscriber{}).Where("feeds", "array-contains", from.ID).Select()
+	docs, err := q.Documents(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+	// each subscriber takes two writes, only the list of the feeds is changed
+	for start := 0; start < len(docs); start += maxBatchSize / 2 {
+		end := start + maxBatchSize/2
+		if end > len(docs) {
+			end = len(docs)
+		}
+		batch := m.fsc.Client.Batch()
+		for _, doc := range docs[start:end] {
+			batch.Update(doc.Ref, []fst.Update{{Path: "feeds", Value: fst.ArrayUnion(to.ID)}})
+			batch.Update(doc.Ref, []fst.Update{{Path: "feeds", Value: fst.ArrayRemove(from.ID)}})
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m subscriberModel) Deactivate(ctx context.Context, s *model.Subscriber, reason string) error {
+	if s == nil || s.ID == "" {
+		return model.ErrInvalidSubscriber
+	}
+	// the caller could hold a stale copy of the subscriber, so only the state fields are written
+	if _, err := m.req().ToRef(s).Update(ctx, []fst.Update{
+		{Path: "inactive", Value: true},
+		{Path: "reason", Value: reason},
+	}); err != nil {
+		return err
+	}
+	s.Deactivate(reason)
+	return nil
+}
+
+func (m subscriberModel) SaveSeen(ctx context.Context, s *model.Subscriber) error {
+	if s == nil || s.ID == "" {
+		return model.ErrInvalidSubscriber
+	}
+	_, err := m.req().ToRef(s).Update(ctx, []fst.Update{
+		{Path: "created", Value: s.Created},
+		{Path: "lastseen", Value: s.LastSeen},
+		{Path: "language", Value: s.Language},
+		{Path: "name", Value: s.Name},
+		{Path: "chat", Value: s.Chat},
+		{Path: "inactive", Value: s.Inactive},
+		{Path: "reason", Value: s.Reason},
+	})
+	return err
+}
+
+func (m subscriberModel) ClearPruned(ctx context.Context, s *model.Subscriber, n int) error {
+	if s == nil || s.ID == "" {
+		return model.ErrInvalidSubscriber
+	}
+	if _, err := m.req().ToRef(s).Update(ctx, []fst.Update{{Path: "pruned", Value: fst.Increment(-n)}}); err != nil {
+		return err
+	}
+	s.Pruned -= n
+	return nil
+}
+
 func (m subscriberModel) Delete(ctx context.Context, s *model.Subscriber) error {
 	if s == nil || s.ID == "" {
 		return model.ErrInvalidSubscriber
@@ -53,6 +186,11 @@ func (m subscriberModel) Delete(ctx context.Context, s *model.Subscriber) error
 	if err := m.updateModel.DeleteForSubscriber(ctx, s); err != nil {
 		return err
 	}
+	for _, dm := range m.dataModels {
+		if err := dm.DeleteForSubscriber(ctx, s); err != nil {
+			return err
+		}
+	}
 	return m.req().DeleteEntities(ctx, s)()
 }
 
@@ -5,6 +5,7 @@ import (
 	"context"
 	"github.com/d-ashesss/news-feed-bot/pkg/model"
 	"github.com/jschoedt/go-firestorm"
+	"time"
 )
 
 // subscriptionModel is a Firestore implementation of model.SubscriptionModel.
@@ -34,7 +35,7 @@ func (m subscriptionModel) Subscribe(ctx context.Context, s *model.Subscriber, c
 	if s == nil || s.ID == "" {
 		return model.ErrInvalidSubscriber
 	}
-	if cat.ID == "" {
+	if cat.ID == "" || cat.Personal {
 		return model.ErrInvalidCategory
 	}
 	sub, err := m.subscriberModel.Get(ctx, s.UserID)
@@ -68,6 +69,43 @@ func (m subscriptionModel) Unsubscribe(ctx context.Context, s *model.Subscriber,
 	return nil
 }
 
+func (m subscriptionModel) SubscribeFeed(ctx context.Context, s *model.Subscriber, f model.Feed) error {
+	return m.updateFeeds(ctx, s, f, (*model.Subscriber).AddFeed)
+}
+
+func (m subscriptionModel) UnsubscribeFeed(ctx context.Context, s *model.Subscriber, f model.Feed) error {
+	return m.updateFeeds(ctx, s, f, (*model.Subscriber).RemoveFeed)
+}
+
+func (m subscriptionModel) MuteFeed(ctx context.Context, s *model.Subscriber, f model.Feed) error {
+	return m.updateFeeds(ctx, s, f, (*model.Subscriber).MuteFeed)
+}
+
+func (m subscriptionModel) UnmuteFeed(ctx context.Context, s *model.Subscriber, f model.Feed) error {
+	return m.updateFeeds(ctx, s, f, (*model.Subscriber).UnmuteFeed)
+}
+
+// updateFeeds applies the change of the Feed to the subscribed and muted feeds of the Subscriber and saves them.
+func (m subscriptionModel) updateFeeds(ctx context.Context, s *model.Subscriber, f model.Feed, change func(*model.Subscriber, model.Feed)) error {
+	if s == nil || s.ID == "" {
+		return model.ErrInvalidSubscriber
+	}
+	if f.ID == "" {
+		return model.ErrInvalidFeed
+	}
+	sub, err := m.subscriberModel.Get(ctx, s.UserID)
+	if err != nil {
+		return model.ErrInvalidSubscriber
+	}
+	change(sub, f)
+	if err := m.req().UpdateEntities(ctx, sub)(); err != nil {
+		return err
+	}
+	s.Feeds = sub.Feeds
+	s.MutedFeeds = sub.MutedFeeds
+	return nil
+}
+
 func (m subscriptionModel) GetCategorySubscription(ctx context.Context, s *model.Subscriber, cat model.Category) (*model.Subscription, error) {
 	if s == nil || s.ID == "" {
 		return nil, model.ErrInvalidSubscriber
@@ -75,17 +113,24 @@ func (m subscriptionModel) GetCategorySubscription(ctx context.Context, s *model
 	if cat.ID == "" {
 		return nil, model.ErrInvalidCategory
 	}
-	subscribed := s.HasCategory(cat)
-	unread, err := m.updateModel.GetCountInCategory(ctx, s, &cat)
+	cats, err := m.categoryModel.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	return &model.Subscription{
-		Category:   cat,
-		Subscribed: subscribed,
-		Unread:     unread,
-	}, nil
+	counted := append([]model.Category{cat}, model.CategoryDescendants(cats, cat)...)
+	subs := make([]model.Subscription, 0, len(counted))
+	for i := range counted {
+		unread, err := m.countUnread(ctx, s, &counted[i], filterChain(s, cats, counted[i]))
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, model.Subscription{Category: counted[i], Subscribed: s.HasCategory(counted[i]), OwnUnread: unread})
+	}
+	for _, parent := range model.CategoryParents(cats, cat) {
+		subs = append(subs, model.Subscription{Category: parent, Subscribed: s.HasCategory(parent)})
+	}
+	model.AggregateSubscriptions(subs)
+	return &subs[0], nil
 }
 
 func (m subscriptionModel) GetSubscriptionStatus(ctx context.Context, s *model.Subscriber) ([]model.Subscription, error) {
@@ -98,24 +143,58 @@ func (m subscriptionModel) GetSubscriptionStatus(ctx context.Context, s *model.S
 	}
 	subs := make([]model.Subscription, len(cats))
 	for i := range cats {
-		if sub, err := m.GetCategorySubscription(ctx, s, cats[i]); err == nil {
-			subs[i] = *sub
+		subs[i] = model.Subscription{Category: cats[i], Subscribed: s.HasCategory(cats[i])}
+		if unread, err := m.countUnread(ctx, s, &cats[i], filterChain(s, cats, cats[i])); err == nil {
+			subs[i].OwnUnread = unread
 		}
 	}
+	model.AggregateSubscriptions(subs)
 	return subs, nil
 }
 
+func (m subscriptionModel) SetFilter(ctx context.Context, s *model.Subscriber, cat model.Category, filter model.Filter) error {
+	if s == nil || s.ID == "" {
+		return model.ErrInvalidSubscriber
+	}
+	if cat.ID == "" {
+		return model.ErrInvalidCategory
+	}
+	// the unread updates are kept, so they are shown again once the filter lets them through
+	s.SetFilter(cat, filter)
+	return m.subscriberModel.Save(ctx, s)
+}
+
 func (m subscriptionModel) AddUpdate(ctx context.Context, up model.Update) error {
 	if up.Category == nil {
 		return model.ErrInvalidCategory
 	}
-	catRef := m.req().ToRef(up.Category)
-	q := m.req().ToCollection(model.Subscriber{}).Where("categories", "array-contains", catRef)
+	cats, err := m.categoryParents(ctx, *up.Category)
+	if err != nil {
+		return err
+	}
+	cats = append([]model.Category{*up.Category}, cats...)
+	catRefs := make([]*fst.DocumentRef, len(cats))
+	for i := range cats {
+		catRefs[i] = m.req().ToRef(&cats[i])
+	}
+	q := m.req().ToCollection(model.Subscriber{}).Where("categories", "array-contains-any", catRefs)
 	var ss []model.Subscriber
 	if err := m.req().QueryEntities(ctx, q, &ss)(); err != nil {
 		return err
 	}
+	source := model.Feed{ID: up.Source}
+	if len(source.ID) > 0 {
+		q := m.req().ToCollection(model.Subscriber{}).Where("feeds", "array-contains", source.ID)
+		var fs []model.Subscriber
+		if err := m.req().QueryEntities(ctx, q, &fs)(); err != nil {
+			return err
+		}
+		ss = appendSubscribers(ss, fs)
+	}
 	for _, s := range ss {
+		if s.Inactive || s.IsMuted(source) || !matchCategoryFilters(s, cats, up) {
+			continue
+		}
 		sup := up
 		sup.Subscriber = &s
 		_, _ = m.updateModel.Create(ctx, &sup)
@@ -124,14 +203,255 @@ func (m subscriptionModel) AddUpdate(ctx context.Context, up model.Update) error
 }
 
 func (m subscriptionModel) ShiftUpdate(ctx context.Context, s *model.Subscriber, cat model.Category) (*model.Update, error) {
-	up, err := m.updateModel.GetFromCategory(ctx, s, &cat)
+	chain, err := m.categoryFilters(ctx, s, cat)
+	if err != nil {
+		return nil, err
+	}
+	if chain == nil {
+		up, err := m.updateModel.GetFromCategory(ctx, s, &cat)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.updateModel.Delete(ctx, up); err != nil {
+			return nil, err
+		}
+		return up, nil
+	}
+	ups, err := m.updateModel.GetAllFromCategory(ctx, s, &cat)
+	if err != nil {
+		return nil, err
+	}
+	ups = filterUpdates(s, chain, ups)
+	if len(ups) == 0 {
+		return nil, model.ErrNoUpdates
+	}
+	if err := m.updateModel.Delete(ctx, &ups[0]); err != nil {
+		return nil, err
+	}
+	return &ups[0], nil
+}
+
+func (m subscriptionModel) GetUnread(ctx context.Context, s *model.Subscriber, cat model.Category) ([]model.Update, error) {
+	chain, err := m.categoryFilters(ctx, s, cat)
+	if err != nil {
+		return nil, err
+	}
+	ups, err := m.updateModel.GetAllFromCategory(ctx, s, &cat)
+	if err != nil {
+		return nil, err
+	}
+	return filterUpdates(s, chain, ups), nil
+}
+
+func (m subscriptionModel) GetUnreadRange(ctx context.Context, s *model.Subscriber, cat model.Category, from, to time.Time) ([]model.Update, error) {
+	chain, err := m.categoryFilters(ctx, s, cat)
+	if err != nil {
+		return nil, err
+	}
+	ups, err := m.updateModel.GetRangeFromCategory(ctx, s, &cat, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return filterUpdates(s, chain, ups), nil
+}
+
+func (m subscriptionModel) GetOldestUnread(ctx context.Context, s *model.Subscriber, limit int) ([]model.Update, error) {
+	if s == nil || len(s.Filters) == 0 {
+		return m.updateModel.GetOldest(ctx, s, limit)
+	}
+	cats, err := m.categoryModel.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the updates hidden by the filters would take the place of the shown ones in a page of the oldest
+	ups, err := m.updateModel.GetOldest(ctx, s, 0)
 	if err != nil {
 		return nil, err
 	}
-	if err := m.updateModel.Delete(ctx, up); err != nil {
+	shown := ups[:0]
+	for _, up := range ups {
+		if up.Category != nil && !matchCategoryFilters(*s, filterChain(s, cats, *up.Category), up) {
+			continue
+		}
+		if shown = append(shown, up); len(shown) == limit {
+			break
+		}
+	}
+	return shown, nil
+}
+
+func (m subscriptionModel) GetUnreadCount(ctx context.Context) (int, error) {
+	return m.updateModel.GetCount(ctx)
+}
+
+func (m subscriptionModel) GetUnreadPage(ctx context.Context, s *model.Subscriber, cat model.Category, offset, limit int) ([]model.Update, int, error) {
+	chain, err := m.categoryFilters(ctx, s, cat)
+	if err != nil {
+		return nil, 0, err
+	}
+	if chain != nil {
+		ups, err := m.updateModel.GetAllFromCategory(ctx, s, &cat)
+		if err != nil {
+			return nil, 0, err
+		}
+		ups = filterUpdates(s, chain, ups)
+		if offset >= len(ups) {
+			return nil, len(ups), nil
+		}
+		end := offset + limit
+		if end > len(ups) {
+			end = len(ups)
+		}
+		return ups[offset:end], len(ups), nil
+	}
+	total, err := m.updateModel.GetCountInCategory(ctx, s, &cat)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset >= total {
+		return nil, total, nil
+	}
+	ups, err := m.updateModel.GetPageFromCategory(ctx, s, &cat, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return ups, total, nil
+}
+
+func (m subscriptionModel) TakeUpdate(ctx context.Context, s *model.Subscriber, id string) (*model.Update, error) {
+	return m.updateModel.Take(ctx, s, id)
+}
+
+func (m subscriptionModel) MarkUpdatesRead(ctx context.Context, s *model.Subscriber, ups []model.Update) error {
+	if s == nil || len(s.ID) == 0 {
+		return model.ErrInvalidSubscriber
+	}
+	for i := range ups {
+		ups[i].Subscriber = s
+	}
+	return m.updateModel.DeleteAll(ctx, ups)
+}
+
+func (m subscriptionModel) Prune(ctx context.Context, s *model.Subscriber, before time.Time, maxUnread int) (int, error) {
+	if s == nil || len(s.ID) == 0 {
+		return 0, model.ErrInvalidSubscriber
+	}
+	pruned, err := m.updateModel.Prune(ctx, s, nil, before, maxUnread)
+	if err != nil || pruned == 0 {
+		return pruned, err
+	}
+	// only the counter is updated, the rest of the subscriber could have been changed since it was loaded
+	if _, err := m.req().ToRef(s).Update(ctx, []fst.Update{{Path: "pruned", Value: fst.Increment(pruned)}}); err != nil {
+		return pruned, err
+	}
+	s.Pruned += pruned
+	return pruned, nil
+}
+
+func (m subscriptionModel) MarkRead(ctx context.Context, s *model.Subscriber, cat *model.Category) (int, error) {
+	return m.updateModel.DeleteFromCategory(ctx, s, cat)
+}
+
+// categoryParents loads the categories the Category is nested in, the closest parent first.
+func (m subscriptionModel) categoryParents(ctx context.Context, cat model.Category) ([]model.Category, error) {
+	var parents []model.Category
+	seen := map[string]bool{cat.ID: true}
+	for id := cat.ParentID; len(id) > 0 && !seen[id] && len(parents) < model.MaxCategoryDepth-1; {
+		parent, err := m.categoryModel.Get(ctx, id)
+		if err == model.ErrNotFound {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		seen[id] = true
+		parents = append(parents, *parent)
+		id = parent.ParentID
+	}
+	return parents, nil
+}
+
+// categoryFilters returns the Category and its parents if the Subscriber has set a Filter for any of them,
+// nil otherwise.
+func (m subscriptionModel) categoryFilters(ctx context.Context, s *model.Subscriber, cat model.Category) ([]model.Category, error) {
+	if s == nil || len(s.Filters) == 0 {
+		return nil, nil
+	}
+	parents, err := m.categoryParents(ctx, cat)
+	if err != nil {
 		return nil, err
 	}
-	return up, nil
+	return withFilters(s, append([]model.Category{cat}, parents...)), nil
+}
+
+// countUnread counts the unread updates of the Subscriber in the Category passing the filters of the chain.
+func (m subscriptionModel) countUnread(ctx context.Context, s *model.Subscriber, cat *model.Category, chain []model.Category) (int, error) {
+	if chain == nil {
+		return m.updateModel.GetCountInCategory(ctx, s, cat)
+	}
+	ups, err := m.updateModel.GetAllFromCategory(ctx, s, cat)
+	if err != nil {
+		return 0, err
+	}
+	return len(filterUpdates(s, chain, ups)), nil
+}
+
+// filterChain returns the Category and its parents from the list if the Subscriber has set a Filter for any of them,
+// nil otherwise.
+func filterChain(s *model.Subscriber, cats []model.Category, cat model.Category) []model.Category {
+	if len(s.Filters) == 0 {
+		return nil
+	}
+	return withFilters(s, append([]model.Category{cat}, model.CategoryParents(cats, cat)...))
+}
+
+// withFilters returns the categories if the Subscriber has set a Filter for any of them, nil otherwise.
+func withFilters(s *model.Subscriber, cats []model.Category) []model.Category {
+	for _, cat := range cats {
+		if !s.GetFilter(cat).IsEmpty() {
+			return cats
+		}
+	}
+	return nil
+}
+
+// filterUpdates keeps the updates passing the Subscriber's filters for all the categories of the chain.
+func filterUpdates(s *model.Subscriber, chain []model.Category, ups []model.Update) []model.Update {
+	if chain == nil {
+		return ups
+	}
+	shown := ups[:0]
+	for _, up := range ups {
+		if matchCategoryFilters(*s, chain, up) {
+			shown = append(shown, up)
+		}
+	}
+	return shown
+}
+
+// appendSubscribers appends the subscribers not yet in the list.
+func appendSubscribers(ss []model.Subscriber, more []model.Subscriber) []model.Subscriber {
+	seen := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		seen[s.ID] = true
+	}
+	for _, s := range more {
+		if !seen[s.ID] {
+			seen[s.ID] = true
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
+
+// matchCategoryFilters tells whether the Update passes the Subscriber's filters for all the categories.
+func matchCategoryFilters(s model.Subscriber, cats []model.Category, up model.Update) bool {
+	for _, cat := range cats {
+		if !s.GetFilter(cat).Match(up) {
+			return false
+		}
+	}
+	return true
 }
 
 // req is a shortcut to firestorm.FSClient.NewRequest().
@@ -0,0 +1,127 @@
+package firestore
+
+import (
+	fst "cloud.google.com/go/firestore"
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/jschoedt/go-firestorm"
+	"sort"
+)
+
+// suggestionModel is a Firestore implementation of model.SuggestionModel.
+type suggestionModel struct {
+	fsc *firestorm.FSClient // fsc is a Firestore client.
+}
+
+// NewSuggestionModel initializes Firestore implementation of model.SuggestionModel.
+func NewSuggestionModel(c *fst.Client) model.SuggestionModel {
+	return suggestionModel{fsc: firestorm.New(c, "ID", "")}
+}
+
+func (m suggestionModel) Create(ctx context.Context, s *model.Suggestion) (string, error) {
+	if s == nil || len(s.URL) == 0 {
+		return "", model.ErrInvalidSuggestion
+	}
+	if s.Subscriber == nil || len(s.Subscriber.ID) == 0 {
+		return "", model.ErrInvalidSubscriber
+	}
+	if len(s.Status) == 0 {
+		s.Status = model.SuggestionPending
+	}
+	if err := m.req().CreateEntities(ctx, s)(); err != nil {
+		return "", err
+	}
+	return m.req().GetID(s), nil
+}
+
+func (m suggestionModel) Get(ctx context.Context, id string) (*model.Suggestion, error) {
+	if id == "" {
+		return nil, model.ErrNotFound
+	}
+	s := &model.Suggestion{ID: id}
+	_, err := m.req().SetLoadPaths(firestorm.AllEntities).GetEntities(ctx, s)()
+	if _, ok := err.(firestorm.NotFoundError); ok {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (m suggestionModel) GetPending(ctx context.Context) ([]model.Suggestion, error) {
+	var ss []model.Suggestion
+	q := m.req().ToCollection(model.Suggestion{}).Where("status", "==", model.SuggestionPending)
+	if err := m.req().SetLoadPaths(firestorm.AllEntities).QueryEntities(ctx, q, &ss)(); err != nil {
+		return nil, err
+	}
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i].Created.Before(ss[j].Created)
+	})
+	return ss, nil
+}
+
+func (m suggestionModel) GetForSubscriber(ctx context.Context, s *model.Subscriber) ([]model.Suggestion, error) {
+	if s == nil || len(s.ID) == 0 {
+		return nil, model.ErrInvalidSubscriber
+	}
+	var ss []model.Suggestion
+	q := m.req().ToCollection(model.Suggestion{}).Where("subscriber", "==", m.req().ToRef(s))
+	if err := m.req().QueryEntities(ctx, q, &ss)(); err != nil {
+		return nil, err
+	}
+	for i := range ss {
+		ss[i].Subscriber = s
+	}
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i].Created.After(ss[j].Created)
+	})
+	return ss, nil
+}
+
+func (m suggestionModel) Save(ctx context.Context, s *model.Suggestion) error {
+	if s == nil || len(s.ID) == 0 {
+		return model.ErrInvalidSuggestion
+	}
+	if s.Subscriber == nil || len(s.Subscriber.ID) == 0 {
+		return model.ErrInvalidSubscriber
+	}
+	return m.req().UpdateEntities(ctx, s)()
+}
+
+func (m suggestionModel) Moderate(ctx context.Context, s *model.Suggestion) error {
+	if s == nil || len(s.ID) == 0 {
+		return model.ErrInvalidSuggestion
+	}
+	if s.Subscriber == nil || len(s.Subscriber.ID) == 0 {
+		return model.ErrInvalidSubscriber
+	}
+	err := m.fsc.DoInTransaction(ctx, func(tctx context.Context) error {
+		// the transaction is retried on conflicts, the stored suggestion is read anew every time
+		stored := &model.Suggestion{ID: s.ID}
+		if _, err := m.req().GetEntities(tctx, stored)(); err != nil {
+			return err
+		}
+		if !stored.IsPending() {
+			return model.ErrSuggestionModerated
+		}
+		return m.req().UpdateEntities(tctx, s)()
+	})
+	if _, ok := err.(firestorm.NotFoundError); ok {
+		return model.ErrNotFound
+	}
+	return err
+}
+
+func (m suggestionModel) DeleteForSubscriber(ctx context.Context, s *model.Subscriber) error {
+	ss, err := m.GetForSubscriber(ctx, s)
+	if err != nil {
+		return err
+	}
+	return m.req().DeleteEntities(ctx, ss)()
+}
+
+// req is a shortcut to firestorm.FSClient.NewRequest().
+func (m suggestionModel) req() *firestorm.Request {
+	return m.fsc.NewRequest()
+}
@@ -2,11 +2,17 @@ package firestore
 
 import (
 	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
 	"context"
+	"fmt"
 	"github.com/d-ashesss/news-feed-bot/pkg/model"
 	"github.com/jschoedt/go-firestorm"
+	"time"
 )
 
+// maxBatchSize is the maximum number of writes in a single Firestore batch.
+const maxBatchSize = 500
+
 // updateModel is a Firestore implementation of model.UpdateModel.
 type updateModel struct {
 	fsc *firestorm.FSClient
@@ -33,6 +39,25 @@ func (m updateModel) Create(ctx context.Context, up *model.Update) (string, erro
 	return m.req().GetID(up), nil
 }
 
+func (m updateModel) Get(ctx context.Context, s *model.Subscriber, id string) (*model.Update, error) {
+	if s == nil || len(s.ID) == 0 {
+		return nil, model.ErrInvalidSubscriber
+	}
+	if len(id) == 0 {
+		return nil, model.ErrNotFound
+	}
+	up := &model.Update{ID: id, Subscriber: s}
+	_, err := m.req().SetLoadPaths(firestorm.AllEntities).GetEntities(ctx, up)()
+	if _, ok := err.(firestorm.NotFoundError); ok {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	up.Subscriber = s
+	return up, nil
+}
+
 func (m updateModel) GetFromCategory(ctx context.Context, s *model.Subscriber, cat *model.Category) (*model.Update, error) {
 	if s == nil || len(s.ID) == 0 {
 		return nil, model.ErrInvalidSubscriber
@@ -55,22 +80,121 @@ func (m updateModel) GetFromCategory(ctx context.Context, s *model.Subscriber, c
 	return &ups[0], nil
 }
 
-func (m updateModel) GetCountInCategory(ctx context.Context, s *model.Subscriber, cat *model.Category) (int, error) {
+func (m updateModel) GetAllFromCategory(ctx context.Context, s *model.Subscriber, cat *model.Category) ([]model.Update, error) {
 	if s == nil || len(s.ID) == 0 {
-		return 0, model.ErrInvalidSubscriber
+		return nil, model.ErrInvalidSubscriber
 	}
 	if cat == nil || len(cat.ID) == 0 {
-		return 0, model.ErrInvalidCategory
+		return nil, model.ErrInvalidCategory
 	}
 	var ups []model.Update
 	catRef := m.req().ToRef(cat)
 	q := m.req().ToCollection(model.Update{Subscriber: s}).
 		Where("category", "==", catRef).
 		OrderBy("date", firestore.Asc)
-	if err := m.req().QueryEntities(ctx, q, &ups)(); err != nil {
-		return 0, err
+	if err := m.req().SetLoadPaths(firestorm.AllEntities).QueryEntities(ctx, q, &ups)(); err != nil {
+		return nil, err
+	}
+	return ups, nil
+}
+
+func (m updateModel) GetPageFromCategory(ctx context.Context, s *model.Subscriber, cat *model.Category, offset, limit int) ([]model.Update, error) {
+	if s == nil || len(s.ID) == 0 {
+		return nil, model.ErrInvalidSubscriber
+	}
+	if cat == nil || len(cat.ID) == 0 {
+		return nil, model.ErrInvalidCategory
+	}
+	var ups []model.Update
+	catRef := m.req().ToRef(cat)
+	q := m.req().ToCollection(model.Update{Subscriber: s}).
+		Where("category", "==", catRef).
+		OrderBy("date", firestore.Asc).
+		Offset(offset).
+		Limit(limit)
+	if err := m.req().SetLoadPaths(firestorm.AllEntities).QueryEntities(ctx, q, &ups)(); err != nil {
+		return nil, err
+	}
+	return ups, nil
+}
+
+func (m updateModel) GetRangeFromCategory(ctx context.Context, s *model.Subscriber, cat *model.Category, from, to time.Time) ([]model.Update, error) {
+	if s == nil || len(s.ID) == 0 {
+		return nil, model.ErrInvalidSubscriber
+	}
+	if cat == nil || len(cat.ID) == 0 {
+		return nil, model.ErrInvalidCategory
+	}
+	var ups []model.Update
+	catRef := m.req().ToRef(cat)
+	q := m.req().ToCollection(model.Update{Subscriber: s}).
+		Where("category", "==", catRef).
+		Where("date", ">=", from).
+		Where("date", "<=", to).
+		OrderBy("date", firestore.Asc)
+	if err := m.req().SetLoadPaths(firestorm.AllEntities).QueryEntities(ctx, q, &ups)(); err != nil {
+		return nil, err
+	}
+	return ups, nil
+}
+
+func (m updateModel) GetOldest(ctx context.Context, s *model.Subscriber, limit int) ([]model.Update, error) {
+	if s == nil || len(s.ID) == 0 {
+		return nil, model.ErrInvalidSubscriber
+	}
+	var ups []model.Update
+	q := m.req().ToCollection(model.Update{Subscriber: s}).
+		OrderBy("date", firestore.Asc)
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if err := m.req().SetLoadPaths(firestorm.AllEntities).QueryEntities(ctx, q, &ups)(); err != nil {
+		return nil, err
 	}
-	return len(ups), nil
+	return ups, nil
+}
+
+func (m updateModel) GetCountInCategory(ctx context.Context, s *model.Subscriber, cat *model.Category) (int, error) {
+	if s == nil || len(s.ID) == 0 {
+		return 0, model.ErrInvalidSubscriber
+	}
+	if cat == nil || len(cat.ID) == 0 {
+		return 0, model.ErrInvalidCategory
+	}
+	catRef := m.req().ToRef(cat)
+	q := m.req().ToCollection(model.Update{Subscriber: s}).Where("category", "==", catRef)
+	return count(ctx, q)
+}
+
+func (m updateModel) GetCount(ctx context.Context) (int, error) {
+	// the updates are kept in a collection of each subscriber named after the entity
+	return count(ctx, m.fsc.Client.CollectionGroup("Update").Query)
+}
+
+func (m updateModel) Take(ctx context.Context, s *model.Subscriber, id string) (*model.Update, error) {
+	if s == nil || len(s.ID) == 0 {
+		return nil, model.ErrInvalidSubscriber
+	}
+	if len(id) == 0 {
+		return nil, model.ErrNotFound
+	}
+	var up *model.Update
+	err := m.fsc.DoInTransaction(ctx, func(tctx context.Context) error {
+		// the transaction is retried on conflicts, the update is read anew every time
+		up = &model.Update{ID: id, Subscriber: s}
+		if _, err := m.req().SetLoadPaths(firestorm.AllEntities).GetEntities(tctx, up)(); err != nil {
+			return err
+		}
+		return m.req().DeleteEntities(tctx, up)()
+	})
+	if _, ok := err.(firestorm.NotFoundError); ok {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	up.Subscriber = s
+	return up, nil
 }
 
 func (m updateModel) Delete(ctx context.Context, up *model.Update) error {
@@ -86,6 +210,108 @@ func (m updateModel) Delete(ctx context.Context, up *model.Update) error {
 	return nil
 }
 
+func (m updateModel) DeleteAll(ctx context.Context, ups []model.Update) error {
+	for _, up := range ups {
+		if len(up.ID) == 0 {
+			return model.ErrInvalidUpdate
+		}
+		if up.Subscriber == nil || len(up.Subscriber.ID) == 0 {
+			return model.ErrInvalidSubscriber
+		}
+	}
+	if len(ups) == 0 {
+		return nil
+	}
+	return m.req().DeleteEntities(ctx, ups)()
+}
+
+func (m updateModel) DeleteFromCategory(ctx context.Context, s *model.Subscriber, cat *model.Category) (int, error) {
+	if s == nil || len(s.ID) == 0 {
+		return 0, model.ErrInvalidSubscriber
+	}
+	if cat != nil && len(cat.ID) == 0 {
+		return 0, model.ErrInvalidCategory
+	}
+	q := m.req().ToCollection(model.Update{Subscriber: s}).Select()
+	if cat != nil {
+		q = q.Where("category", "==", m.req().ToRef(cat))
+	}
+	deleted := 0
+	for {
+		docs, err := q.Limit(maxBatchSize).Documents(ctx).GetAll()
+		if err != nil {
+			return deleted, err
+		}
+		if len(docs) == 0 {
+			return deleted, nil
+		}
+		batch := m.fsc.Client.Batch()
+		for _, doc := range docs {
+			batch.Delete(doc.Ref)
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return deleted, err
+		}
+		deleted += len(docs)
+		if len(docs) < maxBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+func (m updateModel) Prune(ctx context.Context, s *model.Subscriber, cat *model.Category, before time.Time, keep int) (int, error) {
+	if s == nil || len(s.ID) == 0 {
+		return 0, model.ErrInvalidSubscriber
+	}
+	if cat != nil && len(cat.ID) == 0 {
+		return 0, model.ErrInvalidCategory
+	}
+	q := m.req().ToCollection(model.Update{Subscriber: s}).Select("category", "date")
+	if cat != nil {
+		q = q.Where("category", "==", m.req().ToRef(cat))
+	}
+	// a single query for all categories, the updates are counted per category as they come from the newest
+	docs, err := q.OrderBy("date", firestore.Desc).Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	counts := make(map[string]int)
+	var refs []*firestore.DocumentRef
+	for _, doc := range docs {
+		date, _ := doc.Data()["date"].(time.Time)
+		catID := ""
+		if ref, ok := doc.Data()["category"].(*firestore.DocumentRef); ok && ref != nil {
+			catID = ref.ID
+		}
+		counts[catID]++
+		if (keep > 0 && counts[catID] > keep) || (!before.IsZero() && date.Before(before)) {
+			refs = append(refs, doc.Ref)
+		}
+	}
+	return m.deleteRefs(ctx, refs)
+}
+
+// deleteRefs deletes the documents in batches.
+func (m updateModel) deleteRefs(ctx context.Context, refs []*firestore.DocumentRef) (int, error) {
+	deleted := 0
+	for len(refs) > 0 {
+		n := len(refs)
+		if n > maxBatchSize {
+			n = maxBatchSize
+		}
+		batch := m.fsc.Client.Batch()
+		for _, ref := range refs[:n] {
+			batch.Delete(ref)
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return deleted, err
+		}
+		deleted += n
+		refs = refs[n:]
+	}
+	return deleted, nil
+}
+
 func (m updateModel) DeleteForSubscriber(ctx context.Context, s *model.Subscriber) error {
 	if s == nil || len(s.ID) == 0 {
 		return model.ErrInvalidSubscriber
@@ -103,3 +329,16 @@ func (m updateModel) DeleteForSubscriber(ctx context.Context, s *model.Subscribe
 func (m updateModel) req() *firestorm.Request {
 	return m.fsc.NewRequest()
 }
+
+// count runs an aggregation query counting the documents matching the query without reading them.
+func count(ctx context.Context, q firestore.Query) (int, error) {
+	res, err := q.NewAggregationQuery().WithCount("count").Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	v, ok := res["count"].(*firestorepb.Value)
+	if !ok {
+		return 0, fmt.Errorf("unexpected count result %v", res["count"])
+	}
+	return int(v.GetIntegerValue()), nil
+}
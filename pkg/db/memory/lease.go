@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"sync"
+	"time"
+)
+
+// leaseModel is an in-memory implementation of model.LeaseModel.
+//
+//	It only coordinates runners within a single process.
+type leaseModel struct {
+	mu     sync.Mutex
+	leases map[string]model.Lease
+	now    func() time.Time
+}
+
+// NewLeaseModel initializes in-memory implementation of model.LeaseModel.
+func NewLeaseModel() model.LeaseModel {
+	return &leaseModel{
+		leases: make(map[string]model.Lease),
+		now:    time.Now,
+	}
+}
+
+func (m *leaseModel) Acquire(_ context.Context, name, holder string, ttl time.Duration) (*model.Lease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now().UTC()
+	cur := m.leases[name]
+	if cur.Holder != holder && cur.Expires.After(now) {
+		return nil, model.ErrLeaseTaken
+	}
+	l := model.Lease{
+		Name:    name,
+		Holder:  holder,
+		Token:   cur.Token + 1,
+		Expires: now.Add(ttl),
+	}
+	m.leases[name] = l
+	return &l, nil
+}
+
+func (m *leaseModel) Renew(_ context.Context, l *model.Lease, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cur, err := m.held(l)
+	if err != nil {
+		return err
+	}
+	cur.Expires = m.now().UTC().Add(ttl)
+	m.leases[l.Name] = cur
+	l.Expires = cur.Expires
+	return nil
+}
+
+func (m *leaseModel) Check(_ context.Context, l *model.Lease) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := m.held(l)
+	return err
+}
+
+func (m *leaseModel) Release(_ context.Context, l *model.Lease) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cur, err := m.held(l)
+	if err != nil {
+		return err
+	}
+	cur.Expires = time.Time{}
+	m.leases[l.Name] = cur
+	return nil
+}
+
+// held returns the stored lease if it is still held by the owner of l.
+func (m *leaseModel) held(l *model.Lease) (model.Lease, error) {
+	if l == nil {
+		return model.Lease{}, model.ErrLeaseLost
+	}
+	cur, ok := m.leases[l.Name]
+	if !ok || cur.Holder != l.Holder || cur.Token != l.Token || !cur.Expires.After(m.now().UTC()) {
+		return model.Lease{}, model.ErrLeaseLost
+	}
+	return cur, nil
+}
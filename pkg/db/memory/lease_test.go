@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLeaseModel(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewLeaseModel().(*leaseModel)
+	m.now = func() time.Time { return now }
+
+	l1, err := m.Acquire(ctx, "feed", "runner-1", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire(runner-1): %v", err)
+	}
+
+	t.Run("taken", func(t *testing.T) {
+		if _, err := m.Acquire(ctx, "feed", "runner-2", time.Minute); err != model.ErrLeaseTaken {
+			t.Errorf("Acquire(runner-2): got %v; want ErrLeaseTaken", err)
+		}
+	})
+
+	t.Run("other resource", func(t *testing.T) {
+		if _, err := m.Acquire(ctx, "other", "runner-2", time.Minute); err != nil {
+			t.Errorf("Acquire(runner-2): %v", err)
+		}
+	})
+
+	t.Run("renew", func(t *testing.T) {
+		now = now.Add(30 * time.Second)
+		if err := m.Renew(ctx, l1, time.Minute); err != nil {
+			t.Fatalf("Renew(runner-1): %v", err)
+		}
+		now = now.Add(45 * time.Second)
+		if err := m.Check(ctx, l1); err != nil {
+			t.Errorf("Check(runner-1): %v", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		if err := m.Check(ctx, l1); err != model.ErrLeaseLost {
+			t.Errorf("Check(runner-1): got %v; want ErrLeaseLost", err)
+		}
+		l2, err := m.Acquire(ctx, "feed", "runner-2", time.Minute)
+		if err != nil {
+			t.Fatalf("Acquire(runner-2): %v", err)
+		}
+		if l2.Token <= l1.Token {
+			t.Errorf("Acquire(runner-2): got token %d; want greater than %d", l2.Token, l1.Token)
+		}
+		if err := m.Release(ctx, l1); err != model.ErrLeaseLost {
+			t.Errorf("Release(runner-1): got %v; want ErrLeaseLost", err)
+		}
+		if err := m.Release(ctx, l2); err != nil {
+			t.Errorf("Release(runner-2): %v", err)
+		}
+	})
+
+	t.Run("released", func(t *testing.T) {
+		l3, err := m.Acquire(ctx, "feed", "runner-3", time.Minute)
+		if err != nil {
+			t.Fatalf("Acquire(runner-3): %v", err)
+		}
+		if l3.Token != 3 {
+			t.Errorf("Acquire(runner-3): got token %d; want 3", l3.Token)
+		}
+	})
+}
+
+func TestLeaseModel_ConcurrentRunners(t *testing.T) {
+	ctx := context.Background()
+	m := NewLeaseModel()
+
+	var holders int32
+	var lastToken int64
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(runner string) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				l, err := m.Acquire(ctx, "feed", runner, time.Minute)
+				if err == model.ErrLeaseTaken {
+					continue
+				}
+				if err != nil {
+					t.Errorf("Acquire(%s): %v", runner, err)
+					return
+				}
+				if n := atomic.AddInt32(&holders, 1); n != 1 {
+					t.Errorf("got %d simultaneous holders", n)
+				}
+				if prev := atomic.SwapInt64(&lastToken, l.Token); prev >= l.Token {
+					t.Errorf("got token %d after %d", l.Token, prev)
+				}
+				atomic.AddInt32(&holders, -1)
+				if err := m.Release(ctx, l); err != nil {
+					t.Errorf("Release(%s): %v", runner, err)
+				}
+			}
+		}(fmt.Sprintf("runner-%d", i))
+	}
+	wg.Wait()
+	if lastToken == 0 {
+		t.Errorf("lease was never acquired")
+	}
+}
@@ -0,0 +1,155 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LeaseSchema creates the table used by the SQL implementation of model.LeaseModel.
+const LeaseSchema = `CREATE TABLE IF NOT EXISTS leases (
+	name    VARCHAR(255) PRIMARY KEY,
+	holder  VARCHAR(255) NOT NULL,
+	token   BIGINT       NOT NULL,
+	expires BIGINT       NOT NULL
+)`
+
+// leaseModel is an SQL implementation of model.LeaseModel.
+//
+//	Expiration time is stored as Unix nanoseconds to stay portable across the databases.
+type leaseModel struct {
+	db     *dbsql.DB        // db is a database handle.
+	dollar bool             // dollar shows if the driver uses $N placeholders instead of ?.
+	now    func() time.Time // now returns the current time.
+}
+
+// NewLeaseModel initializes SQL implementation of model.LeaseModel.
+//
+//	The driver name is only used to pick the placeholder style.
+func NewLeaseModel(db *dbsql.DB, driver string) model.LeaseModel {
+	return leaseModel{
+		db:     db,
+		dollar: driver == "postgres" || driver == "pgx",
+		now:    time.Now,
+	}
+}
+
+func (m leaseModel) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (*model.Lease, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := m.now().UTC()
+	l := &model.Lease{Name: name, Holder: holder, Expires: now.Add(ttl)}
+	var curHolder string
+	var curToken, curExpires int64
+	err = tx.QueryRowContext(ctx, m.q("SELECT holder, token, expires FROM leases WHERE name = ?"), name).
+		Scan(&curHolder, &curToken, &curExpires)
+	switch {
+	case err == dbsql.ErrNoRows:
+		l.Token = 1
+		// a failed insert means a concurrent runner has created the lease first
+		if _, err := tx.ExecContext(ctx, m.q("INSERT INTO leases (name, holder, token, expires) VALUES (?, ?, ?, ?)"),
+			name, holder, l.Token, l.Expires.UnixNano()); err != nil {
+			return nil, model.ErrLeaseTaken
+		}
+	case err != nil:
+		return nil, err
+	default:
+		if curHolder != holder && curExpires > now.UnixNano() {
+			return nil, model.ErrLeaseTaken
+		}
+		l.Token = curToken + 1
+		res, err := tx.ExecContext(ctx, m.q("UPDATE leases SET holder = ?, token = ?, expires = ? WHERE name = ? AND token = ?"),
+			holder, l.Token, l.Expires.UnixNano(), name, curToken)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return nil, model.ErrLeaseTaken
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (m leaseModel) Renew(ctx context.Context, l *model.Lease, ttl time.Duration) error {
+	if l == nil {
+		return model.ErrLeaseLost
+	}
+	now := m.now().UTC()
+	expires := now.Add(ttl)
+	res, err := m.db.ExecContext(ctx, m.q("UPDATE leases SET expires = ? WHERE name = ? AND holder = ? AND token = ? AND expires > ?"),
+		expires.UnixNano(), l.Name, l.Holder, l.Token, now.UnixNano())
+	if err := affected(res, err); err != nil {
+		return err
+	}
+	l.Expires = expires
+	return nil
+}
+
+func (m leaseModel) Check(ctx context.Context, l *model.Lease) error {
+	if l == nil {
+		return model.ErrLeaseLost
+	}
+	var n int
+	err := m.db.QueryRowContext(ctx, m.q("SELECT COUNT(*) FROM leases WHERE name = ? AND holder = ? AND token = ? AND expires > ?"),
+		l.Name, l.Holder, l.Token, m.now().UTC().UnixNano()).Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return model.ErrLeaseLost
+	}
+	return nil
+}
+
+func (m leaseModel) Release(ctx context.Context, l *model.Lease) error {
+	if l == nil {
+		return model.ErrLeaseLost
+	}
+	// the row is kept for the fencing token to keep growing
+	res, err := m.db.ExecContext(ctx, m.q("UPDATE leases SET expires = 0 WHERE name = ? AND holder = ? AND token = ? AND expires > ?"),
+		l.Name, l.Holder, l.Token, m.now().UTC().UnixNano())
+	return affected(res, err)
+}
+
+// q adapts placeholders of the query to the driver.
+func (m leaseModel) q(query string) string {
+	if !m.dollar {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// affected converts an update of no rows into model.ErrLeaseLost.
+func affected(res dbsql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return model.ErrLeaseLost
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"io"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testLease is a row of the leases table.
+type testLease struct {
+	holder  string
+	token   int64
+	expires int64
+}
+
+// testStore is an in-memory leases table, the transactions are serialized like in a database with table locks.
+type testStore struct {
+	tx     sync.Mutex // tx is held by an open transaction.
+	mu     sync.Mutex // mu guards the rows.
+	leases map[string]testLease
+}
+
+// testConnector opens the connections to the store.
+type testConnector struct {
+	store *testStore
+}
+
+func (c testConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return &testConn{store: c.store}, nil
+}
+
+func (c testConnector) Driver() driver.Driver {
+	return testDriver{}
+}
+
+type testDriver struct{}
+
+func (testDriver) Open(_ string) (driver.Conn, error) {
+	return nil, errors.New("not supported")
+}
+
+// testConn executes the queries of leaseModel against the store.
+type testConn struct {
+	store    *testStore
+	snapshot map[string]testLease // snapshot is the state of the table before the open transaction.
+}
+
+func (c *testConn) Prepare(query string) (driver.Stmt, error) {
+	return &testStmt{conn: c, query: testPlaceholderRx.ReplaceAllString(query, "?")}, nil
+}
+
+func (c *testConn) Close() error {
+	return nil
+}
+
+func (c *testConn) Begin() (driver.Tx, error) {
+	c.store.tx.Lock()
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	c.snapshot = make(map[string]testLease, len(c.store.leases))
+	for k, v := range c.store.leases {
+		c.snapshot[k] = v
+	}
+	return c, nil
+}
+
+func (c *testConn) Commit() error {
+	c.snapshot = nil
+	c.store.tx.Unlock()
+	return nil
+}
+
+func (c *testConn) Rollback() error {
+	c.store.mu.Lock()
+	c.store.leases = c.snapshot
+	c.store.mu.Unlock()
+	c.snapshot = nil
+	c.store.tx.Unlock()
+	return nil
+}
+
+var testPlaceholderRx = regexp.MustCompile(`\$\d+`)
+
+type testStmt struct {
+	conn  *testConn
+	query string
+}
+
+func (s *testStmt) Close() error {
+	return nil
+}
+
+func (s *testStmt) NumInput() int {
+	return -1
+}
+
+func (s *testStmt) Exec(args []driver.Value) (driver.Result, error) {
+	unlock := s.lock()
+	defer unlock()
+	leases := s.conn.store.leases
+	switch s.query {
+	case "INSERT INTO leases (name, holder, token, expires) VALUES (?, ?, ?, ?)":
+		name := args[0].(string)
+		if _, ok := leases[name]; ok {
+			return nil, errors.New("duplicate key")
+		}
+		leases[name] = testLease{holder: args[1].(string), token: args[2].(int64), expires: args[3].(int64)}
+		return driver.RowsAffected(1), nil
+	case "UPDATE leases SET holder = ?, token = ?, expires = ? WHERE name = ? AND token = ?":
+		name := args[3].(string)
+		if l, ok := leases[name]; !ok || l.token != args[4].(int64) {
+			return driver.RowsAffected(0), nil
+		}
+		leases[name] = testLease{holder: args[0].(string), token: args[1].(int64), expires: args[2].(int64)}
+		return driver.RowsAffected(1), nil
+	case "UPDATE leases SET expires = ? WHERE name = ? AND holder = ? AND token = ? AND expires > ?":
+		name := args[1].(string)
+		l, ok := leases[name]
+		if !ok || !l.held(args[2:]) {
+			return driver.RowsAffected(0), nil
+		}
+		l.expires = args[0].(int64)
+		leases[name] = l
+		return driver.RowsAffected(1), nil
+	case "UPDATE leases SET expires = 0 WHERE name = ? AND holder = ? AND token = ? AND expires > ?":
+		name := args[0].(string)
+		l, ok := leases[name]
+		if !ok || !l.held(args[1:]) {
+			return driver.RowsAffected(0), nil
+		}
+		l.expires = 0
+		leases[name] = l
+		return driver.RowsAffected(1), nil
+	}
+	return nil, fmt.Errorf("unexpected query %q", s.query)
+}
+
+func (s *testStmt) Query(args []driver.Value) (driver.Rows, error) {
+	unlock := s.lock()
+	defer unlock()
+	leases := s.conn.store.leases
+	switch s.query {
+	case "SELECT holder, token, expires FROM leases WHERE name = ?":
+		l, ok := leases[args[0].(string)]
+		if !ok {
+			return &testRows{columns: []string{"holder", "token", "expires"}}, nil
+		}
+		return &testRows{columns: []string{"holder", "token", "expires"}, rows: [][]driver.Value{{l.holder, l.token, l.expires}}}, nil
+	case "SELECT COUNT(*) FROM leases WHERE name = ? AND holder = ? AND token = ? AND expires > ?":
+		var n int64
+		if l, ok := leases[args[0].(string)]; ok && l.held(args[1:]) {
+			n = 1
+		}
+		return &testRows{columns: []string{"count"}, rows: [][]driver.Value{{n}}}, nil
+	}
+	return nil, fmt.Errorf("unexpected query %q", s.query)
+}
+
+// lock serializes the statement with the transactions unless it runs in one.
+func (s *testStmt) lock() func() {
+	if s.conn.snapshot == nil {
+		s.conn.store.tx.Lock()
+		s.conn.store.mu.Lock()
+		return func() {
+			s.conn.store.mu.Unlock()
+			s.conn.store.tx.Unlock()
+		}
+	}
+	s.conn.store.mu.Lock()
+	return s.conn.store.mu.Unlock
+}
+
+// held matches the lease against the holder, token and time arguments of the query.
+func (l testLease) held(args []driver.Value) bool {
+	return l.holder == args[0].(string) && l.token == args[1].(int64) && l.expires > args[2].(int64)
+}
+
+type testRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *testRows) Columns() []string {
+	return r.columns
+}
+
+func (r *testRows) Close() error {
+	return nil
+}
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func newTestDB() *dbsql.DB {
+	return dbsql.OpenDB(testConnector{store: &testStore{leases: make(map[string]testLease)}})
+}
+
+func TestLeaseModel(t *testing.T) {
+	for _, driverName := range []string{"mysql", "postgres"} {
+		t.Run(driverName, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+			db := newTestDB()
+			defer func() { _ = db.Close() }()
+			m := NewLeaseModel(db, driverName).(leaseModel)
+			m.now = func() time.Time { return now }
+
+			l1, err := m.Acquire(ctx, "feed", "runner-1", time.Minute)
+			if err != nil {
+				t.Fatalf("Acquire(runner-1): %v", err)
+			}
+
+			t.Run("taken", func(t *testing.T) {
+				if _, err := m.Acquire(ctx, "feed", "runner-2", time.Minute); err != model.ErrLeaseTaken {
+					t.Errorf("Acquire(runner-2): got %v; want ErrLeaseTaken", err)
+				}
+			})
+
+			t.Run("other resource", func(t *testing.T) {
+				if _, err := m.Acquire(ctx, "other", "runner-2", time.Minute); err != nil {
+					t.Errorf("Acquire(runner-2): %v", err)
+				}
+			})
+
+			t.Run("renew", func(t *testing.T) {
+				now = now.Add(30 * time.Second)
+				if err := m.Renew(ctx, l1, time.Minute); err != nil {
+					t.Fatalf("Renew(runner-1): %v", err)
+				}
+				now = now.Add(45 * time.Second)
+				if err := m.Check(ctx, l1); err != nil {
+					t.Errorf("Check(runner-1): %v", err)
+				}
+			})
+
+			t.Run("expired", func(t *testing.T) {
+				now = now.Add(time.Minute)
+				if err := m.Check(ctx, l1); err != model.ErrLeaseLost {
+					t.Errorf("Check(runner-1): got %v; want ErrLeaseLost", err)
+				}
+				if err := m.Renew(ctx, l1, time.Minute); err != model.ErrLeaseLost {
+					t.Errorf("Renew(runner-1): got %v; want ErrLeaseLost", err)
+				}
+				l2, err := m.Acquire(ctx, "feed", "runner-2", time.Minute)
+				if err != nil {
+					t.Fatalf("Acquire(runner-2): %v", err)
+				}
+				if l2.Token <= l1.Token {
+					t.Errorf("Acquire(runner-2): got token %d; want greater than %d", l2.Token, l1.Token)
+				}
+				if err := m.Release(ctx, l1); err != model.ErrLeaseLost {
+					t.Errorf("Release(runner-1): got %v; want ErrLeaseLost", err)
+				}
+				if err := m.Release(ctx, l2); err != nil {
+					t.Errorf("Release(runner-2): %v", err)
+				}
+			})
+
+			t.Run("released", func(t *testing.T) {
+				l3, err := m.Acquire(ctx, "feed", "runner-3", time.Minute)
+				if err != nil {
+					t.Fatalf("Acquire(runner-3): %v", err)
+				}
+				if l3.Token != 3 {
+					t.Errorf("Acquire(runner-3): got token %d; want 3", l3.Token)
+				}
+			})
+		})
+	}
+}
+
+func TestLeaseModel_ConcurrentRunners(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB()
+	defer func() { _ = db.Close() }()
+	m := NewLeaseModel(db, "postgres")
+
+	var holders int32
+	var lastToken int64
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(runner string) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				l, err := m.Acquire(ctx, "feed", runner, time.Minute)
+				if err == model.ErrLeaseTaken {
+					continue
+				}
+				if err != nil {
+					t.Errorf("Acquire(%s): %v", runner, err)
+					return
+				}
+				if n := atomic.AddInt32(&holders, 1); n != 1 {
+					t.Errorf("got %d simultaneous holders", n)
+				}
+				if prev := atomic.SwapInt64(&lastToken, l.Token); prev >= l.Token {
+					t.Errorf("got token %d after %d", l.Token, prev)
+				}
+				atomic.AddInt32(&holders, -1)
+				if err := m.Release(ctx, l); err != nil {
+					t.Errorf("Release(%s): %v", runner, err)
+				}
+			}
+		}(fmt.Sprintf("runner-%d", i))
+	}
+	wg.Wait()
+	if lastToken == 0 {
+		t.Errorf("lease was never acquired")
+	}
+}
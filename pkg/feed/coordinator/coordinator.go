@@ -0,0 +1,151 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/feed/fetcher"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"log"
+	"net/http"
+	"time"
+)
+
+// DefaultLeaseTTL is the time a feed stays locked by a runner unless configured otherwise.
+const DefaultLeaseTTL = 5 * time.Minute
+
+// Coordinator fetches the feeds that are due making sure each feed is fetched by a single runner at a time.
+type Coordinator struct {
+	feedModel         model.FeedModel
+	categoryModel     model.CategoryModel
+	subscriptionModel model.SubscriptionModel
+	leaseModel        model.LeaseModel
+	holder            string
+
+	Bounds   fetcher.Bounds   // Bounds limit adaptive polling intervals of the feeds.
+	LeaseTTL time.Duration    // LeaseTTL is the time a feed stays locked by the runner.
+	Listener fetcher.Listener // Listener is an optional listener of ingested updates.
+	Client   *http.Client     // Client downloads the feeds, fetcher.DefaultClient if nil.
+}
+
+// New instantiates new Coordinator. Holder identifies the runner when taking leases.
+func New(
+	feedModel model.FeedModel,
+	categoryModel model.CategoryModel,
+	subscriptionModel model.SubscriptionModel,
+	leaseModel model.LeaseModel,
+	holder string,
+) *Coordinator {
+	return &Coordinator{
+		feedModel:         feedModel,
+		categoryModel:     categoryModel,
+		subscriptionModel: subscriptionModel,
+		leaseModel:        leaseModel,
+		holder:            holder,
+		Bounds:            fetcher.DefaultBounds,
+		LeaseTTL:          DefaultLeaseTTL,
+	}
+}
+
+// FetchAll fetches updates from the feeds of all categories.
+//
+//	Feeds that are not due are skipped unless force is set.
+func (c *Coordinator) FetchAll(ctx context.Context, force bool) error {
+	cats, err := c.categoryModel.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("get categories: %v", err)
+	}
+	for _, cat := range cats {
+		c.FetchCategory(ctx, &cat, force)
+	}
+	return nil
+}
+
+// FetchCategory fetches updates from the feeds of the category.
+//
+//	Feeds that are not due are skipped unless force is set.
+func (c *Coordinator) FetchCategory(ctx context.Context, cat *model.Category, force bool) {
+	feeds, err := c.feedModel.GetAll(ctx, cat)
+	if err != nil {
+		log.Printf("[coordinator] get feeds in %q: %s", cat.Name, err)
+		return
+	}
+	f := fetcher.New(c.subscriptionModel)
+	f.Listener = c.Listener
+	f.Client = c.Client
+	for _, feed := range feeds {
+		if !force && !feed.IsDue(time.Now().UTC()) {
+			continue
+		}
+		c.fetchFeed(ctx, f, feed, cat, force)
+	}
+}
+
+// fetchFeed fetches a single feed under its lease.
+func (c *Coordinator) fetchFeed(ctx context.Context, f *fetcher.Fetcher, feed model.Feed, cat *model.Category, force bool) {
+	lease, err := c.leaseModel.Acquire(ctx, "feed:"+feed.ID, c.holder, c.LeaseTTL)
+	if err == model.ErrLeaseTaken {
+		log.Printf("[coordinator] feed %q is being fetched by another runner", feed.Title)
+		return
+	}
+	if err != nil {
+		log.Printf("[coordinator] acquire lease for %q: %v", feed.Title, err)
+		return
+	}
+	defer func() {
+		if err := c.leaseModel.Release(ctx, lease); err != nil {
+			log.Printf("[coordinator] release lease for %q: %v", feed.Title, err)
+		}
+	}()
+
+	// the feed could have been fetched by another runner since it was listed
+	fd, err := c.feedModel.Get(ctx, cat, feed.ID)
+	if err != nil {
+		log.Printf("[coordinator] reload feed %q: %v", feed.Title, err)
+		return
+	}
+	now := time.Now().UTC()
+	if !force && !fd.IsDue(now) {
+		return
+	}
+
+	interval := fd.GetInterval()
+	// the download and the delivery could outlive the lease, it is kept before every update is delivered
+	renewed := time.Now()
+	fenced := *f
+	fenced.Fence = func(ctx context.Context) error {
+		return c.renewLease(ctx, lease, &renewed)
+	}
+	res, err := fenced.Fetch(ctx, fd, cat)
+	if err != nil {
+		log.Printf("[coordinator] fetch updates from %q: %v", fd.Title, err)
+	} else {
+		interval = fetcher.NextInterval(interval, res, c.Bounds)
+	}
+
+	// fencing: never move the feed state forward once the lease is lost
+	if err := c.leaseModel.Check(ctx, lease); err != nil {
+		log.Printf("[coordinator] lease for %q: %v", fd.Title, err)
+		return
+	}
+	// the high-water mark is only advanced after a successful fetch
+	if res != nil {
+		if err := c.feedModel.SetUpdated(ctx, fd, res.LastUpdate, res.Seen); err != nil {
+			log.Printf("[coordinator] update feed %q: %v", fd.Title, err)
+		}
+	}
+	if err := c.feedModel.SetSchedule(ctx, fd, interval, now.Add(interval)); err != nil {
+		log.Printf("[coordinator] schedule feed %q: %v", fd.Title, err)
+	}
+}
+
+// renewLease renews the lease once half of its TTL has passed since it was renewed at the time of renewed.
+func (c *Coordinator) renewLease(ctx context.Context, lease *model.Lease, renewed *time.Time) error {
+	if time.Since(*renewed) < c.LeaseTTL/2 {
+		return nil
+	}
+	if err := c.leaseModel.Renew(ctx, lease, c.LeaseTTL); err != nil {
+		return err
+	}
+	*renewed = time.Now()
+	return nil
+}
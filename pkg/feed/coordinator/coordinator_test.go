@@ -0,0 +1,209 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/db/memory"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Test</title>
+<item><guid>item-1</guid><title>Item 1</title><pubDate>Sat, 01 Jan 2000 10:00:00 GMT</pubDate></item>
+<item><guid>item-2</guid><title>Item 2</title><pubDate>Sat, 01 Jan 2000 11:00:00 GMT</pubDate></item>
+</channel></rss>`
+
+type testCategoryModel struct {
+	model.CategoryModel
+	cats []model.Category
+}
+
+func (m *testCategoryModel) GetAll(_ context.Context) ([]model.Category, error) {
+	return m.cats, nil
+}
+
+type testFeedModel struct {
+	model.FeedModel
+	mu    sync.Mutex
+	feeds map[string]model.Feed
+}
+
+func (m *testFeedModel) GetAll(_ context.Context, _ *model.Category) ([]model.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	feeds := make([]model.Feed, 0, len(m.feeds))
+	for _, f := range m.feeds {
+		feeds = append(feeds, f)
+	}
+	return feeds, nil
+}
+
+func (m *testFeedModel) Get(_ context.Context, _ *model.Category, id string) (*model.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.feeds[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &f, nil
+}
+
+func (m *testFeedModel) SetUpdated(_ context.Context, f *model.Feed, u time.Time, seen []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f.LastUpdate = u
+	f.SeenGUIDs = seen
+	m.feeds[f.ID] = *f
+	return nil
+}
+
+func (m *testFeedModel) SetSchedule(_ context.Context, f *model.Feed, interval time.Duration, next time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f.Interval = interval
+	f.NextFetch = next
+	m.feeds[f.ID] = *f
+	return nil
+}
+
+type testSubscriptionModel struct {
+	model.SubscriptionModel
+	mu      sync.Mutex
+	updates map[string]int
+	delay   time.Duration
+}
+
+func (m *testSubscriptionModel) AddUpdate(_ context.Context, up model.Update) error {
+	time.Sleep(m.delay)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updates[up.FeedID]++
+	return nil
+}
+
+type testLeaseModel struct {
+	model.LeaseModel
+	renewed int
+}
+
+func (m *testLeaseModel) Renew(ctx context.Context, l *model.Lease, ttl time.Duration) error {
+	m.renewed++
+	return m.LeaseModel.Renew(ctx, l, ttl)
+}
+
+func TestCoordinator_ConcurrentRunners(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = fmt.Fprint(w, testRSS)
+	}))
+	defer srv.Close()
+
+	cat := model.Category{ID: "cat", Name: "Cat"}
+	categoryModel := &testCategoryModel{cats: []model.Category{cat}}
+	feedModel := &testFeedModel{feeds: map[string]model.Feed{}}
+	for i := 0; i < 3; i++ {
+		id := fmt.Sprintf("feed-%d", i)
+		feedModel.feeds[id] = model.Feed{ID: id, Category: &cat, Title: id, URL: srv.URL + "/" + id}
+	}
+	subscriptionModel := &testSubscriptionModel{updates: map[string]int{}}
+	leaseModel := memory.NewLeaseModel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(holder string) {
+			defer wg.Done()
+			c := New(feedModel, categoryModel, subscriptionModel, leaseModel, holder)
+			c.Client = srv.Client()
+			if err := c.FetchAll(context.Background(), false); err != nil {
+				t.Errorf("FetchAll(%s): %v", holder, err)
+			}
+		}(fmt.Sprintf("runner-%d", i))
+	}
+	wg.Wait()
+
+	for _, guid := range []string{"item-1", "item-2"} {
+		if got, want := subscriptionModel.updates[guid], len(feedModel.feeds); got != want {
+			t.Errorf("%s was added %d times; want %d", guid, got, want)
+		}
+	}
+	for id, f := range feedModel.feeds {
+		if f.NextFetch.IsZero() {
+			t.Errorf("%s was not scheduled", id)
+		}
+	}
+}
+
+func TestCoordinator_LeaseLost(t *testing.T) {
+	leaseModel := memory.NewLeaseModel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the lease expires while the feed is downloaded and another runner takes over
+		time.Sleep(20 * time.Millisecond)
+		if _, err := leaseModel.Acquire(r.Context(), "feed:feed", "other", time.Minute); err != nil {
+			t.Errorf("Acquire(): %v", err)
+		}
+		_, _ = fmt.Fprint(w, testRSS)
+	}))
+	defer srv.Close()
+
+	cat := model.Category{ID: "cat", Name: "Cat"}
+	categoryModel := &testCategoryModel{cats: []model.Category{cat}}
+	feedModel := &testFeedModel{feeds: map[string]model.Feed{
+		"feed": {ID: "feed", Category: &cat, Title: "feed", URL: srv.URL},
+	}}
+	subscriptionModel := &testSubscriptionModel{updates: map[string]int{}}
+
+	c := New(feedModel, categoryModel, subscriptionModel, leaseModel, "runner")
+	c.Client = srv.Client()
+	c.LeaseTTL = time.Millisecond
+	if err := c.FetchAll(context.Background(), false); err != nil {
+		t.Fatalf("FetchAll(): %v", err)
+	}
+	if len(subscriptionModel.updates) != 0 {
+		t.Errorf("got updates %v delivered; want none after the lease is lost", subscriptionModel.updates)
+	}
+	if f := feedModel.feeds["feed"]; !f.NextFetch.IsZero() {
+		t.Errorf("feed was scheduled; want it left to the new holder")
+	}
+}
+
+func TestCoordinator_RenewLease(t *testing.T) {
+	items := ""
+	for i := 0; i < 6; i++ {
+		items += fmt.Sprintf("<item><guid>item-%d</guid><title>Item %d</title></item>", i, i)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><rss version="2.0"><channel><title>Test</title>%s</channel></rss>`, items)
+	}))
+	defer srv.Close()
+
+	cat := model.Category{ID: "cat", Name: "Cat"}
+	categoryModel := &testCategoryModel{cats: []model.Category{cat}}
+	feedModel := &testFeedModel{feeds: map[string]model.Feed{
+		"feed": {ID: "feed", Category: &cat, Title: "feed", URL: srv.URL},
+	}}
+	// delivering all the updates takes longer than the lease lives
+	subscriptionModel := &testSubscriptionModel{updates: map[string]int{}, delay: 30 * time.Millisecond}
+	leaseModel := &testLeaseModel{LeaseModel: memory.NewLeaseModel()}
+
+	c := New(feedModel, categoryModel, subscriptionModel, leaseModel, "runner")
+	c.Client = srv.Client()
+	c.LeaseTTL = 100 * time.Millisecond
+	if err := c.FetchAll(context.Background(), false); err != nil {
+		t.Fatalf("FetchAll(): %v", err)
+	}
+	if got := len(subscriptionModel.updates); got != 6 {
+		t.Errorf("got %d updates delivered; want 6", got)
+	}
+	if leaseModel.renewed == 0 || leaseModel.renewed >= 6 {
+		t.Errorf("lease was renewed %d times; want it renewed by elapsed time", leaseModel.renewed)
+	}
+	if f := feedModel.feeds["feed"]; f.NextFetch.IsZero() {
+		t.Errorf("feed was not scheduled; want the lease kept through the delivery")
+	}
+}
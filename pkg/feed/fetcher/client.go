@@ -0,0 +1,95 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned when the feed is served from an address of the local network.
+var ErrForbiddenAddress = errors.New("address is not allowed")
+
+// MaxRedirects is the number of redirects DefaultClient follows.
+const MaxRedirects = 5
+
+// forbiddenNetworks are the networks not covered by the checks of net.IP the feeds may not be downloaded from.
+var forbiddenNetworks = mustParseNetworks("0.0.0.0/8", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7")
+
+// DefaultClient is the HTTP client used by the Fetcher unless another one is set,
+// it refuses to connect to loopback, private, link-local and unspecified addresses.
+var DefaultClient = NewClient()
+
+// NewClient initializes new HTTP client that only connects to the addresses of the public network.
+//
+//	The addresses are checked once the host is resolved, so a public name can not point at the local network,
+//	and on each redirect, of which up to MaxRedirects are followed.
+func NewClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   checkDialAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: checkRedirect,
+		Timeout:       time.Minute,
+	}
+}
+
+// checkDialAddress rejects the connections to the forbidden addresses, the address is already resolved.
+func checkDialAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !allowedIP(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// checkRedirect limits the number of redirects and rejects the ones to the forbidden addresses.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= MaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", MaxRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+	}
+	if ip := net.ParseIP(req.URL.Hostname()); ip != nil && !allowedIP(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+	return nil
+}
+
+// allowedIP tells whether the feeds may be downloaded from the address.
+func allowedIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range forbiddenNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseNetworks(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
@@ -0,0 +1,101 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAllowedIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		allowed bool
+	}{
+		{ip: "93.184.216.34", allowed: true},
+		{ip: "172.32.0.1", allowed: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", allowed: true},
+		{ip: "127.0.0.1", allowed: false},
+		{ip: "::1", allowed: false},
+		{ip: "::ffff:127.0.0.1", allowed: false},
+		{ip: "10.0.0.1", allowed: false},
+		{ip: "172.16.0.1", allowed: false},
+		{ip: "192.168.1.1", allowed: false},
+		{ip: "fd00::1", allowed: false},
+		{ip: "169.254.169.254", allowed: false},
+		{ip: "fe80::1", allowed: false},
+		{ip: "0.0.0.0", allowed: false},
+		{ip: "0.1.2.3", allowed: false},
+		{ip: "::", allowed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := allowedIP(net.ParseIP(tt.ip)); got != tt.allowed {
+				t.Errorf("allowedIP(%s): got %v; want %v", tt.ip, got, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestDefaultClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer srv.Close()
+
+	t.Run("loopback", func(t *testing.T) {
+		if _, err := New(nil).Discover(context.Background(), srv.URL); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Discover(): got %v; want ErrForbiddenAddress", err)
+		}
+	})
+
+	t.Run("resolved name", func(t *testing.T) {
+		URL := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+		if _, err := New(nil).Discover(context.Background(), URL); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Discover(): got %v; want ErrForbiddenAddress", err)
+		}
+	})
+}
+
+func TestCheckRedirect(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/local", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	})
+	mux.HandleFunc("/scheme", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	// the test server itself is on the loopback address, so only the redirects are checked
+	f := New(nil)
+	f.Client = &http.Client{CheckRedirect: checkRedirect}
+
+	t.Run("local address", func(t *testing.T) {
+		if _, err := f.Discover(context.Background(), srv.URL+"/local"); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Discover(): got %v; want ErrForbiddenAddress", err)
+		}
+	})
+
+	t.Run("scheme", func(t *testing.T) {
+		if _, err := f.Discover(context.Background(), srv.URL+"/scheme"); err == nil || !strings.Contains(err.Error(), "unsupported scheme") {
+			t.Errorf("Discover(): got %v; want unsupported scheme", err)
+		}
+	})
+
+	t.Run("too many redirects", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "https://example.com/feed", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := checkRedirect(req, make([]*http.Request, MaxRedirects-1)); err != nil {
+			t.Errorf("checkRedirect(%d): %v", MaxRedirects, err)
+		}
+		if err := checkRedirect(req, make([]*http.Request, MaxRedirects)); err == nil {
+			t.Errorf("checkRedirect(%d): got no error; want stopped after redirects", MaxRedirects+1)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/mmcdole/gofeed"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrFeedNotFound is returned by Discover when there is no feed at the URL.
+var ErrFeedNotFound = errors.New("feed not found")
+
+// maxDiscoverSize limits the size of a document downloaded by Discover.
+const maxDiscoverSize = 2 << 20
+
+// feedLinkTypes are the content types of the feeds a web page can link to.
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+}
+
+// Discover finds the feed at the URL, a web page is resolved to the first feed it links to.
+//
+//	Returns the Feed with the Title and the URL of the feed found.
+func (f Fetcher) Discover(ctx context.Context, URL string) (*model.Feed, error) {
+	body, base, err := f.download(ctx, URL)
+	if err != nil {
+		return nil, err
+	}
+	if feed, err := gofeed.NewParser().Parse(bytes.NewReader(body)); err == nil {
+		return &model.Feed{Title: feed.Title, URL: URL}, nil
+	}
+	link := findFeedLink(body, base)
+	if len(link) == 0 {
+		return nil, ErrFeedNotFound
+	}
+	body, _, err = f.download(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, ErrFeedNotFound
+	}
+	return &model.Feed{Title: feed.Title, URL: link}, nil
+}
+
+// download reads the document at the URL, returns it along with the URL it was served from after redirects.
+func (f Fetcher) download(ctx context.Context, URL string) ([]byte, *url.URL, error) {
+	req, err := http.NewRequest("GET", URL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", "Gofeed/1.0")
+	resp, err := f.client().Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, gofeed.HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDiscoverSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, resp.Request.URL, nil
+}
+
+// findFeedLink returns the absolute URL of the first feed the web page links to, empty if there is none.
+func findFeedLink(page []byte, base *url.URL) string {
+	z := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			if t.DataAtom == atom.Body {
+				return ""
+			}
+			if t.DataAtom != atom.Link {
+				continue
+			}
+			var rel, typ, href string
+			for _, attr := range t.Attr {
+				switch attr.Key {
+				case "rel":
+					rel = strings.ToLower(attr.Val)
+				case "type":
+					typ = strings.ToLower(strings.TrimSpace(attr.Val))
+				case "href":
+					href = strings.TrimSpace(attr.Val)
+				}
+			}
+			if !strings.Contains(rel, "alternate") || !feedLinkTypes[typ] || len(href) == 0 {
+				continue
+			}
+			link, err := base.Parse(href)
+			if err != nil {
+				continue
+			}
+			return link.String()
+		}
+	}
+}
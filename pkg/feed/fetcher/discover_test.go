@@ -0,0 +1,59 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testPage = `<!DOCTYPE html>
+<html><head><title>Test</title>
+<link rel="stylesheet" href="/style.css">
+<link rel="alternate" type="application/rss+xml" title="Test" href="/feed.xml">
+</head><body></body></html>`
+
+func TestFetcher_Discover(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testRSS)
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testPage)
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "<html><body><p>Nothing to see</p></body></html>")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		Name    string
+		Path    string
+		WantURL string
+		WantErr bool
+	}{
+		{Name: "Feed", Path: "/feed.xml", WantURL: srv.URL + "/feed.xml"},
+		{Name: "Page", Path: "/page", WantURL: srv.URL + "/feed.xml"},
+		{Name: "NoFeed", Path: "/plain", WantErr: true},
+		{Name: "NotFound", Path: "/missing", WantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			feed, err := newTestFetcher(nil).Discover(context.Background(), srv.URL+tt.Path)
+			if tt.WantErr {
+				if err == nil {
+					t.Errorf("Discover(%q): got %v; want error", tt.Path, feed.URL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Discover(%q): %v", tt.Path, err)
+			}
+			if feed.URL != tt.WantURL || feed.Title != "Test" {
+				t.Errorf("Discover(%q): got %q %q; want Test %q", tt.Path, feed.Title, feed.URL, tt.WantURL)
+			}
+		})
+	}
+}
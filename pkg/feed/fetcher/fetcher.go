@@ -4,12 +4,34 @@ import (
 	"context"
 	"github.com/d-ashesss/news-feed-bot/pkg/model"
 	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/rss"
 	"log"
+	"net/http"
+	"strings"
+	"time"
 )
 
+// Listener is notified about every update ingested by the Fetcher.
+type Listener interface {
+	OnUpdate(ctx context.Context, up model.Update)
+}
+
+// Listeners notifies all of its listeners.
+type Listeners []Listener
+
+func (ls Listeners) OnUpdate(ctx context.Context, up model.Update) {
+	for _, l := range ls {
+		l.OnUpdate(ctx, up)
+	}
+}
+
 // Fetcher reads the feed and extracts posts from it.
 type Fetcher struct {
 	subscriptionModel model.SubscriptionModel
+
+	Listener Listener                        // Listener is an optional listener of ingested updates.
+	Fence    func(ctx context.Context) error // Fence is an optional check run before every update is saved, the fetch is abandoned if it fails.
+	Client   *http.Client                    // Client downloads the feeds, DefaultClient if nil.
 }
 
 // New instantiates new Fetcher.
@@ -17,9 +39,17 @@ func New(subscriptionModel model.SubscriptionModel) *Fetcher {
 	return &Fetcher{subscriptionModel: subscriptionModel}
 }
 
+// Result describes the outcome of a single fetch.
+type Result struct {
+	Published  []time.Time // Published is a list of publication dates of all items in the feed.
+	New        int         // New is the number of items added to subscriptions.
+	LastUpdate time.Time   // LastUpdate is the new high-water mark of the feed.
+	Seen       []string    // Seen are the GUIDs of the feed items that were ingested or skipped.
+	Hints      Hints       // Hints are the publisher's hints on how often the feed should be polled.
+}
+
 func (f Fetcher) GetTitle(ctx context.Context, URL string) (string, error) {
-	fp := gofeed.NewParser()
-	feed, err := fp.ParseURLWithContext(URL, ctx)
+	feed, _, err := f.parse(ctx, URL)
 	if err != nil {
 		return "", err
 	}
@@ -27,31 +57,176 @@ func (f Fetcher) GetTitle(ctx context.Context, URL string) (string, error) {
 }
 
 // Fetch reads posts from the feed into subscriptions.
-func (f Fetcher) Fetch(ctx context.Context, fd *model.Feed, cat *model.Category) error {
-	fp := gofeed.NewParser()
-	feed, err := fp.ParseURLWithContext(fd.URL, ctx)
+//
+//	An item is new if its GUID was not seen in the feed on the previous fetch.
+//	Feeds without seen GUIDs fall back to comparing the item date to the high-water mark,
+//	as do feeds with model.MaxSeenGUIDs seen, whose GUIDs beyond the limit were not kept.
+//	Undated items are stamped with the fetch time but do not advance the high-water mark.
+//	Items not passing the filters of the feed and the category are skipped.
+//	Items failed to be saved are not marked as seen, so they are retried on the next fetch.
+//	Updates of the personal category are kept from the Listener, so other subscribers can not find them.
+func (f Fetcher) Fetch(ctx context.Context, fd *model.Feed, cat *model.Category) (*Result, error) {
+	feed, header, err := f.parse(ctx, fd.URL)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	log.Printf("[fetcher] fetching updates from feed %q [%s] for category %q", feed.Title, feed.Language, cat.Name)
 
+	seen := make(map[string]bool, len(fd.SeenGUIDs))
+	for _, guid := range fd.SeenGUIDs {
+		seen[guid] = true
+	}
+	dateCheck := len(fd.SeenGUIDs) == 0 || len(fd.SeenGUIDs) >= model.MaxSeenGUIDs
+	now := time.Now().UTC()
+	res := &Result{LastUpdate: fd.LastUpdate, Hints: parseHints(feed, header)}
+	if len(feed.Items) == 0 {
+		// an empty response must not forget the seen items, they would be ingested again once they are back
+		res.Seen = fd.SeenGUIDs
+		return res, nil
+	}
 	for _, i := range feed.Items {
-		if i.PublishedParsed == nil {
+		guid := itemGUID(i)
+		date, dated := itemDate(i)
+		if dated {
+			res.Published = append(res.Published, date)
+		} else {
+			date = now
+		}
+		if seen[guid] || (dateCheck && fd.LastUpdate.After(date)) {
+			res.Seen = append(res.Seen, guid)
 			continue
 		}
 		up := model.Update{
 			Category: cat,
-			FeedID:   i.GUID,
+			FeedID:   guid,
+			Source:   fd.ID,
 			Title:    i.Title,
-			Date:     i.PublishedParsed.UTC(),
+			Summary:  i.Description,
+			Date:     date,
 			URL:      i.Link,
+			Image:    itemImage(i),
 		}
-		if fd.LastUpdate.After(up.Date) {
+		if !fd.Filter.Match(up) || !cat.Filter.Match(up) {
+			res.Seen = append(res.Seen, guid)
 			continue
 		}
+		if f.Fence != nil {
+			if err := f.Fence(ctx); err != nil {
+				return nil, err
+			}
+		}
 		if err := f.subscriptionModel.AddUpdate(ctx, up); err != nil {
 			log.Printf("[fetcher] failed to save update: %v", err)
+			continue
+		}
+		if f.Listener != nil && !cat.Personal {
+			f.Listener.OnUpdate(ctx, up)
+		}
+		res.Seen = append(res.Seen, guid)
+		res.New++
+		// the fetch time of undated items would hide the items published before it on the next fetch
+		if dated && date.After(res.LastUpdate) {
+			res.LastUpdate = date
+		}
+	}
+	return res, nil
+}
+
+// itemGUID returns the GUID of the item falling back to its link.
+func itemGUID(i *gofeed.Item) string {
+	if len(i.GUID) > 0 {
+		return i.GUID
+	}
+	return i.Link
+}
+
+// itemImage returns the URL of the item's image from the feed, an image enclosure or Media RSS.
+func itemImage(i *gofeed.Item) string {
+	if i.Image != nil && len(i.Image.URL) > 0 {
+		return i.Image.URL
+	}
+	for _, e := range i.Enclosures {
+		if strings.HasPrefix(e.Type, "image/") && len(e.URL) > 0 {
+			return e.URL
+		}
+	}
+	media := i.Extensions["media"]
+	for _, e := range media["thumbnail"] {
+		if len(e.Attrs["url"]) > 0 {
+			return e.Attrs["url"]
+		}
+	}
+	for _, e := range media["content"] {
+		if (e.Attrs["medium"] == "image" || strings.HasPrefix(e.Attrs["type"], "image/")) && len(e.Attrs["url"]) > 0 {
+			return e.Attrs["url"]
+		}
+	}
+	return ""
+}
+
+// itemDate returns the published or updated date of the item, whichever is present.
+func itemDate(i *gofeed.Item) (time.Time, bool) {
+	if i.PublishedParsed != nil {
+		return i.PublishedParsed.UTC(), true
+	}
+	if i.UpdatedParsed != nil {
+		return i.UpdatedParsed.UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// parse downloads and parses the feed keeping the response headers.
+func (f Fetcher) parse(ctx context.Context, URL string) (*gofeed.Feed, http.Header, error) {
+	req, err := http.NewRequest("GET", URL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", "Gofeed/1.0")
+	resp, err := f.client().Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, gofeed.HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	fp := gofeed.NewParser()
+	fp.RSSTranslator = &rssTranslator{}
+	feed, err := fp.Parse(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return feed, resp.Header, nil
+}
+
+// client returns the HTTP client to download the feeds with.
+func (f Fetcher) client() *http.Client {
+	if f.Client == nil {
+		return DefaultClient
+	}
+	return f.Client
+}
+
+// rssTranslator keeps RSS properties not supported by the universal gofeed.Feed in its Custom map.
+type rssTranslator struct {
+	gofeed.DefaultRSSTranslator
+}
+
+func (t *rssTranslator) Translate(feed interface{}) (*gofeed.Feed, error) {
+	result, err := t.DefaultRSSTranslator.Translate(feed)
+	if err != nil {
+		return nil, err
+	}
+	if rf, ok := feed.(*rss.Feed); ok && len(rf.TTL) > 0 {
+		if result.Custom == nil {
+			result.Custom = map[string]string{}
 		}
+		result.Custom["ttl"] = rf.TTL
 	}
-	return nil
+	return result, nil
 }
@@ -0,0 +1,215 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Test</title>
+<item><guid>new</guid><title>New</title><pubDate>Sat, 01 Jan 2000 12:00:00 GMT</pubDate></item>
+<item><guid>late</guid><title>Late</title><pubDate>Sat, 01 Jan 2000 09:00:00 GMT</pubDate></item>
+<item><guid>seen</guid><title>Seen</title><pubDate>Sat, 01 Jan 2000 10:00:00 GMT</pubDate></item>
+<item><link>http://localhost/undated</link><title>Undated</title></item>
+<item><guid>failing</guid><title>Failing</title><pubDate>Sat, 01 Jan 2000 13:00:00 GMT</pubDate></item>
+</channel></rss>`
+
+type testSubscriptionModel struct {
+	model.SubscriptionModel
+	updates []model.Update
+}
+
+func (m *testSubscriptionModel) AddUpdate(_ context.Context, up model.Update) error {
+	if up.FeedID == "failing" {
+		return errors.New("failed")
+	}
+	m.updates = append(m.updates, up)
+	return nil
+}
+
+// newTestFetcher initializes new Fetcher allowed to download from the test servers on the loopback address.
+func newTestFetcher(subscriptionModel model.SubscriptionModel) *Fetcher {
+	f := New(subscriptionModel)
+	f.Client = http.DefaultClient
+	return f
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testRSS)
+	}))
+	defer srv.Close()
+
+	mark := time.Date(2000, 1, 1, 10, 0, 0, 0, time.UTC)
+	cat := &model.Category{ID: "cat"}
+
+	t.Run("seen GUIDs", func(t *testing.T) {
+		subs := &testSubscriptionModel{}
+		fd := &model.Feed{URL: srv.URL, LastUpdate: mark, SeenGUIDs: []string{"seen"}}
+		res, err := newTestFetcher(subs).Fetch(context.Background(), fd, cat)
+		if err != nil {
+			t.Fatalf("Fetch(): %v", err)
+		}
+		got := make(map[string]model.Update)
+		for _, up := range subs.updates {
+			got[up.FeedID] = up
+		}
+		for _, guid := range []string{"new", "late", "http://localhost/undated"} {
+			if _, ok := got[guid]; !ok {
+				t.Errorf("Fetch(): %q was not ingested", guid)
+			}
+		}
+		if _, ok := got["seen"]; ok {
+			t.Errorf("Fetch(): seen item was ingested")
+		}
+		if up := got["http://localhost/undated"]; up.Date.IsZero() {
+			t.Errorf("Fetch(): undated item has no date")
+		}
+		if res.New != 3 {
+			t.Errorf("Fetch(): got %d new items; want 3", res.New)
+		}
+		for _, guid := range res.Seen {
+			if guid == "failing" {
+				t.Errorf("Fetch(): failed item was marked as seen")
+			}
+		}
+		if len(res.Seen) != 4 {
+			t.Errorf("Fetch(): got %d seen items; want 4", len(res.Seen))
+		}
+		if want := time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC); !res.LastUpdate.Equal(want) {
+			t.Errorf("Fetch(): got high-water mark %v; want %v of the newest dated item", res.LastUpdate, want)
+		}
+	})
+
+	t.Run("no seen GUIDs", func(t *testing.T) {
+		subs := &testSubscriptionModel{}
+		fd := &model.Feed{URL: srv.URL, LastUpdate: mark}
+		res, err := newTestFetcher(subs).Fetch(context.Background(), fd, cat)
+		if err != nil {
+			t.Fatalf("Fetch(): %v", err)
+		}
+		for _, up := range subs.updates {
+			if up.FeedID == "late" {
+				t.Errorf("Fetch(): item older than the mark was ingested")
+			}
+		}
+		if res.New != 3 {
+			t.Errorf("Fetch(): got %d new items; want 3", res.New)
+		}
+	})
+
+	t.Run("seen GUIDs limit", func(t *testing.T) {
+		subs := &testSubscriptionModel{}
+		seen := []string{"seen"}
+		for len(seen) < model.MaxSeenGUIDs {
+			seen = append(seen, fmt.Sprintf("dropped-%d", len(seen)))
+		}
+		fd := &model.Feed{URL: srv.URL, LastUpdate: mark, SeenGUIDs: seen}
+		if _, err := newTestFetcher(subs).Fetch(context.Background(), fd, cat); err != nil {
+			t.Fatalf("Fetch(): %v", err)
+		}
+		for _, up := range subs.updates {
+			if up.FeedID == "late" || up.FeedID == "seen" {
+				t.Errorf("Fetch(): %q was ingested; want the items older than the mark skipped once the seen GUIDs are truncated", up.FeedID)
+			}
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		subs := &testSubscriptionModel{}
+		fd := &model.Feed{URL: srv.URL, LastUpdate: mark, SeenGUIDs: []string{"seen"}, Filter: model.Filter{Exclude: []string{"late"}}}
+		cat := &model.Category{ID: "cat", Filter: model.Filter{Include: []string{"/^(new|late|seen)\\b/"}}}
+		res, err := newTestFetcher(subs).Fetch(context.Background(), fd, cat)
+		if err != nil {
+			t.Fatalf("Fetch(): %v", err)
+		}
+		if len(subs.updates) != 1 || subs.updates[0].FeedID != "new" {
+			t.Errorf("Fetch(): got %v; want only the new item", subs.updates)
+		}
+		if len(res.Seen) != 5 {
+			t.Errorf("Fetch(): got %d seen items; want 5", len(res.Seen))
+		}
+	})
+
+	t.Run("empty feed", func(t *testing.T) {
+		srvEmpty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><rss version="2.0"><channel><title>Test</title></channel></rss>`)
+		}))
+		defer srvEmpty.Close()
+		fd := &model.Feed{URL: srvEmpty.URL, LastUpdate: mark, SeenGUIDs: []string{"seen"}}
+		res, err := newTestFetcher(&testSubscriptionModel{}).Fetch(context.Background(), fd, cat)
+		if err != nil {
+			t.Fatalf("Fetch(): %v", err)
+		}
+		if len(res.Seen) != 1 || res.Seen[0] != "seen" || !res.LastUpdate.Equal(mark) {
+			t.Errorf("Fetch(): got seen %v, high-water mark %v; want the previous state kept", res.Seen, res.LastUpdate)
+		}
+	})
+
+	t.Run("fence", func(t *testing.T) {
+		subs := &testSubscriptionModel{}
+		f := newTestFetcher(subs)
+		fences := 0
+		f.Fence = func(context.Context) error {
+			if fences++; fences > 1 {
+				return model.ErrLeaseLost
+			}
+			return nil
+		}
+		fd := &model.Feed{URL: srv.URL, LastUpdate: mark, SeenGUIDs: []string{"seen"}}
+		if _, err := f.Fetch(context.Background(), fd, cat); !errors.Is(err, model.ErrLeaseLost) {
+			t.Errorf("Fetch(): got error %v; want %v", err, model.ErrLeaseLost)
+		}
+		if len(subs.updates) != 1 {
+			t.Errorf("Fetch(): got %d updates saved; want 1 before the fence failed", len(subs.updates))
+		}
+	})
+
+	t.Run("failed fetch", func(t *testing.T) {
+		srv404 := httptest.NewServer(http.NotFoundHandler())
+		defer srv404.Close()
+		fd := &model.Feed{URL: srv404.URL, LastUpdate: mark}
+		if _, err := newTestFetcher(&testSubscriptionModel{}).Fetch(context.Background(), fd, cat); err == nil {
+			t.Errorf("Fetch(): want error")
+		}
+	})
+}
+
+func TestItemImage(t *testing.T) {
+	tests := []struct {
+		name string
+		item *gofeed.Item
+		want string
+	}{
+		{name: "none", item: &gofeed.Item{}, want: ""},
+		{name: "image", item: &gofeed.Item{Image: &gofeed.Image{URL: "http://localhost/image.png"}}, want: "http://localhost/image.png"},
+		{name: "enclosure", item: &gofeed.Item{Enclosures: []*gofeed.Enclosure{
+			{URL: "http://localhost/audio.mp3", Type: "audio/mpeg"},
+			{URL: "http://localhost/image.jpg", Type: "image/jpeg"},
+		}}, want: "http://localhost/image.jpg"},
+		{name: "media thumbnail", item: &gofeed.Item{Extensions: ext.Extensions{"media": {
+			"thumbnail": {{Name: "thumbnail", Attrs: map[string]string{"url": "http://localhost/thumb.jpg"}}},
+		}}}, want: "http://localhost/thumb.jpg"},
+		{name: "media content", item: &gofeed.Item{Extensions: ext.Extensions{"media": {
+			"content": {
+				{Name: "content", Attrs: map[string]string{"url": "http://localhost/video.mp4", "medium": "video"}},
+				{Name: "content", Attrs: map[string]string{"url": "http://localhost/photo.jpg", "medium": "image"}},
+			},
+		}}}, want: "http://localhost/photo.jpg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := itemImage(tt.item); got != tt.want {
+				t.Errorf("itemImage() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
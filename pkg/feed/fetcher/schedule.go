@@ -0,0 +1,134 @@
+package fetcher
+
+import (
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Hints are the publisher's hints on how often the feed should be polled.
+type Hints struct {
+	TTL          time.Duration // TTL is taken from RSS <ttl> element.
+	UpdatePeriod time.Duration // UpdatePeriod is taken from sy:updatePeriod and sy:updateFrequency elements.
+	MaxAge       time.Duration // MaxAge is taken from Cache-Control response header.
+}
+
+// Min returns the longest of the hints, which is the minimal interval the publisher asks to respect.
+func (h Hints) Min() time.Duration {
+	d := h.TTL
+	if h.UpdatePeriod > d {
+		d = h.UpdatePeriod
+	}
+	if h.MaxAge > d {
+		d = h.MaxAge
+	}
+	return d
+}
+
+// Bounds limit the polling interval of a feed.
+type Bounds struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// DefaultBounds are the polling interval bounds used when none are configured.
+var DefaultBounds = Bounds{Min: 5 * time.Minute, Max: 24 * time.Hour}
+
+// NextInterval adapts the polling interval of a feed to its observed publishing rate.
+//
+//	The interval moves halfway towards the median gap between publications,
+//	or shrinks and grows depending on whether new items were found when the rate is unknown.
+//	Publisher's hints and the bounds are applied last.
+func NextInterval(current time.Duration, res *Result, b Bounds) time.Duration {
+	next := current
+	if gap := medianGap(res.Published); gap > 0 {
+		next = (current + gap) / 2
+	} else if res.New > 0 {
+		next = current / 2
+	} else {
+		next = current * 3 / 2
+	}
+	if hint := res.Hints.Min(); next < hint {
+		next = hint
+	}
+	if next < b.Min {
+		next = b.Min
+	}
+	if b.Max > 0 && next > b.Max {
+		next = b.Max
+	}
+	return next
+}
+
+// medianGap calculates the median time between consecutive publications.
+func medianGap(dates []time.Time) time.Duration {
+	if len(dates) < 2 {
+		return 0
+	}
+	sorted := make([]time.Time, len(dates))
+	copy(sorted, dates)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	gaps := make([]time.Duration, 0, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		gaps = append(gaps, sorted[i].Sub(sorted[i-1]))
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	return gaps[len(gaps)/2]
+}
+
+// parseHints extracts polling hints from the feed and the HTTP response headers.
+func parseHints(feed *gofeed.Feed, header http.Header) Hints {
+	var h Hints
+	if ttl, err := strconv.Atoi(feed.Custom["ttl"]); err == nil && ttl > 0 {
+		h.TTL = time.Duration(ttl) * time.Minute
+	}
+	if sy, ok := feed.Extensions["sy"]; ok {
+		h.UpdatePeriod = parseUpdatePeriod(sy["updatePeriod"], sy["updateFrequency"])
+	}
+	h.MaxAge = parseMaxAge(header.Get("Cache-Control"))
+	return h
+}
+
+func parseUpdatePeriod(period, frequency []ext.Extension) time.Duration {
+	if len(period) == 0 {
+		return 0
+	}
+	var d time.Duration
+	switch strings.TrimSpace(period[0].Value) {
+	case "hourly":
+		d = time.Hour
+	case "daily":
+		d = 24 * time.Hour
+	case "weekly":
+		d = 7 * 24 * time.Hour
+	case "monthly":
+		d = 30 * 24 * time.Hour
+	case "yearly":
+		d = 365 * 24 * time.Hour
+	default:
+		return 0
+	}
+	if len(frequency) > 0 {
+		if f, err := strconv.Atoi(strings.TrimSpace(frequency[0].Value)); err == nil && f > 0 {
+			d /= time.Duration(f)
+		}
+	}
+	return d
+}
+
+func parseMaxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+		if sec, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil && sec > 0 {
+			return time.Duration(sec) * time.Second
+		}
+	}
+	return 0
+}
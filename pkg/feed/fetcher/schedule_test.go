@@ -0,0 +1,94 @@
+package fetcher
+
+import (
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNextInterval(t *testing.T) {
+	base := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	bounds := Bounds{Min: 5 * time.Minute, Max: 24 * time.Hour}
+	tests := []struct {
+		name    string
+		current time.Duration
+		res     *Result
+		want    time.Duration
+	}{
+		{
+			name:    "frequent publications",
+			current: time.Hour,
+			res: &Result{Published: []time.Time{
+				base, base.Add(10 * time.Minute), base.Add(20 * time.Minute), base.Add(30 * time.Minute),
+			}},
+			want: 35 * time.Minute,
+		},
+		{
+			name:    "rare publications",
+			current: time.Hour,
+			res: &Result{Published: []time.Time{
+				base, base.Add(7 * 24 * time.Hour), base.Add(14 * 24 * time.Hour),
+			}},
+			want: 24 * time.Hour,
+		},
+		{
+			name:    "unknown rate with new items",
+			current: time.Hour,
+			res:     &Result{New: 1},
+			want:    30 * time.Minute,
+		},
+		{
+			name:    "unknown rate without new items",
+			current: time.Hour,
+			res:     &Result{},
+			want:    90 * time.Minute,
+		},
+		{
+			name:    "min bound",
+			current: 6 * time.Minute,
+			res:     &Result{New: 1},
+			want:    5 * time.Minute,
+		},
+		{
+			name:    "publisher hint",
+			current: time.Hour,
+			res:     &Result{New: 1, Hints: Hints{TTL: 2 * time.Hour}},
+			want:    2 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextInterval(tt.current, tt.res, bounds); got != tt.want {
+				t.Errorf("NextInterval(): got %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHints(t *testing.T) {
+	feed := &gofeed.Feed{
+		Custom: map[string]string{"ttl": "30"},
+		Extensions: ext.Extensions{"sy": {
+			"updatePeriod":    {{Value: "daily"}},
+			"updateFrequency": {{Value: "4"}},
+		}},
+	}
+	header := http.Header{}
+	header.Set("Cache-Control", "public, max-age=600")
+
+	h := parseHints(feed, header)
+	if h.TTL != 30*time.Minute {
+		t.Errorf("TTL: got %v; want %v", h.TTL, 30*time.Minute)
+	}
+	if h.UpdatePeriod != 6*time.Hour {
+		t.Errorf("UpdatePeriod: got %v; want %v", h.UpdatePeriod, 6*time.Hour)
+	}
+	if h.MaxAge != 10*time.Minute {
+		t.Errorf("MaxAge: got %v; want %v", h.MaxAge, 10*time.Minute)
+	}
+	if h.Min() != 6*time.Hour {
+		t.Errorf("Min(): got %v; want %v", h.Min(), 6*time.Hour)
+	}
+}
@@ -0,0 +1,193 @@
+// Package i18n provides message catalogs to localize the texts of the bot.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is the language used when there is no catalog for the requested one.
+const DefaultLanguage = "en"
+
+// Message is a translated text with a form for each plural category of the language, see PluralForm.
+//
+//	A message that does not depend on a number only has the PluralOther form.
+type Message map[string]string
+
+// UnmarshalJSON accepts either a plain string or an object of plural forms.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*m = Message{PluralOther: text}
+		return nil
+	}
+	var forms map[string]string
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return err
+	}
+	if _, ok := forms[PluralOther]; !ok {
+		return fmt.Errorf("message has no %q form", PluralOther)
+	}
+	*m = forms
+	return nil
+}
+
+// Catalog is a set of Messages of a language keyed by ID.
+type Catalog map[string]Message
+
+// Bundle keeps the Catalogs of all supported languages.
+type Bundle struct {
+	Default  string             // Default is the language to fall back to.
+	catalogs map[string]Catalog // catalogs are keyed by language code.
+}
+
+// NewBundle initializes new Bundle falling back to the def language.
+func NewBundle(def string) *Bundle {
+	return &Bundle{Default: def, catalogs: make(map[string]Catalog)}
+}
+
+// Add adds the Catalog of a language replacing the existing one.
+func (b *Bundle) Add(lang string, c Catalog) {
+	b.catalogs[strings.ToLower(lang)] = c
+}
+
+// Load loads the catalogs from JSON files named after their language, e.g. `en.json`.
+func (b *Bundle) Load(dir string) error {
+	if err := b.LoadFS(os.DirFS(dir)); err != nil {
+		return fmt.Errorf("%s: %w", dir, err)
+	}
+	return nil
+}
+
+// LoadFS loads the catalogs from JSON files in the root of the file system, see Load.
+func (b *Bundle) LoadFS(fsys fs.FS) error {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no catalogs found")
+	}
+	for _, f := range files {
+		data, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return err
+		}
+		var c Catalog
+		if err := json.Unmarshal(data, &c); err != nil {
+			return fmt.Errorf("%s: %v", f, err)
+		}
+		b.Add(strings.TrimSuffix(path.Base(f), ".json"), c)
+	}
+	return nil
+}
+
+// Languages returns the codes of the languages having a Catalog.
+func (b *Bundle) Languages() []string {
+	langs := make([]string, 0, len(b.catalogs))
+	for lang := range b.catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Match finds the supported language for a language code like `en-US`, or returns the Default one.
+func (b *Bundle) Match(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+	if _, ok := b.catalogs[code]; ok {
+		return code
+	}
+	if i := strings.Index(code, "-"); i > 0 {
+		if _, ok := b.catalogs[code[:i]]; ok {
+			return code[:i]
+		}
+	}
+	return b.Default
+}
+
+// Has tells whether the message is translated to the language.
+func (b *Bundle) Has(lang, key string) bool {
+	_, ok := b.catalogs[lang][key]
+	return ok
+}
+
+// Missing returns the keys of the Default catalog that are not translated to each of the other languages.
+func (b *Bundle) Missing() map[string][]string {
+	missing := make(map[string][]string)
+	for lang, c := range b.catalogs {
+		for key := range b.catalogs[b.Default] {
+			if _, ok := c[key]; !ok {
+				missing[lang] = append(missing[lang], key)
+			}
+		}
+		sort.Strings(missing[lang])
+	}
+	for lang, keys := range missing {
+		if len(keys) == 0 {
+			delete(missing, lang)
+		}
+	}
+	return missing
+}
+
+// Localizer returns the Localizer of a language.
+func (b *Bundle) Localizer(lang string) *Localizer {
+	return &Localizer{bundle: b, Lang: b.Match(lang)}
+}
+
+// lookup finds the form of a message falling back to the Default language.
+func (b *Bundle) lookup(lang, key, form string) (string, bool) {
+	for _, l := range []string{lang, b.Default} {
+		if m, ok := b.catalogs[l][key]; ok {
+			if text, ok := m[form]; ok {
+				return text, true
+			}
+			return m[PluralOther], true
+		}
+	}
+	return "", false
+}
+
+// Localizer translates messages to a language.
+type Localizer struct {
+	bundle *Bundle
+	Lang   string // Lang is the language of the Localizer.
+}
+
+// T translates the message formatting it with the args.
+//
+//	Unknown messages are returned as their key.
+func (l *Localizer) T(key string, args ...interface{}) string {
+	return l.translate(key, PluralOther, args)
+}
+
+// N translates the message choosing the plural form for n and formatting it with the args, or n alone if none.
+func (l *Localizer) N(key string, n int, args ...interface{}) string {
+	if len(args) == 0 {
+		args = []interface{}{n}
+	}
+	lang := DefaultLanguage
+	if l != nil {
+		lang = l.Lang
+	}
+	return l.translate(key, PluralForm(lang, n), args)
+}
+
+func (l *Localizer) translate(key, form string, args []interface{}) string {
+	text := key
+	if l != nil && l.bundle != nil {
+		if t, ok := l.bundle.lookup(l.Lang, key, form); ok {
+			text = t
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
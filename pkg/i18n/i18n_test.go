@@ -0,0 +1,121 @@
+package i18n
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+)
+
+func newTestBundle(t *testing.T) *Bundle {
+	t.Helper()
+	b := NewBundle("en")
+	var en, ru Catalog
+	if err := json.Unmarshal([]byte(`{
+		"hello": "Hello, %s!",
+		"bye": "Bye",
+		"updates": {"one": "%d update", "other": "%d updates"}
+	}`), &en); err != nil {
+		t.Fatalf("unmarshal en: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{
+		"hello": "Привет, %s!",
+		"updates": {"one": "%d новость", "few": "%d новости", "many": "%d новостей", "other": "%d новостей"}
+	}`), &ru); err != nil {
+		t.Fatalf("unmarshal ru: %v", err)
+	}
+	b.Add("en", en)
+	b.Add("ru", ru)
+	return b
+}
+
+func TestMessage_UnmarshalJSON(t *testing.T) {
+	var m Message
+	if err := json.Unmarshal([]byte(`{"one": "a"}`), &m); err == nil {
+		t.Errorf("UnmarshalJSON(): got no error for a message without the other form")
+	}
+	if err := json.Unmarshal([]byte(`"text"`), &m); err != nil || m[PluralOther] != "text" {
+		t.Errorf("UnmarshalJSON(): got %v, %v; want the other form", m, err)
+	}
+}
+
+func TestBundle_Match(t *testing.T) {
+	b := newTestBundle(t)
+	tests := map[string]string{
+		"ru":    "ru",
+		"ru-RU": "ru",
+		"EN_us": "en",
+		"de":    "en",
+		"":      "en",
+	}
+	for code, want := range tests {
+		if got := b.Match(code); got != want {
+			t.Errorf("Match(%q): got %q; want %q", code, got, want)
+		}
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	b := newTestBundle(t)
+	ru := b.Localizer("ru")
+
+	if got := ru.T("hello", "Мир"); got != "Привет, Мир!" {
+		t.Errorf("T(hello): got %q", got)
+	}
+	if got := ru.T("bye"); got != "Bye" {
+		t.Errorf("T(bye): got %q; want the default language", got)
+	}
+	if got := ru.T("unknown"); got != "unknown" {
+		t.Errorf("T(unknown): got %q; want the key", got)
+	}
+	for n, want := range map[int]string{1: "1 новость", 3: "3 новости", 11: "11 новостей", 22: "22 новости"} {
+		if got := ru.N("updates", n); got != want {
+			t.Errorf("N(updates, %d): got %q; want %q", n, got, want)
+		}
+	}
+	if got := b.Localizer("en").N("updates", 1); got != "1 update" {
+		t.Errorf("N(updates, 1): got %q", got)
+	}
+
+	var l *Localizer
+	if got := l.T("bye"); got != "bye" {
+		t.Errorf("T(bye) of nil Localizer: got %q; want the key", got)
+	}
+}
+
+func TestBundle_Missing(t *testing.T) {
+	b := newTestBundle(t)
+	missing := b.Missing()
+	if len(missing) != 1 || len(missing["ru"]) != 1 || missing["ru"][0] != "bye" {
+		t.Errorf("Missing(): got %v; want bye in ru", missing)
+	}
+}
+
+func TestBundle_LoadFS(t *testing.T) {
+	t.Run("catalogs", func(t *testing.T) {
+		b := NewBundle("en")
+		err := b.LoadFS(fstest.MapFS{
+			"en.json":     {Data: []byte(`{"hello": "Hello"}`)},
+			"ru.json":     {Data: []byte(`{"hello": "Привет"}`)},
+			"README.md":   {Data: []byte(`not a catalog`)},
+			"old/de.json": {Data: []byte(`{"hello": "Hallo"}`)},
+		})
+		if err != nil {
+			t.Fatalf("LoadFS(): %v", err)
+		}
+		if langs := b.Languages(); len(langs) != 2 || langs[0] != "en" || langs[1] != "ru" {
+			t.Errorf("LoadFS(): got %v; want en, ru", langs)
+		}
+	})
+
+	t.Run("no catalogs", func(t *testing.T) {
+		if err := NewBundle("en").LoadFS(fstest.MapFS{}); err == nil {
+			t.Errorf("LoadFS(): got no error; want no catalogs found")
+		}
+	})
+
+	t.Run("invalid catalog", func(t *testing.T) {
+		if err := NewBundle("en").LoadFS(fstest.MapFS{"en.json": {Data: []byte(`{"hello": 1}`)}}); err == nil {
+			t.Errorf("LoadFS(): got no error; want the invalid catalog reported")
+		}
+	})
+}
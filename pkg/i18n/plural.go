@@ -0,0 +1,48 @@
+package i18n
+
+// Plural categories of the messages as defined by CLDR.
+const (
+	PluralOne   = "one"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralForm returns the plural category of n in the language.
+//
+//	Only integer rules of the languages likely to be supported are implemented,
+//	the others follow the English rule.
+func PluralForm(lang string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch lang {
+	case "ru", "uk", "be":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return PluralOne
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return PluralFew
+		}
+		return PluralMany
+	case "pl":
+		switch {
+		case n == 1:
+			return PluralOne
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return PluralFew
+		}
+		return PluralMany
+	case "fr", "pt":
+		if n == 0 || n == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	case "ja", "ko", "zh", "vi", "th", "id":
+		return PluralOther
+	}
+	if n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
@@ -0,0 +1,30 @@
+package i18n
+
+import "testing"
+
+func TestPluralForm(t *testing.T) {
+	tests := []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{"en", 0, PluralOther},
+		{"en", 1, PluralOne},
+		{"en", 2, PluralOther},
+		{"ru", 1, PluralOne},
+		{"ru", 21, PluralOne},
+		{"ru", 11, PluralMany},
+		{"ru", 4, PluralFew},
+		{"ru", 14, PluralMany},
+		{"ru", 104, PluralFew},
+		{"ru", 0, PluralMany},
+		{"pl", 21, PluralMany},
+		{"fr", 0, PluralOne},
+		{"ja", 1, PluralOther},
+	}
+	for _, tt := range tests {
+		if got := PluralForm(tt.lang, tt.n); got != tt.want {
+			t.Errorf("PluralForm(%q, %d): got %q; want %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
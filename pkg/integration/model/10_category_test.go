@@ -83,6 +83,60 @@ func TestCategoryModel(t *testing.T) {
 		})
 	})
 
+	t.Run("SetFilter", func(t *testing.T) {
+		t.Run("nil category", func(t *testing.T) {
+			var nilCat *model.Category
+			if err := categoryModel.SetFilter(ctx, nilCat, model.Filter{}); err != model.ErrInvalidCategory {
+				t.Errorf("SetFilter(%v): got %q; want ErrInvalidCategory", nilCat, err)
+			}
+		})
+
+		t.Run("valid category", func(t *testing.T) {
+			filter := model.Filter{Include: []string{"inc"}, Exclude: []string{"/exc/"}}
+			if err := categoryModel.SetFilter(ctx, cat1, filter); err != nil {
+				t.Fatalf("SetFilter(%q): %v", cat1.Name, err)
+			}
+			cat, err := categoryModel.Get(ctx, cat1.ID)
+			if err != nil {
+				t.Fatalf("Get(%q): %v", cat1.Name, err)
+			}
+			if len(cat.Filter.Include) != 1 || len(cat.Filter.Exclude) != 1 {
+				t.Errorf("SetFilter(%q): got %v; want %v", cat1.Name, cat.Filter, filter)
+			}
+		})
+	})
+
+	t.Run("Save", func(t *testing.T) {
+		t.Run("nil category", func(t *testing.T) {
+			var nilCat *model.Category
+			if err := categoryModel.Save(ctx, nilCat); err != model.ErrInvalidCategory {
+				t.Errorf("Save(%v): got %q; want ErrInvalidCategory", nilCat, err)
+			}
+		})
+
+		t.Run("valid category", func(t *testing.T) {
+			cat1.Emoji = "📰"
+			cat1.Description = "Daily news"
+			cat1.SetTranslation(model.CategoryTranslation{Lang: "ru", Name: "Новости"})
+			if err := categoryModel.Save(ctx, cat1); err != nil {
+				t.Fatalf("Save(%q): %v", cat1.Name, err)
+			}
+			cat, err := categoryModel.Get(ctx, cat1.ID)
+			if err != nil {
+				t.Fatalf("Get(%q): %v", cat1.Name, err)
+			}
+			if cat.Emoji != cat1.Emoji || cat.Description != cat1.Description {
+				t.Errorf("Save(%q): got %q %q; want %q %q", cat1.Name, cat.Emoji, cat.Description, cat1.Emoji, cat1.Description)
+			}
+			if got := cat.LocalName("ru"); got != "Новости" {
+				t.Errorf("Save(%q): got translated name %q; want %q", cat1.Name, got, "Новости")
+			}
+			if len(cat.Filter.Include) != 1 {
+				t.Errorf("Save(%q): lost filter %v", cat1.Name, cat.Filter)
+			}
+		})
+	})
+
 	if _, err := categoryModel.Create(ctx, cat2); err != nil {
 		t.Fatalf("Create(%v): %v", cat2, err)
 	}
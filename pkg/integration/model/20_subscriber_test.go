@@ -9,6 +9,7 @@ import (
 	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
 	"github.com/d-ashesss/news-feed-bot/pkg/model"
 	"testing"
+	"time"
 )
 
 func TestSubscriberModel(t *testing.T) {
@@ -83,6 +84,98 @@ func TestSubscriberModel(t *testing.T) {
 		})
 	})
 
+	t.Run("GetStats", func(t *testing.T) {
+		st, err := subscriberModel.GetStats(ctx, time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("GetStats(): %v", err)
+		}
+		want := model.SubscriberStats{Total: 1, Active: 1, Joined: 1}
+		if *st != want {
+			t.Errorf("GetStats(): got %+v; want %+v", *st, want)
+		}
+	})
+
+	t.Run("HasFeedSubscribers", func(t *testing.T) {
+		f := &model.Feed{ID: "F1"}
+		if ok, err := subscriberModel.HasFeedSubscribers(ctx, f); err != nil || ok {
+			t.Errorf("HasFeedSubscribers(%q): got %v, %v; want false", f.ID, ok, err)
+		}
+		s1.AddFeed(*f)
+		if err := subscriberModel.Save(ctx, s1); err != nil {
+			t.Fatalf("Save(%q): %v", s1.UserID, err)
+		}
+		if ok, err := subscriberModel.HasFeedSubscribers(ctx, f); err != nil || !ok {
+			t.Errorf("HasFeedSubscribers(%q): got %v, %v; want true", f.ID, ok, err)
+		}
+		if _, err := subscriberModel.HasFeedSubscribers(ctx, &model.Feed{}); err != model.ErrInvalidFeed {
+			t.Errorf("HasFeedSubscribers(): got %v; want ErrInvalidFeed", err)
+		}
+	})
+
+	t.Run("GetAutoPosting", func(t *testing.T) {
+		if ss, err := subscriberModel.GetAutoPosting(ctx); err != nil || len(ss) != 0 {
+			t.Errorf("GetAutoPosting(): got %v, %v; want none", ss, err)
+		}
+		s1.AutoPost = true
+		if err := subscriberModel.Save(ctx, s1); err != nil {
+			t.Fatalf("Save(%q): %v", s1.UserID, err)
+		}
+		if ss, err := subscriberModel.GetAutoPosting(ctx); err != nil || len(ss) != 1 || ss[0].UserID != s1.UserID {
+			t.Errorf("GetAutoPosting(): got %v, %v; want %q", ss, err, s1.UserID)
+		}
+	})
+
+	t.Run("ReplaceFeed", func(t *testing.T) {
+		from, to := &model.Feed{ID: "F1"}, &model.Feed{ID: "F2"}
+		if err := subscriberModel.ReplaceFeed(ctx, from, to); err != nil {
+			t.Fatalf("ReplaceFeed(%q, %q): %v", from.ID, to.ID, err)
+		}
+		s, err := subscriberModel.Get(ctx, s1.UserID)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", s1.UserID, err)
+		}
+		if s.HasFeed(*from) || !s.HasFeed(*to) {
+			t.Errorf("ReplaceFeed(%q, %q): got feeds %v; want only %q", from.ID, to.ID, s.Feeds, to.ID)
+		}
+		s1.Feeds = s.Feeds
+	})
+
+	t.Run("ClearPruned", func(t *testing.T) {
+		s1.Pruned = 5
+		if err := subscriberModel.Save(ctx, s1); err != nil {
+			t.Fatalf("Save(%q): %v", s1.UserID, err)
+		}
+		if err := subscriberModel.ClearPruned(ctx, s1, 3); err != nil {
+			t.Fatalf("ClearPruned(%q): %v", s1.UserID, err)
+		}
+		s, err := subscriberModel.Get(ctx, s1.UserID)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", s1.UserID, err)
+		}
+		if s.Pruned != 2 || s1.Pruned != 2 {
+			t.Errorf("ClearPruned(%q): got Pruned = %d stored, %d loaded; want 2", s1.UserID, s.Pruned, s1.Pruned)
+		}
+	})
+
+	t.Run("SaveSeen", func(t *testing.T) {
+		stale := *s1
+		s1.Pruned = 7
+		if err := subscriberModel.Save(ctx, s1); err != nil {
+			t.Fatalf("Save(%q): %v", s1.UserID, err)
+		}
+		stale.Seen(time.Now().Add(model.SeenInterval), "en", "John")
+		if err := subscriberModel.SaveSeen(ctx, &stale); err != nil {
+			t.Fatalf("SaveSeen(%q): %v", s1.UserID, err)
+		}
+		s, err := subscriberModel.Get(ctx, s1.UserID)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", s1.UserID, err)
+		}
+		if s.Name != "John" || s.Language != "en" || s.Pruned != 7 {
+			t.Errorf("SaveSeen(%q): got %q, %q, Pruned = %d; want John, en, 7", s1.UserID, s.Name, s.Language, s.Pruned)
+		}
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		t.Run("nil subscriber", func(t *testing.T) {
 			if err := subscriberModel.Delete(ctx, nil); err != model.ErrInvalidSubscriber {
@@ -93,7 +186,7 @@ func TestSubscriberModel(t *testing.T) {
 		t.Run("invalid subscriber", func(t *testing.T) {
 			s := &model.Subscriber{}
 			if err := subscriberModel.Delete(ctx, s); err != model.ErrInvalidSubscriber {
-				t.Errorf("Delete(%q): got %q; want ErrInvalidSubscriber", s, err)
+				t.Errorf("Delete(%v): got %q; want ErrInvalidSubscriber", s, err)
 			}
 		})
 
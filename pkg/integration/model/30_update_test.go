@@ -6,9 +6,11 @@ package model
 import (
 	"cloud.google.com/go/firestore"
 	"context"
+	"fmt"
 	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
 	"github.com/d-ashesss/news-feed-bot/pkg/model"
 	"testing"
+	"time"
 )
 
 func TestUpdateModel(t *testing.T) {
@@ -222,6 +224,29 @@ func TestUpdateModel(t *testing.T) {
 		})
 	})
 
+	t.Run("GetOldest", func(t *testing.T) {
+		if _, err := updateModel.GetOldest(ctx, &model.Subscriber{}, 10); err != model.ErrInvalidSubscriber {
+			t.Errorf("GetOldest({}): got %q; want ErrInvalidSubscriber", err)
+		}
+		ups, err := updateModel.GetOldest(ctx, s1, 10)
+		if err != nil {
+			t.Fatalf("GetOldest(%q): %v", s1.UserID, err)
+		}
+		if len(ups) != 1 || ups[0].Category == nil || ups[0].Category.ID != cat1.ID {
+			t.Errorf("GetOldest(%q): got %v; want the update in %q", s1.UserID, ups, cat1.Name)
+		}
+	})
+
+	t.Run("GetCount", func(t *testing.T) {
+		count, err := updateModel.GetCount(ctx)
+		if err != nil {
+			t.Fatalf("GetCount(): %v", err)
+		}
+		if count < 1 {
+			t.Errorf("GetCount(): got %d updates, want the update of %q counted", count, s1.UserID)
+		}
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		t.Run("nil update", func(t *testing.T) {
 			var up *model.Update
@@ -261,6 +286,137 @@ func TestUpdateModel(t *testing.T) {
 		})
 	})
 
+	t.Run("DeleteFromCategory", func(t *testing.T) {
+		t.Run("nil subscriber", func(t *testing.T) {
+			var s *model.Subscriber
+			if _, err := updateModel.DeleteFromCategory(ctx, s, cat1); err != model.ErrInvalidSubscriber {
+				t.Errorf("DeleteFromCategory(%v): got %q; want ErrInvalidSubscriber", s, err)
+			}
+		})
+
+		t.Run("empty category", func(t *testing.T) {
+			if _, err := updateModel.DeleteFromCategory(ctx, s1, &model.Category{}); err != model.ErrInvalidCategory {
+				t.Errorf("DeleteFromCategory(%q): got %q; want ErrInvalidCategory", s1.UserID, err)
+			}
+		})
+
+		t.Run("more than a batch", func(t *testing.T) {
+			s := &model.Subscriber{UserID: "Sb"}
+			if _, err := subscriberModel.Create(ctx, s); err != nil {
+				t.Fatalf("subscriberModel.Create(%v): %v", s, err)
+			}
+			defer func(t *testing.T) {
+				t.Helper()
+				if err := subscriberModel.Delete(ctx, s); err != nil {
+					t.Fatalf("subscriberModel.Delete(%q): %v", s.UserID, err)
+				}
+			}(t)
+			const num = 510
+			for i := 0; i < num; i++ {
+				up := &model.Update{Subscriber: s, Category: cat1, Title: "Cat1Batch"}
+				if _, err := updateModel.Create(ctx, up); err != nil {
+					t.Fatalf("Create(%v): %v", up, err)
+				}
+			}
+			up := &model.Update{Subscriber: s, Category: cat2, Title: "Cat2Batch"}
+			if _, err := updateModel.Create(ctx, up); err != nil {
+				t.Fatalf("Create(%v): %v", up, err)
+			}
+
+			deleted, err := updateModel.DeleteFromCategory(ctx, s, cat1)
+			if err != nil {
+				t.Fatalf("DeleteFromCategory(%q, %q): %v", s.UserID, cat1.Name, err)
+			}
+			if deleted != num {
+				t.Errorf("DeleteFromCategory(%q, %q): got %d deleted; want %d", s.UserID, cat1.Name, deleted, num)
+			}
+			if count, _ := updateModel.GetCountInCategory(ctx, s, cat2); count != 1 {
+				t.Errorf("GetCountInCategory(%q, %q): got %d; want other categories untouched", s.UserID, cat2.Name, count)
+			}
+
+			if deleted, err := updateModel.DeleteFromCategory(ctx, s, nil); err != nil || deleted != 1 {
+				t.Errorf("DeleteFromCategory(%q, nil): got %d, %v; want 1 deleted", s.UserID, deleted, err)
+			}
+		})
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		t.Run("nil subscriber", func(t *testing.T) {
+			var s *model.Subscriber
+			if _, err := updateModel.Prune(ctx, s, cat1, time.Time{}, 0); err != model.ErrInvalidSubscriber {
+				t.Errorf("Prune(%v): got %q; want ErrInvalidSubscriber", s, err)
+			}
+		})
+
+		t.Run("old and excess updates", func(t *testing.T) {
+			s := &model.Subscriber{UserID: "Sp"}
+			if _, err := subscriberModel.Create(ctx, s); err != nil {
+				t.Fatalf("subscriberModel.Create(%v): %v", s, err)
+			}
+			defer func(t *testing.T) {
+				t.Helper()
+				if err := subscriberModel.Delete(ctx, s); err != nil {
+					t.Fatalf("subscriberModel.Delete(%q): %v", s.UserID, err)
+				}
+			}(t)
+			now := time.Now()
+			for i, age := range []time.Duration{time.Hour, 2 * time.Hour, 3 * time.Hour, 48 * time.Hour} {
+				up := &model.Update{Subscriber: s, Category: cat1, Title: fmt.Sprintf("Cat1Prune%d", i), Date: now.Add(-age)}
+				if _, err := updateModel.Create(ctx, up); err != nil {
+					t.Fatalf("Create(%v): %v", up, err)
+				}
+			}
+
+			pruned, err := updateModel.Prune(ctx, s, cat1, now.Add(-24*time.Hour), 2)
+			if err != nil {
+				t.Fatalf("Prune(%q, %q): %v", s.UserID, cat1.Name, err)
+			}
+			if pruned != 2 {
+				t.Errorf("Prune(%q, %q): got %d pruned; want 2", s.UserID, cat1.Name, pruned)
+			}
+			up, err := updateModel.GetFromCategory(ctx, s, cat1)
+			if err != nil {
+				t.Fatalf("GetFromCategory(%q, %q): %v", s.UserID, cat1.Name, err)
+			}
+			if up.Title != "Cat1Prune1" {
+				t.Errorf("GetFromCategory(%q, %q): got %q; want the oldest kept update", s.UserID, cat1.Name, up.Title)
+			}
+		})
+
+		t.Run("all categories", func(t *testing.T) {
+			s := &model.Subscriber{UserID: "Spa"}
+			if _, err := subscriberModel.Create(ctx, s); err != nil {
+				t.Fatalf("subscriberModel.Create(%v): %v", s, err)
+			}
+			defer func(t *testing.T) {
+				t.Helper()
+				if err := subscriberModel.Delete(ctx, s); err != nil {
+					t.Fatalf("subscriberModel.Delete(%q): %v", s.UserID, err)
+				}
+			}(t)
+			now := time.Now()
+			for i, cat := range []*model.Category{cat1, cat2, cat1, cat2, cat1} {
+				up := &model.Update{Subscriber: s, Category: cat, Title: fmt.Sprintf("%sPrune%d", cat.Name, i), Date: now.Add(-time.Duration(i) * time.Hour)}
+				if _, err := updateModel.Create(ctx, up); err != nil {
+					t.Fatalf("Create(%v): %v", up, err)
+				}
+			}
+
+			pruned, err := updateModel.Prune(ctx, s, nil, time.Time{}, 2)
+			if err != nil {
+				t.Fatalf("Prune(%q, nil): %v", s.UserID, err)
+			}
+			if pruned != 1 {
+				t.Errorf("Prune(%q, nil): got %d pruned; want 1 beyond the limit in %q", s.UserID, pruned, cat1.Name)
+			}
+			if n, err := updateModel.GetCountInCategory(ctx, s, cat2); err != nil {
+				t.Fatalf("GetCountInCategory(%q, %q): %v", s.UserID, cat2.Name, err)
+			} else if n != 2 {
+				t.Errorf("GetCountInCategory(%q, %q): got %d; want 2 kept", s.UserID, cat2.Name, n)
+			}
+		})
+	})
+
 	t.Run("DeleteForSubscriber", func(t *testing.T) {
 		t.Run("nil subscriber", func(t *testing.T) {
 			var s *model.Subscriber
@@ -306,4 +462,46 @@ func TestUpdateModel(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("GetPageFromCategory and Take", func(t *testing.T) {
+		s := &model.Subscriber{UserID: "Sp"}
+		if _, err := subscriberModel.Create(ctx, s); err != nil {
+			t.Fatalf("subscriberModel.Create(%v): %v", s, err)
+		}
+		defer func(t *testing.T) {
+			t.Helper()
+			if err := subscriberModel.Delete(ctx, s); err != nil {
+				t.Fatalf("subscriberModel.Delete(%q): %v", s.UserID, err)
+			}
+		}(t)
+		date := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		for i := 0; i < 3; i++ {
+			up := &model.Update{Subscriber: s, Category: cat1, Title: fmt.Sprintf("Page%d", i), Date: date.Add(time.Duration(i) * time.Hour)}
+			if _, err := updateModel.Create(ctx, up); err != nil {
+				t.Fatalf("Create(%v): %v", up, err)
+			}
+		}
+
+		ups, err := updateModel.GetPageFromCategory(ctx, s, cat1, 1, 5)
+		if err != nil {
+			t.Fatalf("GetPageFromCategory(%q, %q): %v", s.UserID, cat1.Name, err)
+		}
+		if len(ups) != 2 || ups[0].Title != "Page1" || ups[1].Title != "Page2" {
+			t.Fatalf("GetPageFromCategory(%q, %q): got %v; want Page1, Page2", s.UserID, cat1.Name, ups)
+		}
+
+		up, err := updateModel.Take(ctx, s, ups[0].ID)
+		if err != nil {
+			t.Fatalf("Take(%q): %v", ups[0].ID, err)
+		}
+		if up.Title != "Page1" || up.Category == nil || up.Category.ID != cat1.ID {
+			t.Errorf("Take(%q): got %v; want Page1 in %q", ups[0].ID, up, cat1.Name)
+		}
+		if _, err := updateModel.Take(ctx, s, ups[0].ID); err != model.ErrNotFound {
+			t.Errorf("Take(%q): got %v; want ErrNotFound for taken update", ups[0].ID, err)
+		}
+		if count, err := updateModel.GetCountInCategory(ctx, s, cat1); err != nil || count != 2 {
+			t.Errorf("GetCountInCategory(%q, %q): got %d, %v; want 2", s.UserID, cat1.Name, count, err)
+		}
+	})
 }
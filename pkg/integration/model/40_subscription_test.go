@@ -235,6 +235,192 @@ func TestSubscriptionModel(t *testing.T) {
 					cat3.ID: 0,
 				})
 			})
+
+			t.Run("inactive subscriber", func(t *testing.T) {
+				cat := model.NewCategory("Cat Inactive")
+				if _, err := categoryModel.Create(ctx, cat); err != nil {
+					t.Fatalf("failed to create category %v: %v", cat, err)
+				}
+				defer func() {
+					_ = categoryModel.Delete(ctx, cat)
+				}()
+				s := model.NewSubscriber("U Inactive")
+				if _, err := subscriberModel.Create(ctx, s); err != nil {
+					t.Fatalf("failed to create subscriber %v: %v", s, err)
+				}
+				defer func() {
+					_ = subscriberModel.Delete(ctx, s)
+				}()
+				if err := subscriptionModel.Subscribe(ctx, s, *cat); err != nil {
+					t.Fatalf("Subscribe(%q, %q): %v", s.UserID, cat.Name, err)
+				}
+				s.Deactivate("blocked")
+				if err := subscriberModel.Save(ctx, s); err != nil {
+					t.Fatalf("Save(%q): %v", s.UserID, err)
+				}
+
+				up := model.Update{Category: cat, Title: "Inactive Up1", Date: time.Now()}
+				if err := subscriptionModel.AddUpdate(ctx, up); err != nil {
+					t.Fatalf("AddUpdate(%q): %v", up.Title, err)
+				}
+				if count, _ := updateModel.GetCountInCategory(ctx, s, cat); count != 0 {
+					t.Errorf("GetCountInCategory(%q, %q): got %d; want inactive subscriber skipped", s.UserID, cat.Name, count)
+				}
+			})
+
+			t.Run("subcategory", func(t *testing.T) {
+				parent := model.NewCategory("Cat Parent")
+				if _, err := categoryModel.Create(ctx, parent); err != nil {
+					t.Fatalf("failed to create category %v: %v", parent, err)
+				}
+				defer func() {
+					_ = categoryModel.Delete(ctx, parent)
+				}()
+				child := model.NewCategory("Cat Child")
+				child.ParentID = parent.ID
+				if _, err := categoryModel.Create(ctx, child); err != nil {
+					t.Fatalf("failed to create category %v: %v", child, err)
+				}
+				defer func() {
+					_ = categoryModel.Delete(ctx, child)
+				}()
+				s := model.NewSubscriber("U Parent")
+				if _, err := subscriberModel.Create(ctx, s); err != nil {
+					t.Fatalf("failed to create subscriber %v: %v", s, err)
+				}
+				defer func() {
+					_ = subscriberModel.Delete(ctx, s)
+				}()
+				if err := subscriptionModel.Subscribe(ctx, s, *parent); err != nil {
+					t.Fatalf("Subscribe(%q, %q): %v", s.UserID, parent.Name, err)
+				}
+
+				up := model.Update{Category: child, Title: "Child Up1", Date: time.Now()}
+				if err := subscriptionModel.AddUpdate(ctx, up); err != nil {
+					t.Fatalf("AddUpdate(%q): %v", up.Title, err)
+				}
+				if count, _ := updateModel.GetCountInCategory(ctx, s, child); count != 1 {
+					t.Errorf("GetCountInCategory(%q, %q): got %d; want 1", s.UserID, child.Name, count)
+				}
+				sub, err := subscriptionModel.GetCategorySubscription(ctx, s, *parent)
+				if err != nil {
+					t.Fatalf("GetCategorySubscription(%q, %q): %v", s.UserID, parent.Name, err)
+				}
+				if sub.Unread != 1 || sub.OwnUnread != 0 || sub.Subcategories != 1 {
+					t.Errorf("GetCategorySubscription(%q, %q): got %d unread, %d own, %d subcategories; want 1, 0, 1",
+						s.UserID, parent.Name, sub.Unread, sub.OwnUnread, sub.Subcategories)
+				}
+				sub, err = subscriptionModel.GetCategorySubscription(ctx, s, *child)
+				if err != nil {
+					t.Fatalf("GetCategorySubscription(%q, %q): %v", s.UserID, child.Name, err)
+				}
+				if sub.Subscribed || !sub.Inherited {
+					t.Errorf("GetCategorySubscription(%q, %q): got subscribed %v, inherited %v; want inherited only",
+						s.UserID, child.Name, sub.Subscribed, sub.Inherited)
+				}
+			})
+
+			t.Run("feeds", func(t *testing.T) {
+				cat := model.NewCategory("Cat Feeds")
+				if _, err := categoryModel.Create(ctx, cat); err != nil {
+					t.Fatalf("failed to create category %v: %v", cat, err)
+				}
+				defer func() {
+					_ = categoryModel.Delete(ctx, cat)
+				}()
+				muting := model.NewSubscriber("U Muting")
+				if _, err := subscriberModel.Create(ctx, muting); err != nil {
+					t.Fatalf("failed to create subscriber %v: %v", muting, err)
+				}
+				defer func() {
+					_ = subscriberModel.Delete(ctx, muting)
+				}()
+				picking := model.NewSubscriber("U Picking")
+				if _, err := subscriberModel.Create(ctx, picking); err != nil {
+					t.Fatalf("failed to create subscriber %v: %v", picking, err)
+				}
+				defer func() {
+					_ = subscriberModel.Delete(ctx, picking)
+				}()
+				wanted := model.Feed{ID: "feed-wanted", Category: cat}
+				muted := model.Feed{ID: "feed-muted", Category: cat}
+				if err := subscriptionModel.Subscribe(ctx, muting, *cat); err != nil {
+					t.Fatalf("Subscribe(%q, %q): %v", muting.UserID, cat.Name, err)
+				}
+				if err := subscriptionModel.MuteFeed(ctx, muting, muted); err != nil {
+					t.Fatalf("MuteFeed(%q, %q): %v", muting.UserID, muted.ID, err)
+				}
+				if err := subscriptionModel.SubscribeFeed(ctx, picking, wanted); err != nil {
+					t.Fatalf("SubscribeFeed(%q, %q): %v", picking.UserID, wanted.ID, err)
+				}
+
+				for _, f := range []model.Feed{wanted, muted} {
+					up := model.Update{Category: cat, Source: f.ID, Title: "Feeds " + f.ID, Date: time.Now()}
+					if err := subscriptionModel.AddUpdate(ctx, up); err != nil {
+						t.Fatalf("AddUpdate(%q): %v", up.Title, err)
+					}
+				}
+				if count, _ := updateModel.GetCountInCategory(ctx, muting, cat); count != 1 {
+					t.Errorf("GetCountInCategory(%q, %q): got %d; want muted feed skipped", muting.UserID, cat.Name, count)
+				}
+				if count, _ := updateModel.GetCountInCategory(ctx, picking, cat); count != 1 {
+					t.Errorf("GetCountInCategory(%q, %q): got %d; want subscribed feed only", picking.UserID, cat.Name, count)
+				}
+			})
+		})
+
+		t.Run("SetFilter", func(t *testing.T) {
+			filter := model.Filter{Exclude: []string{cat1up1Title}}
+			if err := subscriptionModel.SetFilter(ctx, s1, *cat1, filter); err != nil {
+				t.Fatalf("SetFilter(%q, %q): %v", s1.UserID, cat1.Name, err)
+			}
+			sub, err := subscriptionModel.GetCategorySubscription(ctx, s1, *cat1)
+			if err != nil {
+				t.Fatalf("GetCategorySubscription(%q, %q): %v", s1.UserID, cat1.Name, err)
+			}
+			if sub.Unread != 1 {
+				t.Errorf("GetCategorySubscription(%q, %q): got Unread = %d; want 1 with the filter", s1.UserID, cat1.Name, sub.Unread)
+			}
+			ups, total, err := subscriptionModel.GetUnreadPage(ctx, s1, *cat1, 0, 10)
+			if err != nil {
+				t.Fatalf("GetUnreadPage(%q, %q): %v", s1.UserID, cat1.Name, err)
+			}
+			if total != 1 || len(ups) != 1 || ups[0].Title != cat1up2Title {
+				t.Errorf("GetUnreadPage(%q, %q): got %v of %d; want only %q", s1.UserID, cat1.Name, ups, total, cat1up2Title)
+			}
+
+			if err := subscriptionModel.SetFilter(ctx, s1, *cat1, model.Filter{}); err != nil {
+				t.Fatalf("SetFilter(%q, %q): %v", s1.UserID, cat1.Name, err)
+			}
+			if sub, err := subscriptionModel.GetCategorySubscription(ctx, s1, *cat1); err != nil {
+				t.Fatalf("GetCategorySubscription(%q, %q): %v", s1.UserID, cat1.Name, err)
+			} else if sub.Unread != 2 {
+				t.Errorf("GetCategorySubscription(%q, %q): got Unread = %d; want 2 once the filter is cleared", s1.UserID, cat1.Name, sub.Unread)
+			}
+		})
+
+		t.Run("GetUnreadRange", func(t *testing.T) {
+			ups, _, err := subscriptionModel.GetUnreadPage(ctx, s1, *cat1, 0, 10)
+			if err != nil {
+				t.Fatalf("GetUnreadPage(%q, %q): %v", s1.UserID, cat1.Name, err)
+			}
+			if len(ups) != 2 {
+				t.Fatalf("GetUnreadPage(%q, %q): got %d updates; want 2", s1.UserID, cat1.Name, len(ups))
+			}
+			got, err := subscriptionModel.GetUnreadRange(ctx, s1, *cat1, ups[1].Date, ups[1].Date)
+			if err != nil {
+				t.Fatalf("GetUnreadRange(%q, %q): %v", s1.UserID, cat1.Name, err)
+			}
+			if len(got) != 1 || got[0].Title != cat1up2Title {
+				t.Errorf("GetUnreadRange(%q, %q): got %v; want only %q", s1.UserID, cat1.Name, got, cat1up2Title)
+			}
+			got, err = subscriptionModel.GetUnreadRange(ctx, s1, *cat1, ups[0].Date, ups[1].Date)
+			if err != nil {
+				t.Fatalf("GetUnreadRange(%q, %q): %v", s1.UserID, cat1.Name, err)
+			}
+			if len(got) != 2 {
+				t.Errorf("GetUnreadRange(%q, %q): got %d updates; want 2", s1.UserID, cat1.Name, len(got))
+			}
 		})
 
 		t.Run("ShiftUpdate", func(t *testing.T) {
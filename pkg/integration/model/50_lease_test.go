@@ -0,0 +1,107 @@
+//go:build integration
+// +build integration
+
+package model
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"fmt"
+	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"github.com/google/uuid"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLeaseModel(t *testing.T) {
+	ctx := context.Background()
+	fsc, err := firestore.NewClient(ctx, firestore.DetectProjectID)
+	defer func(fsc *firestore.Client) {
+		_ = fsc.Close()
+	}(fsc)
+	if err != nil {
+		t.Fatalf("failed to create firestore client: %v", err)
+	}
+
+	leaseModel := firestoreDb.NewLeaseModel(fsc)
+	name := "test:" + uuid.NewString()
+	defer func() {
+		_, _ = fsc.Collection("Lease").Doc(name).Delete(ctx)
+	}()
+
+	var l1 *model.Lease
+
+	t.Run("Acquire", func(t *testing.T) {
+		l1, err = leaseModel.Acquire(ctx, name, "R1", time.Minute)
+		if err != nil {
+			t.Fatalf("Acquire(%q, R1): %v", name, err)
+		}
+		if l1.Token != 1 {
+			t.Errorf("Acquire(%q, R1): got token %d; want 1", name, l1.Token)
+		}
+
+		t.Run("taken", func(t *testing.T) {
+			if _, err := leaseModel.Acquire(ctx, name, "R2", time.Minute); err != model.ErrLeaseTaken {
+				t.Errorf("Acquire(%q, R2): got %v; want ErrLeaseTaken", name, err)
+			}
+		})
+	})
+
+	t.Run("Renew", func(t *testing.T) {
+		if err := leaseModel.Renew(ctx, l1, 2*time.Minute); err != nil {
+			t.Errorf("Renew(%q, R1): %v", name, err)
+		}
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		if err := leaseModel.Check(ctx, l1); err != nil {
+			t.Errorf("Check(%q, R1): %v", name, err)
+		}
+		stale := *l1
+		stale.Token--
+		if err := leaseModel.Check(ctx, &stale); err != model.ErrLeaseLost {
+			t.Errorf("Check(%q, stale token): got %v; want ErrLeaseLost", name, err)
+		}
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		if err := leaseModel.Release(ctx, l1); err != nil {
+			t.Fatalf("Release(%q, R1): %v", name, err)
+		}
+		if err := leaseModel.Check(ctx, l1); err != model.ErrLeaseLost {
+			t.Errorf("Check(%q, R1): got %v; want ErrLeaseLost for released lease", name, err)
+		}
+	})
+
+	t.Run("concurrent runners", func(t *testing.T) {
+		var mu sync.Mutex
+		acquired := make([]*model.Lease, 0)
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(holder string) {
+				defer wg.Done()
+				l, err := leaseModel.Acquire(ctx, name, holder, time.Minute)
+				if err == model.ErrLeaseTaken {
+					return
+				}
+				if err != nil {
+					t.Errorf("Acquire(%q, %s): %v", name, holder, err)
+					return
+				}
+				mu.Lock()
+				acquired = append(acquired, l)
+				mu.Unlock()
+			}(fmt.Sprintf("C%d", i))
+		}
+		wg.Wait()
+		if len(acquired) != 1 {
+			t.Fatalf("Acquire(%q): got %d holders; want 1", name, len(acquired))
+		}
+		if acquired[0].Token <= l1.Token {
+			t.Errorf("Acquire(%q): got token %d; want greater than %d", name, acquired[0].Token, l1.Token)
+		}
+	})
+}
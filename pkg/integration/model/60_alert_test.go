@@ -0,0 +1,93 @@
+//go:build integration
+// +build integration
+
+package model
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestAlertModel(t *testing.T) {
+	ctx := context.Background()
+	fsc, err := firestore.NewClient(ctx, firestore.DetectProjectID)
+	defer func(fsc *firestore.Client) {
+		_ = fsc.Close()
+	}(fsc)
+	if err != nil {
+		t.Fatalf("failed to create firestore client: %v", err)
+	}
+	resetData(t, ctx, fsc)
+
+	alertModel := firestoreDb.NewAlertModel(fsc)
+	subscriberModel := firestoreDb.NewSubscriberModel(fsc, firestoreDb.NewUpdateModel(fsc), alertModel)
+
+	sub := model.NewSubscriber("alert-test")
+	if _, err := subscriberModel.Create(ctx, sub); err != nil {
+		t.Fatalf("subscriberModel.Create(%v): %v", sub, err)
+	}
+	a1 := &model.Alert{Subscriber: sub, Phrase: "golang"}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("empty phrase", func(t *testing.T) {
+			a := &model.Alert{Subscriber: sub}
+			if _, err := alertModel.Create(ctx, a); err != model.ErrInvalidAlert {
+				t.Errorf("Create(%v): got %v; want ErrInvalidAlert", a, err)
+			}
+		})
+
+		t.Run("nil subscriber", func(t *testing.T) {
+			a := &model.Alert{Phrase: "golang"}
+			if _, err := alertModel.Create(ctx, a); err != model.ErrInvalidSubscriber {
+				t.Errorf("Create(%v): got %v; want ErrInvalidSubscriber", a, err)
+			}
+		})
+
+		t.Run("valid alert", func(t *testing.T) {
+			if _, err := alertModel.Create(ctx, a1); err != nil {
+				t.Fatalf("Create(%v): %v", a1, err)
+			}
+		})
+	})
+
+	t.Run("GetForSubscriber", func(t *testing.T) {
+		alerts, err := alertModel.GetForSubscriber(ctx, sub)
+		if err != nil {
+			t.Fatalf("GetForSubscriber(): %v", err)
+		}
+		if len(alerts) != 1 || alerts[0].Phrase != a1.Phrase {
+			t.Errorf("GetForSubscriber(): got %v; want [%q]", alerts, a1.Phrase)
+		}
+	})
+
+	t.Run("SetSent", func(t *testing.T) {
+		w := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		if err := alertModel.SetSent(ctx, a1, 3, w); err != nil {
+			t.Fatalf("SetSent(): %v", err)
+		}
+		alerts, err := alertModel.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("GetAll(): %v", err)
+		}
+		if len(alerts) != 1 || alerts[0].Sent != 3 || !alerts[0].WindowStart.Equal(w) {
+			t.Errorf("SetSent(): got %v; want 3 sent since %v", alerts, w)
+		}
+	})
+
+	t.Run("subscriber deleted", func(t *testing.T) {
+		if err := subscriberModel.Delete(ctx, sub); err != nil {
+			t.Fatalf("subscriberModel.Delete(): %v", err)
+		}
+		alerts, err := alertModel.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("GetAll(): %v", err)
+		}
+		if len(alerts) != 0 {
+			t.Errorf("GetAll(): got %d alerts of deleted subscriber", len(alerts))
+		}
+	})
+}
@@ -0,0 +1,96 @@
+//go:build integration
+// +build integration
+
+package model
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestBookmarkModel(t *testing.T) {
+	ctx := context.Background()
+	fsc, err := firestore.NewClient(ctx, firestore.DetectProjectID)
+	defer func(fsc *firestore.Client) {
+		_ = fsc.Close()
+	}(fsc)
+	if err != nil {
+		t.Fatalf("failed to create firestore client: %v", err)
+	}
+	resetData(t, ctx, fsc)
+
+	bookmarkModel := firestoreDb.NewBookmarkModel(fsc)
+	subscriberModel := firestoreDb.NewSubscriberModel(fsc, firestoreDb.NewUpdateModel(fsc), bookmarkModel)
+
+	sub := model.NewSubscriber("bookmark-test")
+	if _, err := subscriberModel.Create(ctx, sub); err != nil {
+		t.Fatalf("subscriberModel.Create(%v): %v", sub, err)
+	}
+	saved := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	b1 := &model.Bookmark{Subscriber: sub, Title: "First", URL: "http://localhost/1", Saved: saved}
+	b2 := &model.Bookmark{Subscriber: sub, Title: "Second", URL: "http://localhost/2", Saved: saved.Add(time.Hour)}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("empty URL", func(t *testing.T) {
+			b := &model.Bookmark{Subscriber: sub}
+			if _, err := bookmarkModel.Create(ctx, b); err != model.ErrInvalidBookmark {
+				t.Errorf("Create(%v): got %v; want ErrInvalidBookmark", b, err)
+			}
+		})
+
+		t.Run("nil subscriber", func(t *testing.T) {
+			b := &model.Bookmark{URL: "http://localhost/"}
+			if _, err := bookmarkModel.Create(ctx, b); err != model.ErrInvalidSubscriber {
+				t.Errorf("Create(%v): got %v; want ErrInvalidSubscriber", b, err)
+			}
+		})
+
+		t.Run("valid bookmark", func(t *testing.T) {
+			for _, b := range []*model.Bookmark{b1, b2} {
+				if _, err := bookmarkModel.Create(ctx, b); err != nil {
+					t.Fatalf("Create(%v): %v", b, err)
+				}
+			}
+		})
+	})
+
+	t.Run("GetForSubscriber", func(t *testing.T) {
+		bs, err := bookmarkModel.GetForSubscriber(ctx, sub)
+		if err != nil {
+			t.Fatalf("GetForSubscriber(): %v", err)
+		}
+		if len(bs) != 2 || bs[0].Title != b2.Title {
+			t.Errorf("GetForSubscriber(): got %v; want the most recently saved first", bs)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := bookmarkModel.Delete(ctx, b1); err != nil {
+			t.Fatalf("Delete(): %v", err)
+		}
+		bs, err := bookmarkModel.GetForSubscriber(ctx, sub)
+		if err != nil {
+			t.Fatalf("GetForSubscriber(): %v", err)
+		}
+		if len(bs) != 1 {
+			t.Errorf("GetForSubscriber(): got %d bookmarks; want 1", len(bs))
+		}
+	})
+
+	t.Run("subscriber deleted", func(t *testing.T) {
+		if err := subscriberModel.Delete(ctx, sub); err != nil {
+			t.Fatalf("subscriberModel.Delete(): %v", err)
+		}
+		bs, err := bookmarkModel.GetForSubscriber(ctx, sub)
+		if err != nil {
+			t.Fatalf("GetForSubscriber(): %v", err)
+		}
+		if len(bs) != 0 {
+			t.Errorf("GetForSubscriber(): got %d bookmarks of deleted subscriber", len(bs))
+		}
+	})
+}
@@ -0,0 +1,114 @@
+//go:build integration
+// +build integration
+
+package model
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestHistoryModel(t *testing.T) {
+	ctx := context.Background()
+	fsc, err := firestore.NewClient(ctx, firestore.DetectProjectID)
+	defer func(fsc *firestore.Client) {
+		_ = fsc.Close()
+	}(fsc)
+	if err != nil {
+		t.Fatalf("failed to create firestore client: %v", err)
+	}
+	resetData(t, ctx, fsc)
+
+	historyModel := firestoreDb.NewHistoryModel(fsc)
+	subscriberModel := firestoreDb.NewSubscriberModel(fsc, firestoreDb.NewUpdateModel(fsc), historyModel)
+
+	sub := model.NewSubscriber("history-test")
+	if _, err := subscriberModel.Create(ctx, sub); err != nil {
+		t.Fatalf("subscriberModel.Create(%v): %v", sub, err)
+	}
+	read := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Add", func(t *testing.T) {
+		t.Run("nil subscriber", func(t *testing.T) {
+			h := &model.HistoryItem{}
+			if err := historyModel.Add(ctx, h); err != model.ErrInvalidSubscriber {
+				t.Errorf("Add(%v): got %v; want ErrInvalidSubscriber", h, err)
+			}
+		})
+
+		t.Run("valid item", func(t *testing.T) {
+			for i := 0; i < 3; i++ {
+				h := &model.HistoryItem{Subscriber: sub, Title: "Item", Read: read.Add(time.Duration(i) * time.Hour)}
+				if err := historyModel.Add(ctx, h); err != nil {
+					t.Fatalf("Add(%v): %v", h, err)
+				}
+			}
+		})
+	})
+
+	t.Run("GetForSubscriber", func(t *testing.T) {
+		hs, err := historyModel.GetForSubscriber(ctx, sub)
+		if err != nil {
+			t.Fatalf("GetForSubscriber(): %v", err)
+		}
+		if len(hs) != 3 || !hs[0].Read.Equal(read.Add(2*time.Hour)) {
+			t.Errorf("GetForSubscriber(): got %v; want the most recently read first", hs)
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		hs, err := historyModel.GetForSubscriber(ctx, sub)
+		if err != nil || len(hs) == 0 {
+			t.Fatalf("GetForSubscriber(): got %v, %v", hs, err)
+		}
+		h, err := historyModel.Get(ctx, sub, hs[0].ID)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", hs[0].ID, err)
+		}
+		if h.ID != hs[0].ID || !h.Read.Equal(hs[0].Read) {
+			t.Errorf("Get(%q): got %v; want %v", hs[0].ID, h, hs[0])
+		}
+		other := model.NewSubscriber("history-test-other")
+		if _, err := subscriberModel.Create(ctx, other); err != nil {
+			t.Fatalf("subscriberModel.Create(%v): %v", other, err)
+		}
+		if _, err := historyModel.Get(ctx, other, hs[0].ID); err != model.ErrNotFound {
+			t.Errorf("Get(%q): got %v for other subscriber; want ErrNotFound", hs[0].ID, err)
+		}
+		if _, err := historyModel.Get(ctx, sub, "missing"); err != model.ErrNotFound {
+			t.Errorf("Get(missing): got %v; want ErrNotFound", err)
+		}
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		if err := historyModel.Prune(ctx, sub, 2, read); err != nil {
+			t.Fatalf("Prune(): %v", err)
+		}
+		hs, err := historyModel.GetForSubscriber(ctx, sub)
+		if err != nil {
+			t.Fatalf("GetForSubscriber(): %v", err)
+		}
+		if len(hs) != 2 {
+			t.Errorf("Prune(): got %d items; want 2", len(hs))
+		}
+		if err := historyModel.Prune(ctx, sub, 2, read.Add(time.Hour)); err != nil {
+			t.Fatalf("Prune(): %v", err)
+		}
+		if hs, _ := historyModel.GetForSubscriber(ctx, sub); len(hs) != 1 {
+			t.Errorf("Prune(): got %d items; want 1 read after the date", len(hs))
+		}
+	})
+
+	t.Run("subscriber deleted", func(t *testing.T) {
+		if err := subscriberModel.Delete(ctx, sub); err != nil {
+			t.Fatalf("subscriberModel.Delete(): %v", err)
+		}
+		if hs, _ := historyModel.GetForSubscriber(ctx, sub); len(hs) != 0 {
+			t.Errorf("GetForSubscriber(): got %d items of deleted subscriber", len(hs))
+		}
+	})
+}
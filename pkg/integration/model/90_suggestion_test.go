@@ -0,0 +1,133 @@
+//go:build integration
+// +build integration
+
+package model
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestSuggestionModel(t *testing.T) {
+	ctx := context.Background()
+	fsc, err := firestore.NewClient(ctx, firestore.DetectProjectID)
+	defer func(fsc *firestore.Client) {
+		_ = fsc.Close()
+	}(fsc)
+	if err != nil {
+		t.Fatalf("failed to create firestore client: %v", err)
+	}
+	resetData(t, ctx, fsc)
+
+	suggestionModel := firestoreDb.NewSuggestionModel(fsc)
+	subscriberModel := firestoreDb.NewSubscriberModel(fsc, firestoreDb.NewUpdateModel(fsc), suggestionModel)
+
+	sub := model.NewSubscriber("suggestion-test")
+	if _, err := subscriberModel.Create(ctx, sub); err != nil {
+		t.Fatalf("subscriberModel.Create(%v): %v", sub, err)
+	}
+	created := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	s1 := &model.Suggestion{Subscriber: sub, Title: "First", URL: "http://localhost/1", Created: created}
+	s2 := &model.Suggestion{Subscriber: sub, Title: "Second", URL: "http://localhost/2", Created: created.Add(time.Hour)}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("empty URL", func(t *testing.T) {
+			s := &model.Suggestion{Subscriber: sub}
+			if _, err := suggestionModel.Create(ctx, s); err != model.ErrInvalidSuggestion {
+				t.Errorf("Create(%v): got %v; want ErrInvalidSuggestion", s, err)
+			}
+		})
+
+		t.Run("nil subscriber", func(t *testing.T) {
+			s := &model.Suggestion{URL: "http://localhost/"}
+			if _, err := suggestionModel.Create(ctx, s); err != model.ErrInvalidSubscriber {
+				t.Errorf("Create(%v): got %v; want ErrInvalidSubscriber", s, err)
+			}
+		})
+
+		t.Run("valid suggestion", func(t *testing.T) {
+			for _, s := range []*model.Suggestion{s1, s2} {
+				if _, err := suggestionModel.Create(ctx, s); err != nil {
+					t.Fatalf("Create(%v): %v", s, err)
+				}
+				if s.Status != model.SuggestionPending {
+					t.Errorf("Create(): got status %q; want pending", s.Status)
+				}
+			}
+		})
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		s, err := suggestionModel.Get(ctx, s1.ID)
+		if err != nil {
+			t.Fatalf("Get(): %v", err)
+		}
+		if s.Subscriber == nil || s.Subscriber.UserID != sub.UserID {
+			t.Errorf("Get(): got subscriber %v; want %v", s.Subscriber, sub)
+		}
+		if _, err := suggestionModel.Get(ctx, "none"); err != model.ErrNotFound {
+			t.Errorf("Get(none): got %v; want ErrNotFound", err)
+		}
+	})
+
+	t.Run("Save", func(t *testing.T) {
+		s1.Reject("telegram:1", created.Add(2*time.Hour))
+		if err := suggestionModel.Save(ctx, s1); err != nil {
+			t.Fatalf("Save(): %v", err)
+		}
+		ss, err := suggestionModel.GetPending(ctx)
+		if err != nil {
+			t.Fatalf("GetPending(): %v", err)
+		}
+		if len(ss) != 1 || ss[0].ID != s2.ID {
+			t.Errorf("GetPending(): got %v; want only the second suggestion", ss)
+		}
+	})
+
+	t.Run("Moderate", func(t *testing.T) {
+		approved := *s2
+		approved.Approve("telegram:1", &model.Category{ID: "cat"}, created.Add(3*time.Hour))
+		if err := suggestionModel.Moderate(ctx, &approved); err != nil {
+			t.Fatalf("Moderate(): %v", err)
+		}
+		rejected := *s2
+		rejected.Reject("telegram:2", created.Add(3*time.Hour))
+		if err := suggestionModel.Moderate(ctx, &rejected); err != model.ErrSuggestionModerated {
+			t.Errorf("Moderate(): got %v; want ErrSuggestionModerated for the moderated suggestion", err)
+		}
+		s, err := suggestionModel.Get(ctx, s2.ID)
+		if err != nil {
+			t.Fatalf("Get(): %v", err)
+		}
+		if s.Status != model.SuggestionApproved || s.Category != "cat" {
+			t.Errorf("Moderate(): got %q into %q; want the first outcome kept", s.Status, s.Category)
+		}
+	})
+
+	t.Run("GetForSubscriber", func(t *testing.T) {
+		ss, err := suggestionModel.GetForSubscriber(ctx, sub)
+		if err != nil {
+			t.Fatalf("GetForSubscriber(): %v", err)
+		}
+		if len(ss) != 2 || ss[0].Title != s2.Title {
+			t.Errorf("GetForSubscriber(): got %v; want the most recent first", ss)
+		}
+	})
+
+	t.Run("subscriber deleted", func(t *testing.T) {
+		if err := subscriberModel.Delete(ctx, sub); err != nil {
+			t.Fatalf("subscriberModel.Delete(): %v", err)
+		}
+		ss, err := suggestionModel.GetForSubscriber(ctx, sub)
+		if err != nil {
+			t.Fatalf("GetForSubscriber(): %v", err)
+		}
+		if len(ss) != 0 {
+			t.Errorf("GetForSubscriber(): got %d suggestions of deleted subscriber", len(ss))
+		}
+	})
+}
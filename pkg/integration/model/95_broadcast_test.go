@@ -0,0 +1,83 @@
+//go:build integration
+// +build integration
+
+package model
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	firestoreDb "github.com/d-ashesss/news-feed-bot/pkg/db/firestore"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestBroadcastModel(t *testing.T) {
+	ctx := context.Background()
+	fsc, err := firestore.NewClient(ctx, firestore.DetectProjectID)
+	defer func(fsc *firestore.Client) {
+		_ = fsc.Close()
+	}(fsc)
+	if err != nil {
+		t.Fatalf("failed to create firestore client: %v", err)
+	}
+
+	broadcastModel := firestoreDb.NewBroadcastModel(fsc)
+	created := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	b1 := &model.Broadcast{Text: "First", Created: created}
+	b2 := &model.Broadcast{Text: "Second", Category: "world", Created: created.Add(time.Hour)}
+
+	t.Run("Create", func(t *testing.T) {
+		t.Run("empty text", func(t *testing.T) {
+			b := &model.Broadcast{Text: " "}
+			if _, err := broadcastModel.Create(ctx, b); err != model.ErrInvalidBroadcast {
+				t.Errorf("Create(%v): got %v; want ErrInvalidBroadcast", b, err)
+			}
+		})
+
+		t.Run("valid broadcast", func(t *testing.T) {
+			for _, b := range []*model.Broadcast{b1, b2} {
+				if _, err := broadcastModel.Create(ctx, b); err != nil {
+					t.Fatalf("Create(%v): %v", b, err)
+				}
+			}
+		})
+	})
+
+	t.Run("Save", func(t *testing.T) {
+		b1.Started = created.Add(time.Minute)
+		b1.Cursor = "subscriber"
+		b1.Delivered = 10
+		if err := broadcastModel.Save(ctx, b1); err != nil {
+			t.Fatalf("Save(): %v", err)
+		}
+		b, err := broadcastModel.Get(ctx, b1.ID)
+		if err != nil {
+			t.Fatalf("Get(): %v", err)
+		}
+		if !b.IsStarted() || b.Cursor != "subscriber" || b.Delivered != 10 {
+			t.Errorf("Get(): got %+v; want the progress saved", b)
+		}
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+		bs, err := broadcastModel.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("GetAll(): %v", err)
+		}
+		if len(bs) < 2 || bs[0].Text != b2.Text {
+			t.Errorf("GetAll(): got %v; want the most recent first", bs)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		for _, b := range []*model.Broadcast{b1, b2} {
+			if err := broadcastModel.Delete(ctx, b); err != nil {
+				t.Fatalf("Delete(): %v", err)
+			}
+		}
+		if _, err := broadcastModel.Get(ctx, b1.ID); err != model.ErrNotFound {
+			t.Errorf("Get(): got %v; want ErrNotFound", err)
+		}
+	})
+}
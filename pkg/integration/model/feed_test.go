@@ -145,11 +145,24 @@ func TestFeedModel(t *testing.T) {
 		})
 	})
 
+	t.Run("GetCount", func(t *testing.T) {
+		if _, err := feedModel.GetCount(ctx, &model.Category{}); err != model.ErrInvalidCategory {
+			t.Errorf("GetCount(): got %q; want ErrInvalidCategory", err)
+		}
+		n, err := feedModel.GetCount(ctx, cat1)
+		if err != nil {
+			t.Fatalf("GetCount(%v): %v", cat1.Name, err)
+		}
+		if n != 1 {
+			t.Errorf("GetCount(%v): got %d; want 1", cat1.Name, n)
+		}
+	})
+
 	t.Run("SetUpdated", func(t *testing.T) {
 		t.Run("nil feed", func(t *testing.T) {
 			var f *model.Feed
 			u := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-			if err := feedModel.SetUpdated(ctx, f, u); err != model.ErrInvalidFeed {
+			if err := feedModel.SetUpdated(ctx, f, u, nil); err != model.ErrInvalidFeed {
 				t.Errorf("SetUpdated(%v): got %q; want ErrInvalidFeed", f, err)
 			}
 		})
@@ -157,7 +170,7 @@ func TestFeedModel(t *testing.T) {
 		t.Run("nil category", func(t *testing.T) {
 			f := &model.Feed{ID: "test"}
 			u := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-			if err := feedModel.SetUpdated(ctx, f, u); err != model.ErrInvalidCategory {
+			if err := feedModel.SetUpdated(ctx, f, u, nil); err != model.ErrInvalidCategory {
 				t.Errorf("SetUpdated(%v): got %q; want ErrInvalidCategory", f, err)
 			}
 		})
@@ -165,14 +178,15 @@ func TestFeedModel(t *testing.T) {
 		t.Run("invalid category", func(t *testing.T) {
 			f := &model.Feed{ID: "test", Category: &model.Category{}}
 			u := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-			if err := feedModel.SetUpdated(ctx, f, u); err != model.ErrInvalidCategory {
+			if err := feedModel.SetUpdated(ctx, f, u, nil); err != model.ErrInvalidCategory {
 				t.Errorf("SetUpdated(%v): got %q; want ErrInvalidCategory", f, err)
 			}
 		})
 
 		t.Run("valid feed", func(t *testing.T) {
 			u := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-			if err := feedModel.SetUpdated(ctx, cat1f1, u); err != nil {
+			seen := []string{"guid-1", "guid-2"}
+			if err := feedModel.SetUpdated(ctx, cat1f1, u, seen); err != nil {
 				t.Fatalf("SetUpdated(%q, %q): %v", cat1f1.Title, u.Format(time.RFC3339), err)
 			}
 			f, err := feedModel.Get(ctx, cat1, cat1f1.ID)
@@ -182,6 +196,44 @@ func TestFeedModel(t *testing.T) {
 			if !f.LastUpdate.Equal(u) {
 				t.Errorf("SetUpdated(%q): got %q; want %q", cat1f1.Title, f.LastUpdate.Format(time.RFC3339), u.Format(time.RFC3339))
 			}
+			if len(f.SeenGUIDs) != len(seen) {
+				t.Errorf("SetUpdated(%q): got %d seen GUIDs; want %d", cat1f1.Title, len(f.SeenGUIDs), len(seen))
+			}
+		})
+	})
+
+	t.Run("SetSchedule", func(t *testing.T) {
+		t.Run("nil feed", func(t *testing.T) {
+			var f *model.Feed
+			next := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+			if err := feedModel.SetSchedule(ctx, f, time.Hour, next); err != model.ErrInvalidFeed {
+				t.Errorf("SetSchedule(%v): got %q; want ErrInvalidFeed", f, err)
+			}
+		})
+
+		t.Run("invalid category", func(t *testing.T) {
+			f := &model.Feed{ID: "test", Category: &model.Category{}}
+			next := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+			if err := feedModel.SetSchedule(ctx, f, time.Hour, next); err != model.ErrInvalidCategory {
+				t.Errorf("SetSchedule(%v): got %q; want ErrInvalidCategory", f, err)
+			}
+		})
+
+		t.Run("valid feed", func(t *testing.T) {
+			next := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+			if err := feedModel.SetSchedule(ctx, cat1f1, 15*time.Minute, next); err != nil {
+				t.Fatalf("SetSchedule(%q): %v", cat1f1.Title, err)
+			}
+			f, err := feedModel.Get(ctx, cat1, cat1f1.ID)
+			if err != nil {
+				t.Fatalf("Get(%q, %q): %v", cat1.Name, cat1f1.Title, err)
+			}
+			if f.Interval != 15*time.Minute {
+				t.Errorf("SetSchedule(%q): got interval %v; want %v", cat1f1.Title, f.Interval, 15*time.Minute)
+			}
+			if !f.NextFetch.Equal(next) {
+				t.Errorf("SetSchedule(%q): got %q; want %q", cat1f1.Title, f.NextFetch.Format(time.RFC3339), next.Format(time.RFC3339))
+			}
 		})
 	})
 
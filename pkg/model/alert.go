@@ -0,0 +1,50 @@
+package model
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxAlerts limits the number of alerts a Subscriber can have.
+const MaxAlerts = 10
+
+// MaxAlertPhraseLength limits the length of an alert phrase in characters.
+const MaxAlertPhraseLength = 64
+
+// Alert represents a phrase the Subscriber wants to be notified about in updates of any category.
+type Alert struct {
+	ID          string      // ID is an internal ID.
+	Subscriber  *Subscriber // Subscriber is the owner of the alert.
+	Phrase      string      // Phrase is the phrase to look for.
+	Sent        int         // Sent is the number of notifications sent in the current rate window.
+	WindowStart time.Time   // WindowStart is the start of the current rate window.
+}
+
+// AlertModel is a data model for Alert.
+type AlertModel interface {
+	// Create saves an Alert entity into the DB.
+	Create(ctx context.Context, a *Alert) (string, error)
+	// GetAll retrieves Alert entities of all subscribers from the DB.
+	GetAll(ctx context.Context) ([]Alert, error)
+	// GetForSubscriber retrieves Alert entities of the Subscriber from the DB.
+	GetForSubscriber(ctx context.Context, s *Subscriber) ([]Alert, error)
+	// SetSent saves the rate window state of an Alert.
+	SetSent(ctx context.Context, a *Alert, sent int, windowStart time.Time) error
+	// Delete deletes an Alert entity from the DB.
+	Delete(ctx context.Context, a *Alert) error
+	// DeleteForSubscriber deletes all Alert's of the Subscriber.
+	DeleteForSubscriber(ctx context.Context, s *Subscriber) error
+}
+
+// ValidateAlertPhrase checks that the phrase can be used for an Alert.
+func ValidateAlertPhrase(phrase string) error {
+	if len(strings.TrimSpace(phrase)) == 0 || utf8.RuneCountInString(phrase) > MaxAlertPhraseLength {
+		return ErrInvalidAlert
+	}
+	if strings.ContainsAny(phrase, "\n`") {
+		return ErrInvalidAlert
+	}
+	return nil
+}
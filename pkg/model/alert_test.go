@@ -0,0 +1,29 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateAlertPhrase(t *testing.T) {
+	tests := []struct {
+		name   string
+		phrase string
+		want   error
+	}{
+		{name: "word", phrase: "golang", want: nil},
+		{name: "phrase", phrase: "climate change", want: nil},
+		{name: "empty", phrase: "", want: ErrInvalidAlert},
+		{name: "spaces", phrase: "   ", want: ErrInvalidAlert},
+		{name: "too long", phrase: strings.Repeat("a", MaxAlertPhraseLength+1), want: ErrInvalidAlert},
+		{name: "multiline", phrase: "one\ntwo", want: ErrInvalidAlert},
+		{name: "markup", phrase: "`code`", want: ErrInvalidAlert},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateAlertPhrase(tt.phrase); got != tt.want {
+				t.Errorf("ValidateAlertPhrase(%q): got %v; want %v", tt.phrase, got, tt.want)
+			}
+		})
+	}
+}
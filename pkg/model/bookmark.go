@@ -0,0 +1,34 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// MaxBookmarks limits the number of bookmarks a Subscriber can have.
+const MaxBookmarks = 200
+
+// Bookmark represents an update saved by the Subscriber to read later.
+//
+//	It keeps a copy of the update, so it survives the deletion of the update and its category.
+type Bookmark struct {
+	ID         string      // ID is an internal ID.
+	Subscriber *Subscriber // Subscriber is the owner of the bookmark.
+	Title      string      // Title is the title of the update.
+	Category   string      // Category is the name of the category of the update.
+	Date       time.Time   // Date is the date when the update was published.
+	URL        string      // URL is the HTTP link to the publication.
+	Saved      time.Time   // Saved is the date when the bookmark was created.
+}
+
+// BookmarkModel is a data model for Bookmark.
+type BookmarkModel interface {
+	// Create saves a Bookmark entity into the DB.
+	Create(ctx context.Context, b *Bookmark) (string, error)
+	// GetForSubscriber retrieves Bookmark entities of the Subscriber from the DB, the most recently saved first.
+	GetForSubscriber(ctx context.Context, s *Subscriber) ([]Bookmark, error)
+	// Delete deletes a Bookmark entity from the DB.
+	Delete(ctx context.Context, b *Bookmark) error
+	// DeleteForSubscriber deletes all Bookmark's of the Subscriber.
+	DeleteForSubscriber(ctx context.Context, s *Subscriber) error
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// MaxBroadcastLength limits the length of the text of a Broadcast, as Telegram does for a message.
+const MaxBroadcastLength = 4096
+
+// Prefixes of the segments of the subscribers a Broadcast is sent to.
+const (
+	BroadcastSegmentAll      = "all"
+	BroadcastSegmentCategory = "category:"
+	BroadcastSegmentLanguage = "lang:"
+)
+
+// Broadcast represents an announcement sent by the admins to all active subscribers or a segment of them.
+//
+//	Subscribers are processed in the order of their IDs, so the delivery interrupted at the Cursor can be resumed.
+type Broadcast struct {
+	ID        string    // ID is an internal ID.
+	Text      string    // Text is the message to send.
+	Category  string    // Category limits the recipients to the subscribers of the category, its parents or subcategories.
+	Language  string    // Language limits the recipients to the subscribers speaking the language.
+	Author    string    // Author is the UserID of the admin who has created the broadcast, or the name of the tool.
+	Created   time.Time // Created is the date when the broadcast was created.
+	Started   time.Time // Started is the date when the delivery was confirmed, it is not delivered until then.
+	Finished  time.Time // Finished is the date when the delivery was complete.
+	Cursor    string    // Cursor is the ID of the last subscriber processed.
+	Delivered int       // Delivered is the number of subscribers who have received the message.
+	Blocked   int       // Blocked is the number of subscribers who can't be reached anymore.
+	Failed    int       // Failed is the number of subscribers the message failed to be sent to.
+}
+
+// IsStarted checks if the delivery of the Broadcast was confirmed.
+func (b Broadcast) IsStarted() bool {
+	return !b.Started.IsZero()
+}
+
+// IsFinished checks if the delivery of the Broadcast is complete.
+func (b Broadcast) IsFinished() bool {
+	return !b.Finished.IsZero()
+}
+
+// Total returns the number of subscribers processed so far.
+func (b Broadcast) Total() int {
+	return b.Delivered + b.Blocked + b.Failed
+}
+
+// SetSegment limits the recipients to the segment: all, category:<id> or lang:<code>.
+func (b *Broadcast) SetSegment(spec string) error {
+	spec = strings.TrimSpace(spec)
+	b.Category, b.Language = "", ""
+	switch {
+	case spec == BroadcastSegmentAll:
+	case strings.HasPrefix(spec, BroadcastSegmentCategory) && len(spec) > len(BroadcastSegmentCategory):
+		b.Category = strings.TrimPrefix(spec, BroadcastSegmentCategory)
+	case strings.HasPrefix(spec, BroadcastSegmentLanguage) && len(spec) > len(BroadcastSegmentLanguage):
+		b.Language = normalizeLanguage(strings.TrimPrefix(spec, BroadcastSegmentLanguage))
+	default:
+		return ErrInvalidBroadcast
+	}
+	return nil
+}
+
+// Segment describes the recipients in the form accepted by SetSegment.
+func (b Broadcast) Segment() string {
+	switch {
+	case len(b.Category) > 0:
+		return BroadcastSegmentCategory + b.Category
+	case len(b.Language) > 0:
+		return BroadcastSegmentLanguage + b.Language
+	}
+	return BroadcastSegmentAll
+}
+
+// Matches checks if the active Subscriber belongs to the segment of the Broadcast,
+// cats are all the categories to look up the parents and the subcategories in.
+// Channels are not sent the broadcasts as they are read by their own audience.
+func (b Broadcast) Matches(s Subscriber, cats []Category) bool {
+	if s.Inactive || s.IsChannel() {
+		return false
+	}
+	if len(b.Language) > 0 {
+		lang := normalizeLanguage(s.GetLocale())
+		if lang != b.Language && !strings.HasPrefix(lang, b.Language+"-") {
+			return false
+		}
+	}
+	if len(b.Category) > 0 {
+		related := map[string]bool{b.Category: true}
+		for _, c := range cats {
+			if c.ID != b.Category {
+				continue
+			}
+			for _, p := range CategoryParents(cats, c) {
+				related[p.ID] = true
+			}
+			for _, d := range CategoryDescendants(cats, c) {
+				related[d.ID] = true
+			}
+		}
+		for _, c := range s.Categories {
+			if related[c.ID] {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// ValidateBroadcastText checks that the text can be sent in a Broadcast.
+func ValidateBroadcastText(text string) error {
+	if len(strings.TrimSpace(text)) == 0 || len([]rune(text)) > MaxBroadcastLength {
+		return ErrInvalidBroadcast
+	}
+	return nil
+}
+
+// normalizeLanguage brings a language code like `en_US` to the form of `en-us`.
+func normalizeLanguage(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"))
+}
+
+// BroadcastModel is a data model for Broadcast.
+type BroadcastModel interface {
+	// Create saves a Broadcast entity into the DB.
+	Create(ctx context.Context, b *Broadcast) (string, error)
+	// Get retrieves a Broadcast entity from the DB.
+	Get(ctx context.Context, id string) (*Broadcast, error)
+	// GetAll retrieves all Broadcast entities from the DB, the most recent first.
+	GetAll(ctx context.Context) ([]Broadcast, error)
+	// Save updates a Broadcast entity in the DB.
+	Save(ctx context.Context, b *Broadcast) error
+	// Delete deletes a Broadcast entity from the DB.
+	Delete(ctx context.Context, b *Broadcast) error
+}
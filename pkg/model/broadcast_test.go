@@ -0,0 +1,77 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBroadcast_SetSegment(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		category string
+		language string
+		want     error
+	}{
+		{name: "all", spec: "all", want: nil},
+		{name: "category", spec: "category:world", category: "world", want: nil},
+		{name: "language", spec: "lang:pt_BR", language: "pt-br", want: nil},
+		{name: "empty category", spec: "category:", want: ErrInvalidBroadcast},
+		{name: "unknown", spec: "everyone", want: ErrInvalidBroadcast},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Broadcast{Category: "old", Language: "old"}
+			if err := b.SetSegment(tt.spec); err != tt.want {
+				t.Fatalf("SetSegment(%q): got %v; want %v", tt.spec, err, tt.want)
+			}
+			if tt.want != nil {
+				return
+			}
+			if b.Category != tt.category || b.Language != tt.language {
+				t.Errorf("SetSegment(%q): got %q, %q; want %q, %q", tt.spec, b.Category, b.Language, tt.category, tt.language)
+			}
+			if got := b.Segment(); got != strings.ToLower(strings.ReplaceAll(tt.spec, "_", "-")) {
+				t.Errorf("Segment(): got %q; want %q", got, tt.spec)
+			}
+		})
+	}
+}
+
+func TestBroadcast_Matches(t *testing.T) {
+	cats := []Category{
+		{ID: "world"},
+		{ID: "europe", ParentID: "world"},
+		{ID: "france", ParentID: "europe"},
+		{ID: "sport"},
+	}
+	tests := []struct {
+		name    string
+		segment string
+		s       Subscriber
+		want    bool
+	}{
+		{name: "all", segment: "all", s: Subscriber{}, want: true},
+		{name: "inactive", segment: "all", s: Subscriber{Inactive: true}, want: false},
+		{name: "group", segment: "all", s: Subscriber{Chat: ChatGroup}, want: true},
+		{name: "channel", segment: "all", s: Subscriber{Chat: ChatChannel}, want: false},
+		{name: "language", segment: "lang:en", s: Subscriber{Language: "en-US"}, want: true},
+		{name: "chosen language", segment: "lang:en", s: Subscriber{Language: "en", Locale: "ru"}, want: false},
+		{name: "other language", segment: "lang:en", s: Subscriber{Language: "es"}, want: false},
+		{name: "category", segment: "category:europe", s: Subscriber{Categories: []Category{{ID: "europe"}}}, want: true},
+		{name: "parent", segment: "category:europe", s: Subscriber{Categories: []Category{{ID: "world"}}}, want: true},
+		{name: "subcategory", segment: "category:europe", s: Subscriber{Categories: []Category{{ID: "france"}}}, want: true},
+		{name: "other category", segment: "category:europe", s: Subscriber{Categories: []Category{{ID: "sport"}}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Broadcast
+			if err := b.SetSegment(tt.segment); err != nil {
+				t.Fatalf("SetSegment(%q): %v", tt.segment, err)
+			}
+			if got := b.Matches(tt.s, cats); got != tt.want {
+				t.Errorf("Matches(%+v): got %v; want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,12 +2,29 @@ package model
 
 import (
 	"context"
+	"strings"
 )
 
 // Category represents a category entity.
 type Category struct {
-	ID   string // ID is an internal ID.
-	Name string // Name is a name of the category.
+	ID           string                // ID is an internal ID.
+	Name         string                // Name is a name of the category.
+	Filter       Filter                // Filter selects the updates fetched into the category.
+	Emoji        string                // Emoji is an icon shown along with the name.
+	Description  string                // Description tells what the category is about.
+	Translations []CategoryTranslation // Translations are the name and the description in other languages.
+	ParentID     string                // ParentID is the ID of the Category this one is nested in, empty for the top level.
+	Personal     bool                  // Personal marks the category of the feeds added by the subscribers, each of them gets the updates of their own feeds only.
+}
+
+// MaxCategoryDepth is the maximum number of levels of nested categories.
+const MaxCategoryDepth = 10
+
+// CategoryTranslation is the name and the description of a Category in a language.
+type CategoryTranslation struct {
+	Lang        string // Lang is a language code.
+	Name        string // Name is a name of the category in the language, the default Name is used if empty.
+	Description string // Description is a description of the category in the language, the default one is used if empty.
 }
 
 // NewCategory initializes new Category.
@@ -15,6 +32,165 @@ func NewCategory(name string) *Category {
 	return &Category{Name: name}
 }
 
+// PersonalCategoryName is the name the personal Category is created with.
+const PersonalCategoryName = "My feeds"
+
+// NewPersonalCategory initializes new Category for the feeds added by the subscribers.
+func NewPersonalCategory() *Category {
+	return &Category{Name: PersonalCategoryName, Emoji: "📌", Personal: true}
+}
+
+// FindPersonalCategory returns the Category for the feeds added by the subscribers.
+func FindPersonalCategory(cats []Category) (Category, bool) {
+	for _, cat := range cats {
+		if cat.Personal {
+			return cat, true
+		}
+	}
+	return Category{}, false
+}
+
+// GetTranslation returns the translation of the Category to the language.
+func (c Category) GetTranslation(lang string) (CategoryTranslation, bool) {
+	for _, tr := range c.Translations {
+		if strings.EqualFold(tr.Lang, lang) {
+			return tr, true
+		}
+	}
+	return CategoryTranslation{}, false
+}
+
+// LocalName returns the name of the Category in the language falling back to the default Name.
+func (c Category) LocalName(lang string) string {
+	if tr, ok := c.GetTranslation(lang); ok && len(tr.Name) > 0 {
+		return tr.Name
+	}
+	return c.Name
+}
+
+// LocalDescription returns the description of the Category in the language falling back to the default one.
+func (c Category) LocalDescription(lang string) string {
+	if tr, ok := c.GetTranslation(lang); ok && len(tr.Description) > 0 {
+		return tr.Description
+	}
+	return c.Description
+}
+
+// Label returns the name of the Category in the language prefixed with its Emoji.
+func (c Category) Label(lang string) string {
+	if len(c.Emoji) > 0 {
+		return c.Emoji + " " + c.LocalName(lang)
+	}
+	return c.LocalName(lang)
+}
+
+// HasName tells whether the name is the default or a translated name of the Category ignoring case.
+func (c Category) HasName(name string) bool {
+	if strings.EqualFold(c.Name, name) {
+		return true
+	}
+	for _, tr := range c.Translations {
+		if len(tr.Name) > 0 && strings.EqualFold(tr.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTranslation replaces the translation to its language, an empty translation is removed.
+func (c *Category) SetTranslation(tr CategoryTranslation) {
+	trs := make([]CategoryTranslation, 0, len(c.Translations)+1)
+	for _, t := range c.Translations {
+		if !strings.EqualFold(t.Lang, tr.Lang) {
+			trs = append(trs, t)
+		}
+	}
+	if len(tr.Name) > 0 || len(tr.Description) > 0 {
+		trs = append(trs, tr)
+	}
+	c.Translations = trs
+}
+
+// CategoryParents returns the categories the Category is nested in, the closest parent first.
+func CategoryParents(cats []Category, cat Category) []Category {
+	byID := make(map[string]Category, len(cats))
+	for _, c := range cats {
+		byID[c.ID] = c
+	}
+	var parents []Category
+	seen := map[string]bool{cat.ID: true}
+	for id := cat.ParentID; len(id) > 0 && !seen[id] && len(parents) < MaxCategoryDepth; {
+		p, ok := byID[id]
+		if !ok {
+			break
+		}
+		seen[id] = true
+		parents = append(parents, p)
+		id = p.ParentID
+	}
+	return parents
+}
+
+// CategoryDescendants returns the categories nested in the Category at any depth.
+func CategoryDescendants(cats []Category, cat Category) []Category {
+	var desc []Category
+	seen := map[string]bool{cat.ID: true}
+	queue := []string{cat.ID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, c := range cats {
+			if c.ParentID == id && !seen[c.ID] {
+				seen[c.ID] = true
+				desc = append(desc, c)
+				queue = append(queue, c.ID)
+			}
+		}
+	}
+	return desc
+}
+
+// ValidateCategoryParent checks that the Category can be nested in the parent without making a loop
+// or exceeding MaxCategoryDepth. An empty parentID moves the Category to the top level.
+func ValidateCategoryParent(cats []Category, cat Category, parentID string) error {
+	if len(parentID) == 0 {
+		return nil
+	}
+	if parentID == cat.ID {
+		return ErrInvalidCategoryParent
+	}
+	var parent *Category
+	for i := range cats {
+		if cats[i].ID == parentID {
+			parent = &cats[i]
+		}
+	}
+	if parent == nil {
+		return ErrInvalidCategoryParent
+	}
+	for _, c := range CategoryDescendants(cats, cat) {
+		if c.ID == parentID {
+			return ErrInvalidCategoryParent
+		}
+	}
+	depth := len(CategoryParents(cats, *parent)) + 2 + categoryHeight(cats, cat)
+	if depth > MaxCategoryDepth {
+		return ErrInvalidCategoryParent
+	}
+	return nil
+}
+
+// categoryHeight returns the number of levels of categories nested in the Category.
+func categoryHeight(cats []Category, cat Category) int {
+	height := 0
+	for _, c := range CategoryDescendants(cats, cat) {
+		if h := len(CategoryParents(cats, c)) - len(CategoryParents(cats, cat)); h > height {
+			height = h
+		}
+	}
+	return height
+}
+
 // CategoryModel is a data model for Category.
 type CategoryModel interface {
 	// Create saves a Category entity into the DB.
@@ -23,6 +199,10 @@ type CategoryModel interface {
 	Get(ctx context.Context, id string) (*Category, error)
 	// GetAll retrieves all Category entities from the DB.
 	GetAll(ctx context.Context) ([]Category, error)
+	// Save saves changes of a Category entity into the DB.
+	Save(ctx context.Context, c *Category) error
+	// SetFilter saves the Filter of a Category.
+	SetFilter(ctx context.Context, c *Category, filter Filter) error
 	// Delete deletes a Category entity from the DB.
 	Delete(ctx context.Context, c *Category) error
 }
@@ -0,0 +1,110 @@
+package model
+
+import "testing"
+
+func TestCategory_Translations(t *testing.T) {
+	c := &Category{Name: "World", Emoji: "🌍", Description: "World news"}
+	c.SetTranslation(CategoryTranslation{Lang: "ru", Name: "Мир"})
+
+	if got := c.LocalName("ru"); got != "Мир" {
+		t.Errorf("LocalName(ru): got %q; want Мир", got)
+	}
+	if got := c.LocalName("de"); got != "World" {
+		t.Errorf("LocalName(de): got %q; want the default name", got)
+	}
+	if got := c.LocalDescription("ru"); got != "World news" {
+		t.Errorf("LocalDescription(ru): got %q; want the default description", got)
+	}
+	if got := c.Label("ru"); got != "🌍 Мир" {
+		t.Errorf("Label(ru): got %q; want the name with emoji", got)
+	}
+	if !c.HasName("мир") || !c.HasName("world") || c.HasName("sport") {
+		t.Errorf("HasName(): want to match the default and the translated names only")
+	}
+
+	c.SetTranslation(CategoryTranslation{Lang: "ru", Name: "Мир", Description: "Новости мира"})
+	if len(c.Translations) != 1 || c.LocalDescription("ru") != "Новости мира" {
+		t.Errorf("SetTranslation(): got %v; want the translation replaced", c.Translations)
+	}
+	c.SetTranslation(CategoryTranslation{Lang: "ru"})
+	if len(c.Translations) != 0 {
+		t.Errorf("SetTranslation(): got %v; want an empty translation removed", c.Translations)
+	}
+}
+
+func TestCategoryParents(t *testing.T) {
+	cats := []Category{
+		{ID: "world", Name: "World"},
+		{ID: "europe", Name: "Europe", ParentID: "world"},
+		{ID: "france", Name: "France", ParentID: "europe"},
+		{ID: "asia", Name: "Asia", ParentID: "world"},
+		{ID: "loop1", Name: "Loop 1", ParentID: "loop2"},
+		{ID: "loop2", Name: "Loop 2", ParentID: "loop1"},
+	}
+
+	parents := CategoryParents(cats, cats[2])
+	if len(parents) != 2 || parents[0].ID != "europe" || parents[1].ID != "world" {
+		t.Errorf("CategoryParents(france): got %v; want europe, world", parents)
+	}
+	if parents := CategoryParents(cats, cats[0]); len(parents) != 0 {
+		t.Errorf("CategoryParents(world): got %v; want none", parents)
+	}
+	if parents := CategoryParents(cats, cats[4]); len(parents) != 1 {
+		t.Errorf("CategoryParents(loop1): got %v; want the loop to be cut", parents)
+	}
+
+	desc := CategoryDescendants(cats, cats[0])
+	if len(desc) != 3 {
+		t.Errorf("CategoryDescendants(world): got %v; want europe, asia, france", desc)
+	}
+	if desc := CategoryDescendants(cats, cats[3]); len(desc) != 0 {
+		t.Errorf("CategoryDescendants(asia): got %v; want none", desc)
+	}
+	if desc := CategoryDescendants(cats, cats[4]); len(desc) != 1 {
+		t.Errorf("CategoryDescendants(loop1): got %v; want the loop to be cut", desc)
+	}
+}
+
+func TestValidateCategoryParent(t *testing.T) {
+	cats := []Category{
+		{ID: "world", Name: "World"},
+		{ID: "europe", Name: "Europe", ParentID: "world"},
+		{ID: "asia", Name: "Asia"},
+	}
+	tests := []struct {
+		name     string
+		cat      Category
+		parentID string
+		wantErr  error
+	}{
+		{name: "top level", cat: cats[1], parentID: "", wantErr: nil},
+		{name: "valid parent", cat: cats[2], parentID: "world", wantErr: nil},
+		{name: "itself", cat: cats[0], parentID: "world", wantErr: ErrInvalidCategoryParent},
+		{name: "unknown parent", cat: cats[2], parentID: "nothing", wantErr: ErrInvalidCategoryParent},
+		{name: "subcategory", cat: cats[0], parentID: "europe", wantErr: ErrInvalidCategoryParent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCategoryParent(cats, tt.cat, tt.parentID); err != tt.wantErr {
+				t.Errorf("ValidateCategoryParent(%q, %q): got %v; want %v", tt.cat.ID, tt.parentID, err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("too deep", func(t *testing.T) {
+		cats := make([]Category, MaxCategoryDepth+1)
+		for i := range cats {
+			cats[i] = Category{ID: string(rune('a' + i))}
+			if i > 0 && i < MaxCategoryDepth {
+				cats[i].ParentID = cats[i-1].ID
+			}
+		}
+		last := cats[MaxCategoryDepth]
+		if err := ValidateCategoryParent(cats, last, cats[MaxCategoryDepth-2].ID); err != nil {
+			t.Errorf("ValidateCategoryParent(): got %v; want the last level allowed", err)
+		}
+		if err := ValidateCategoryParent(cats, last, cats[MaxCategoryDepth-1].ID); err != ErrInvalidCategoryParent {
+			t.Errorf("ValidateCategoryParent(): got %v; want ErrInvalidCategoryParent", err)
+		}
+	})
+}
@@ -6,7 +6,20 @@ var ErrInvalidSubscriber = errors.New("invalid subscriber")
 var ErrInvalidSubscriberID = errors.New("invalid subscriber ID")
 var ErrInvalidUpdate = errors.New("invalid update")
 var ErrInvalidFeed = errors.New("invalid feed")
+var ErrTooManyFeeds = errors.New("too many feeds")
 var ErrInvalidCategory = errors.New("invalid category")
 var ErrInvalidCategoryName = errors.New("invalid category name")
+var ErrInvalidCategoryParent = errors.New("invalid category parent")
 var ErrNotFound = errors.New("not found")
 var ErrNoUpdates = errors.New("no update Available")
+var ErrLeaseTaken = errors.New("lease is taken")
+var ErrLeaseLost = errors.New("lease is lost")
+var ErrInvalidPattern = errors.New("invalid pattern")
+var ErrInvalidAlert = errors.New("invalid alert")
+var ErrTooManyAlerts = errors.New("too many alerts")
+var ErrInvalidBookmark = errors.New("invalid bookmark")
+var ErrTooManyBookmarks = errors.New("too many bookmarks")
+var ErrInvalidSuggestion = errors.New("invalid suggestion")
+var ErrTooManySuggestions = errors.New("too many suggestions")
+var ErrSuggestionModerated = errors.New("suggestion is moderated already")
+var ErrInvalidBroadcast = errors.New("invalid broadcast")
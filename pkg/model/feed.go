@@ -2,15 +2,66 @@ package model
 
 import (
 	"context"
+	"net/url"
+	"strings"
 	"time"
 )
 
+// DefaultFeedInterval is a polling interval of a Feed that was never fetched.
+const DefaultFeedInterval = time.Hour
+
+// MaxSeenGUIDs limits the number of GUIDs remembered for a Feed, larger feeds also compare the item dates to the high-water mark.
+const MaxSeenGUIDs = 1000
+
+// MaxCustomFeeds limits the number of feeds a Subscriber can add.
+const MaxCustomFeeds = 10
+
 type Feed struct {
-	ID         string    // ID is an internal ID.
-	Category   *Category // Category is the category of the feed.
-	Title      string    // Title is the title of the feed.
-	URL        string    // URL is a http link to the feed.
-	LastUpdate time.Time // LastUpdate is the published time of the last update fetched from the feed.
+	ID         string        // ID is an internal ID.
+	Category   *Category     // Category is the category of the feed.
+	Title      string        // Title is the title of the feed.
+	URL        string        // URL is a http link to the feed.
+	LastUpdate time.Time     // LastUpdate is the high-water mark: the date of the newest update ingested from the feed.
+	SeenGUIDs  []string      // SeenGUIDs are the GUIDs of the items that were present in the feed on the last fetch.
+	Interval   time.Duration // Interval is the current polling interval of the feed.
+	NextFetch  time.Time     // NextFetch is the time when the feed is due to be fetched.
+	Filter     Filter        // Filter selects the updates fetched from the feed.
+}
+
+// IsDue checks if the Feed has to be fetched at the given time.
+func (f Feed) IsDue(t time.Time) bool {
+	return !f.NextFetch.After(t)
+}
+
+// GetInterval returns the polling interval of the Feed falling back to DefaultFeedInterval.
+func (f Feed) GetInterval() time.Duration {
+	if f.Interval <= 0 {
+		return DefaultFeedInterval
+	}
+	return f.Interval
+}
+
+// ValidateFeedURL checks that the URL can be used for a Feed.
+func ValidateFeedURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || len(parsed.Host) == 0 {
+		return ErrInvalidFeed
+	}
+	return nil
+}
+
+// FeedURLKey reduces the URL of a Feed to compare it to the URLs of other feeds:
+// the scheme, the fragment and the trailing slash are dropped, the host is lowercase.
+func FeedURLKey(u string) string {
+	parsed, err := url.Parse(strings.TrimSpace(u))
+	if err != nil {
+		return strings.TrimSpace(u)
+	}
+	key := strings.ToLower(parsed.Host) + strings.TrimSuffix(parsed.EscapedPath(), "/")
+	if len(parsed.RawQuery) > 0 {
+		key += "?" + parsed.RawQuery
+	}
+	return key
 }
 
 type FeedModel interface {
@@ -20,7 +71,14 @@ type FeedModel interface {
 	Get(ctx context.Context, cat *Category, id string) (*Feed, error)
 	// GetAll retrieves all Feed entities for provided Category from the DB.
 	GetAll(ctx context.Context, cat *Category) ([]Feed, error)
-	SetUpdated(ctx context.Context, f *Feed, u time.Time) error
+	// GetCount retrieves the number of Feed entities for provided Category from the DB.
+	GetCount(ctx context.Context, cat *Category) (int, error)
+	// SetUpdated saves the high-water mark of a Feed along with the GUIDs seen in it.
+	SetUpdated(ctx context.Context, f *Feed, u time.Time, seen []string) error
+	// SetSchedule saves the polling interval and the time of the next fetch of a Feed.
+	SetSchedule(ctx context.Context, f *Feed, interval time.Duration, next time.Time) error
+	// SetFilter saves the Filter of a Feed.
+	SetFilter(ctx context.Context, f *Feed, filter Filter) error
 	// Delete deletes a Feed entity from the DB. Category property has to be set on Feed entity.
 	Delete(ctx context.Context, f *Feed) error
 }
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFeed_IsDue(t *testing.T) {
+	now := time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		next time.Time
+		want bool
+	}{
+		{name: "never fetched", next: time.Time{}, want: true},
+		{name: "in the past", next: now.Add(-time.Minute), want: true},
+		{name: "now", next: now, want: true},
+		{name: "in the future", next: now.Add(time.Minute), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Feed{NextFetch: tt.next}
+			if got := f.IsDue(now); got != tt.want {
+				t.Errorf("IsDue(): got %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeed_GetInterval(t *testing.T) {
+	if got := (Feed{}).GetInterval(); got != DefaultFeedInterval {
+		t.Errorf("GetInterval(): got %v; want %v", got, DefaultFeedInterval)
+	}
+	if got := (Feed{Interval: time.Minute}).GetInterval(); got != time.Minute {
+		t.Errorf("GetInterval(): got %v; want %v", got, time.Minute)
+	}
+}
+
+func TestValidateFeedURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want error
+	}{
+		{url: "https://example.com/feed.xml", want: nil},
+		{url: "http://example.com", want: nil},
+		{url: "ftp://example.com/feed.xml", want: ErrInvalidFeed},
+		{url: "example.com/feed.xml", want: ErrInvalidFeed},
+		{url: "not a url", want: ErrInvalidFeed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := ValidateFeedURL(tt.url); got != tt.want {
+				t.Errorf("ValidateFeedURL(): got %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedURLKey(t *testing.T) {
+	want := FeedURLKey("https://example.com/feed")
+	for _, u := range []string{"http://example.com/feed", "https://EXAMPLE.com/feed/", " https://example.com/feed#top"} {
+		if got := FeedURLKey(u); got != want {
+			t.Errorf("FeedURLKey(%q): got %q; want %q", u, got, want)
+		}
+	}
+	if got := FeedURLKey("https://example.com/feed?page=2"); got == want {
+		t.Errorf("FeedURLKey(): query was dropped")
+	}
+}
@@ -0,0 +1,153 @@
+package model
+
+import (
+	"container/list"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// MaxFilterPatterns limits the number of patterns in a Filter of a Subscriber.
+const MaxFilterPatterns = 20
+
+// MaxPatternLength limits the length of a pattern in characters.
+const MaxPatternLength = 100
+
+// maxCachedPatterns limits the number of compiled regular expressions kept in the patternCache.
+const maxCachedPatterns = 1000
+
+// Filter selects updates by patterns matched against their title and summary.
+//
+//	Patterns are keywords matched case-insensitively,
+//	or regular expressions when wrapped in slashes, like /^breaking/.
+type Filter struct {
+	Include []string // Include lists patterns at least one of which an update has to match, if any.
+	Exclude []string // Exclude lists patterns none of which an update may match.
+}
+
+// Len returns the number of patterns in the Filter.
+func (f Filter) Len() int {
+	return len(f.Include) + len(f.Exclude)
+}
+
+// IsEmpty checks if the Filter lets every update through.
+func (f Filter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Match checks if the Update passes the Filter.
+func (f Filter) Match(up Update) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	text := up.Title + "\n" + up.Summary
+	for _, p := range f.Exclude {
+		if matchPattern(p, text) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, p := range f.Include {
+		if matchPattern(p, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePattern checks if the pattern can be used in a Filter.
+func ValidatePattern(p string) error {
+	if len(strings.TrimSpace(p)) == 0 || utf8.RuneCountInString(p) > MaxPatternLength {
+		return ErrInvalidPattern
+	}
+	if expr, ok := patternRegexp(p); ok {
+		if compilePattern(expr) == nil {
+			return ErrInvalidPattern
+		}
+	}
+	return nil
+}
+
+func matchPattern(p, text string) bool {
+	if expr, ok := patternRegexp(p); ok {
+		re := compilePattern(expr)
+		if re == nil {
+			return false
+		}
+		return re.MatchString(text)
+	}
+	return strings.Contains(strings.ToLower(text), strings.ToLower(p))
+}
+
+// patternCache keeps the compiled regular expressions of the recently used patterns by their expression, nil if invalid.
+var patternCache = newRegexpCache(maxCachedPatterns)
+
+// compilePattern compiles the case-insensitive regular expression of a pattern once, returns nil if it is invalid.
+func compilePattern(expr string) *regexp.Regexp {
+	if re, ok := patternCache.get(expr); ok {
+		return re
+	}
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		re = nil
+	}
+	patternCache.put(expr, re)
+	return re
+}
+
+// regexpCache is an LRU cache of the compiled regular expressions.
+type regexpCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List               // order lists the cached entries, the most recently used first.
+	items map[string]*list.Element // items are the elements of order keyed by expression.
+}
+
+type regexpCacheEntry struct {
+	expr string
+	re   *regexp.Regexp
+}
+
+func newRegexpCache(size int) *regexpCache {
+	return &regexpCache{size: size, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+// get returns the cached regular expression marking it as recently used.
+func (c *regexpCache) get(expr string) (*regexp.Regexp, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[expr]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*regexpCacheEntry).re, true
+}
+
+// put caches the regular expression removing the least recently used one once the cache is full.
+func (c *regexpCache) put(expr string, re *regexp.Regexp) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[expr]; ok {
+		e.Value.(*regexpCacheEntry).re = re
+		c.order.MoveToFront(e)
+		return
+	}
+	c.items[expr] = c.order.PushFront(&regexpCacheEntry{expr: expr, re: re})
+	for c.order.Len() > c.size {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.items, e.Value.(*regexpCacheEntry).expr)
+	}
+}
+
+// patternRegexp extracts a regular expression from the pattern wrapped in slashes.
+func patternRegexp(p string) (string, bool) {
+	if len(p) > 2 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+		return p[1 : len(p)-1], true
+	}
+	return "", false
+}
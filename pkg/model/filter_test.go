@@ -0,0 +1,87 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFilter_Match(t *testing.T) {
+	up := Update{Title: "Breaking: Elections in Europe", Summary: "Voters head to the polls"}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "empty", filter: Filter{}, want: true},
+		{name: "include keyword", filter: Filter{Include: []string{"elections"}}, want: true},
+		{name: "include summary", filter: Filter{Include: []string{"POLLS"}}, want: true},
+		{name: "include missing", filter: Filter{Include: []string{"sports"}}, want: false},
+		{name: "include any", filter: Filter{Include: []string{"sports", "europe"}}, want: true},
+		{name: "exclude keyword", filter: Filter{Exclude: []string{"breaking"}}, want: false},
+		{name: "exclude wins", filter: Filter{Include: []string{"europe"}, Exclude: []string{"voters"}}, want: false},
+		{name: "include regexp", filter: Filter{Include: []string{"/^breaking:/"}}, want: true},
+		{name: "exclude regexp", filter: Filter{Exclude: []string{"/asia|africa/"}}, want: true},
+		{name: "invalid regexp", filter: Filter{Include: []string{"/(/"}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(up); got != tt.want {
+				t.Errorf("Match(): got %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{pattern: "keyword", wantErr: false},
+		{pattern: "/^re(gexp)?$/", wantErr: false},
+		{pattern: "/(/", wantErr: true},
+		{pattern: "  ", wantErr: true},
+		{pattern: strings.Repeat("x", MaxPatternLength+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if err := ValidatePattern(tt.pattern); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePattern(%q): got %v", tt.pattern, err)
+			}
+		})
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	re := compilePattern("^cached$")
+	if re == nil || !re.MatchString("CACHED") {
+		t.Fatalf("compilePattern(): got %v; want case-insensitive regexp", re)
+	}
+	if again := compilePattern("^cached$"); again != re {
+		t.Errorf("compilePattern(): the expression was compiled again")
+	}
+	if re := compilePattern("("); re != nil {
+		t.Errorf("compilePattern(): got %v for invalid expression; want nil", re)
+	}
+}
+
+func TestRegexpCache(t *testing.T) {
+	c := newRegexpCache(2)
+	for i := 0; i < 3; i++ {
+		expr := fmt.Sprintf("^%d$", i)
+		if i == 2 {
+			// the first expression is used again, so the second one is the least recently used
+			c.get("^0$")
+		}
+		c.put(expr, compilePattern(expr))
+	}
+	if len(c.items) != 2 || c.order.Len() != 2 {
+		t.Errorf("regexpCache: got %d items; want 2", len(c.items))
+	}
+	for expr, want := range map[string]bool{"^0$": true, "^1$": false, "^2$": true} {
+		if _, ok := c.get(expr); ok != want {
+			t.Errorf("regexpCache.get(%q): got cached %v; want %v", expr, ok, want)
+		}
+	}
+}
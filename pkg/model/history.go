@@ -0,0 +1,71 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// Default limits of the reading history.
+const (
+	DefaultHistoryLimit  = 100
+	DefaultHistoryMaxAge = 30 * 24 * time.Hour
+)
+
+// HistoryItem represents an update delivered to the Subscriber.
+//
+//	It keeps a copy of the update, so it can be shown again after the update was deleted.
+type HistoryItem struct {
+	ID         string      // ID is an internal ID.
+	Subscriber *Subscriber // Subscriber is the reader of the update.
+	CategoryID string      // CategoryID is the ID of the category of the update.
+	Title      string      // Title is the title of the update.
+	Summary    string      // Summary is the short description of the update.
+	Date       time.Time   // Date is the date when the update was published.
+	URL        string      // URL is the HTTP link to the publication.
+	Image      string      // Image is the HTTP link to the illustration of the publication.
+	Read       time.Time   // Read is the date when the update was delivered.
+}
+
+// NewHistoryItem initializes a HistoryItem for the update delivered to the Subscriber.
+func NewHistoryItem(s *Subscriber, up *Update, read time.Time) *HistoryItem {
+	h := &HistoryItem{
+		Subscriber: s,
+		Title:      up.Title,
+		Summary:    up.Summary,
+		Date:       up.Date,
+		URL:        up.URL,
+		Image:      up.Image,
+		Read:       read,
+	}
+	if up.Category != nil {
+		h.CategoryID = up.Category.ID
+	}
+	return h
+}
+
+// Update restores the delivered update.
+func (h HistoryItem) Update(cat *Category) *Update {
+	return &Update{
+		Subscriber: h.Subscriber,
+		Category:   cat,
+		Title:      h.Title,
+		Summary:    h.Summary,
+		Date:       h.Date,
+		URL:        h.URL,
+		Image:      h.Image,
+	}
+}
+
+// HistoryModel is a data model for HistoryItem.
+type HistoryModel interface {
+	// Add saves a HistoryItem entity into the DB.
+	Add(ctx context.Context, h *HistoryItem) error
+	// Get retrieves the HistoryItem of the Subscriber from the DB.
+	Get(ctx context.Context, s *Subscriber, id string) (*HistoryItem, error)
+	// GetForSubscriber retrieves HistoryItem entities of the Subscriber from the DB, the most recently read first.
+	GetForSubscriber(ctx context.Context, s *Subscriber) ([]HistoryItem, error)
+	// Prune keeps only the given number of the most recent HistoryItem's of the Subscriber read after the given date.
+	Prune(ctx context.Context, s *Subscriber, keep int, after time.Time) error
+	// DeleteForSubscriber deletes all HistoryItem's of the Subscriber.
+	DeleteForSubscriber(ctx context.Context, s *Subscriber) error
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistoryItem_Update(t *testing.T) {
+	cat := &Category{ID: "cat", Name: "Cat"}
+	s := &Subscriber{ID: "sub"}
+	up := &Update{
+		Category: cat,
+		Title:    "Title",
+		Summary:  "Summary",
+		Date:     time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		URL:      "http://localhost/",
+		Image:    "http://localhost/image.png",
+	}
+	h := NewHistoryItem(s, up, time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC))
+	if h.CategoryID != cat.ID {
+		t.Errorf("NewHistoryItem(): got category %q; want %q", h.CategoryID, cat.ID)
+	}
+	got := h.Update(cat)
+	if got.Subscriber != s || *got != (Update{Subscriber: s, Category: cat, Title: up.Title, Summary: up.Summary, Date: up.Date, URL: up.URL, Image: up.Image}) {
+		t.Errorf("Update(): got %v; want %v", got, up)
+	}
+}
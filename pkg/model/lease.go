@@ -0,0 +1,26 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// Lease represents an exclusive right to work on a named resource for a limited time.
+type Lease struct {
+	Name    string    // Name identifies the leased resource.
+	Holder  string    // Holder is an ID of the runner owning the lease.
+	Token   int64     // Token is a fencing token, it grows every time the lease is acquired.
+	Expires time.Time // Expires is the time after which the lease can be taken by another holder.
+}
+
+// LeaseModel is a data model for Lease.
+type LeaseModel interface {
+	// Acquire takes the lease for the holder if it is free or expired, returns ErrLeaseTaken otherwise.
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (*Lease, error)
+	// Renew extends the lease if it is still held with the same token, returns ErrLeaseLost otherwise.
+	Renew(ctx context.Context, l *Lease, ttl time.Duration) error
+	// Check verifies the lease is still held with the same token, returns ErrLeaseLost otherwise.
+	Check(ctx context.Context, l *Lease) error
+	// Release frees the lease if it is still held with the same token, returns ErrLeaseLost otherwise.
+	Release(ctx context.Context, l *Lease) error
+}
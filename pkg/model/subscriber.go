@@ -1,17 +1,136 @@
 package model
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Subscriber represents subscriber entitiy.
 type Subscriber struct {
-	ID         string     // ID is an internal DB ID of the user.
-	UserID     string     // UserID is an external ID of the user. Like Telegram user ID.
-	Categories []Category // Categories is a list of Category'ies the user is subscribed to.
+	ID         string               // ID is an internal DB ID of the user.
+	UserID     string               // UserID is an external ID of the user. Like Telegram user ID, or chat ID for the groups and the channels.
+	Chat       string               // Chat is the kind of the chat the updates are sent to, ChatPrivate for a user.
+	Categories []Category           // Categories is a list of Category'ies the user is subscribed to.
+	Filters    []SubscriptionFilter // Filters is a list of Filter's the user has set for the categories.
+	Feeds      []string             // Feeds is a list of IDs of the Feed's the user is subscribed to apart from their categories.
+	MutedFeeds []string             // MutedFeeds is a list of IDs of the Feed's the user does not want from the subscribed categories.
+	Input      string               // Input is a kind of text input the user is expected to send next.
+	PageSize   int                  // PageSize is the number of updates per page in the list view, DefaultPageSize if not set.
+	Pruned     int                  // Pruned is the number of unread updates dropped by the retention policy the user was not told about yet.
+	Inactive   bool                 // Inactive is set when the user can not be reached anymore, e.g. has blocked the bot.
+	Reason     string               // Reason is why the user was marked Inactive.
+	Created    time.Time            // Created is when the user has started using the bot.
+	LastSeen   time.Time            // LastSeen is when the user has interacted with the bot last time, up to SeenInterval.
+	Language   string               // Language is the language code of the user's client.
+	Name       string               // Name is the display name of the user.
+	Locale     string               // Locale is the language of the bot chosen by the user, the Language of the client is used if not set.
+	AutoPost   bool                 // AutoPost makes the bot post every new update of the subscribed categories to the channel.
+	Template   string               // Template is the format of the updates posted to the channel, the default one is used if empty.
+}
+
+// Kinds of the chats the Subscriber can be.
+const (
+	ChatPrivate = ""
+	ChatGroup   = "group"
+	ChatChannel = "channel"
+)
+
+// SeenInterval is how often the LastSeen time of an active Subscriber is saved.
+const SeenInterval = time.Hour
+
+// PageSizes are the page sizes of the list view the Subscriber can choose from.
+var PageSizes = []int{3, 5, 10}
+
+// DefaultPageSize is the page size of the list view used by default.
+const DefaultPageSize = 5
+
+// SubscriptionFilter is a Filter the Subscriber has set for a Category.
+type SubscriptionFilter struct {
+	CategoryID string
+	Filter     Filter
 }
 
 // NewSubscriber initializes new Subscriber.
 func NewSubscriber(userID string) *Subscriber {
-	return &Subscriber{UserID: userID}
+	return &Subscriber{UserID: userID, Created: time.Now()}
+}
+
+// Seen records an interaction of the Subscriber at the time, it tells whether there are changes worth saving.
+//
+//	Created of the subscribers saved before it was introduced is set to the time.
+func (s *Subscriber) Seen(now time.Time, lang, name string) bool {
+	changed := false
+	if s.Created.IsZero() {
+		s.Created = now
+		changed = true
+	}
+	if now.Sub(s.LastSeen) >= SeenInterval {
+		s.LastSeen = now
+		changed = true
+	}
+	if lang != "" && lang != s.Language {
+		s.Language = lang
+		changed = true
+	}
+	if name != "" && name != s.Name {
+		s.Name = name
+		changed = true
+	}
+	return changed
+}
+
+// IsGroup tells whether the Subscriber is a group chat.
+func (s *Subscriber) IsGroup() bool {
+	return s.Chat == ChatGroup
+}
+
+// IsChannel tells whether the Subscriber is a channel.
+func (s *Subscriber) IsChannel() bool {
+	return s.Chat == ChatChannel
+}
+
+// IsAutoPosting tells whether the updates are to be posted to the channel as they come.
+func (s *Subscriber) IsAutoPosting() bool {
+	return s.IsChannel() && s.AutoPost && !s.Inactive
+}
+
+// GetPageSize returns the page size of the list view chosen by the Subscriber.
+func (s *Subscriber) GetPageSize() int {
+	if s.PageSize <= 0 {
+		return DefaultPageSize
+	}
+	return s.PageSize
+}
+
+// NextPageSize returns the page size of the list view following the current one in PageSizes.
+func (s *Subscriber) NextPageSize() int {
+	cur := s.GetPageSize()
+	for _, size := range PageSizes {
+		if size > cur {
+			return size
+		}
+	}
+	return PageSizes[0]
+}
+
+// Deactivate marks the Subscriber as no longer reachable for the reason.
+func (s *Subscriber) Deactivate(reason string) {
+	s.Inactive = true
+	s.Reason = reason
+}
+
+// Activate marks the Subscriber as reachable again.
+func (s *Subscriber) Activate() {
+	s.Inactive = false
+	s.Reason = ""
+}
+
+// GetLocale returns the language the bot should speak to the Subscriber.
+func (s *Subscriber) GetLocale() string {
+	if len(s.Locale) > 0 {
+		return s.Locale
+	}
+	return s.Language
 }
 
 // AddCategory adds a Category to the list of Subscriber's subscriptions.
@@ -39,12 +158,127 @@ func (s *Subscriber) HasCategory(c Category) bool {
 	return false
 }
 
+// AddFeed subscribes the Subscriber to a single Feed, the Feed is no longer muted.
+func (s *Subscriber) AddFeed(f Feed) {
+	s.MutedFeeds = removeID(s.MutedFeeds, f.ID)
+	if !s.HasFeed(f) {
+		s.Feeds = append(s.Feeds, f.ID)
+	}
+}
+
+// RemoveFeed unsubscribes the Subscriber from a single Feed.
+func (s *Subscriber) RemoveFeed(f Feed) {
+	s.Feeds = removeID(s.Feeds, f.ID)
+}
+
+// HasFeed tells whether the Subscriber is subscribed to a single Feed.
+func (s *Subscriber) HasFeed(f Feed) bool {
+	return hasID(s.Feeds, f.ID)
+}
+
+// MuteFeed stops the updates of a Feed from reaching the Subscriber, the subscription to the Feed is dropped.
+func (s *Subscriber) MuteFeed(f Feed) {
+	s.Feeds = removeID(s.Feeds, f.ID)
+	if !s.IsMuted(f) {
+		s.MutedFeeds = append(s.MutedFeeds, f.ID)
+	}
+}
+
+// UnmuteFeed lets the updates of a Feed reach the Subscriber again.
+func (s *Subscriber) UnmuteFeed(f Feed) {
+	s.MutedFeeds = removeID(s.MutedFeeds, f.ID)
+}
+
+// IsMuted tells whether the Subscriber has muted a Feed.
+func (s *Subscriber) IsMuted(f Feed) bool {
+	return hasID(s.MutedFeeds, f.ID)
+}
+
+// GetFilter returns the Filter the Subscriber has set for a Category.
+func (s *Subscriber) GetFilter(c Category) Filter {
+	for _, f := range s.Filters {
+		if f.CategoryID == c.ID {
+			return f.Filter
+		}
+	}
+	return Filter{}
+}
+
+// SetFilter sets the Filter for a Category, an empty Filter is removed.
+func (s *Subscriber) SetFilter(c Category, filter Filter) {
+	filters := make([]SubscriptionFilter, 0, len(s.Filters)+1)
+	for _, f := range s.Filters {
+		if f.CategoryID != c.ID {
+			filters = append(filters, f)
+		}
+	}
+	if !filter.IsEmpty() {
+		filters = append(filters, SubscriptionFilter{CategoryID: c.ID, Filter: filter})
+	}
+	s.Filters = filters
+}
+
+// SubscriberDataModel is a data model keeping entities that belong to a Subscriber.
+type SubscriberDataModel interface {
+	// DeleteForSubscriber deletes all entities of the Subscriber.
+	DeleteForSubscriber(ctx context.Context, s *Subscriber) error
+}
+
+// SubscriberStats are the totals of the Subscribers.
+type SubscriberStats struct {
+	Total  int // Total is the number of all subscribers.
+	Active int // Active is the number of subscribers the bot can reach.
+	Joined int // Joined is the number of subscribers who started using the bot since the given time.
+	Seen   int // Seen is the number of subscribers who used the bot since the given time.
+}
+
 // SubscriberModel is a data model for Subscriber.
 type SubscriberModel interface {
 	// Create saves a Subscriber entity into the DB.
 	Create(ctx context.Context, s *Subscriber) (string, error)
 	// Get retrieves a Subscriber entity from the DB by external UserID.
 	Get(ctx context.Context, id string) (*Subscriber, error)
+	// GetAll retrieves all Subscriber entities from the DB.
+	GetAll(ctx context.Context) ([]Subscriber, error)
+	// GetAutoPosting retrieves the Subscriber entities with AutoPost set from the DB.
+	GetAutoPosting(ctx context.Context) ([]Subscriber, error)
+	// GetStats counts the Subscribers, the joined and seen ones are counted since the given time.
+	GetStats(ctx context.Context, since time.Time) (*SubscriberStats, error)
+	// Save saves changes of a Subscriber entity into the DB.
+	Save(ctx context.Context, s *Subscriber) error
+	// HasFeedSubscribers tells whether any Subscriber is subscribed to the Feed apart from the categories.
+	HasFeedSubscribers(ctx context.Context, f *Feed) (bool, error)
+	// ReplaceFeed subscribes the Subscribers of a Feed apart from its Category to another Feed instead.
+	ReplaceFeed(ctx context.Context, from, to *Feed) error
+	// Deactivate marks the Subscriber as no longer reachable for the reason leaving the rest of the entity intact.
+	Deactivate(ctx context.Context, s *Subscriber, reason string) error
+	// SaveSeen saves the fields of a Subscriber entity changed by an interaction: Created, LastSeen, Language,
+	//   Name, Chat and the inactive state, the rest could have been changed since the Subscriber was loaded.
+	SaveSeen(ctx context.Context, s *Subscriber) error
+	// ClearPruned subtracts the number of pruned updates the Subscriber was told about from Pruned,
+	//   the updates pruned meanwhile are kept to be told about later.
+	ClearPruned(ctx context.Context, s *Subscriber, n int) error
 	// Delete deletes a Subscriber entity from the DB.
 	Delete(ctx context.Context, s *Subscriber) error
 }
+
+// hasID tells whether the list of IDs contains the ID.
+func hasID(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// removeID returns the list of IDs without the ID.
+func removeID(ids []string, id string) []string {
+	res := make([]string, 0, len(ids))
+	for _, i := range ids {
+		if i != id {
+			res = append(res, i)
+		}
+	}
+	return res
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 )
 
 func TestSubscriber_AddCategory(t *testing.T) {
@@ -42,3 +43,122 @@ func TestSubscriber_HasCategory(t *testing.T) {
 		}
 	})
 }
+
+func TestSubscriber_MuteFeed(t *testing.T) {
+	f := Feed{ID: "test-feed"}
+	s := &Subscriber{}
+
+	s.AddFeed(f)
+	s.AddFeed(f)
+	if len(s.Feeds) != 1 || !s.HasFeed(f) {
+		t.Fatalf("AddFeed(): got feeds %v; want %q once", s.Feeds, f.ID)
+	}
+	s.MuteFeed(f)
+	if s.HasFeed(f) || !s.IsMuted(f) {
+		t.Errorf("MuteFeed(): got feeds %v, muted %v; want muted only", s.Feeds, s.MutedFeeds)
+	}
+	s.AddFeed(f)
+	if !s.HasFeed(f) || s.IsMuted(f) {
+		t.Errorf("AddFeed(): got feeds %v, muted %v; want subscribed only", s.Feeds, s.MutedFeeds)
+	}
+	s.RemoveFeed(f)
+	s.UnmuteFeed(f)
+	if len(s.Feeds) != 0 || len(s.MutedFeeds) != 0 {
+		t.Errorf("RemoveFeed(): got feeds %v, muted %v; want none", s.Feeds, s.MutedFeeds)
+	}
+}
+
+func TestSubscriber_SetFilter(t *testing.T) {
+	c1 := Category{ID: "test-cat-1"}
+	c2 := Category{ID: "test-cat-2"}
+	s := &Subscriber{}
+
+	s.SetFilter(c1, Filter{Include: []string{"a"}})
+	s.SetFilter(c2, Filter{Exclude: []string{"b"}})
+	s.SetFilter(c1, Filter{Include: []string{"c"}})
+	if len(s.Filters) != 2 {
+		t.Fatalf("SetFilter(): got %d filters; want 2", len(s.Filters))
+	}
+	if got := s.GetFilter(c1); len(got.Include) != 1 || got.Include[0] != "c" {
+		t.Errorf("GetFilter(%v): got %v", c1, got)
+	}
+
+	s.SetFilter(c2, Filter{})
+	if len(s.Filters) != 1 {
+		t.Errorf("SetFilter(): empty filter was not removed")
+	}
+	if got := s.GetFilter(c2); !got.IsEmpty() {
+		t.Errorf("GetFilter(%v): got %v; want empty filter", c2, got)
+	}
+}
+
+func TestSubscriber_NextPageSize(t *testing.T) {
+	s := &Subscriber{}
+	if got := s.GetPageSize(); got != DefaultPageSize {
+		t.Errorf("GetPageSize(): got %d; want default %d", got, DefaultPageSize)
+	}
+	seen := map[int]bool{}
+	for range PageSizes {
+		s.PageSize = s.NextPageSize()
+		seen[s.PageSize] = true
+	}
+	if len(seen) != len(PageSizes) || s.PageSize != DefaultPageSize {
+		t.Errorf("NextPageSize(): got %v after a full cycle ending at %d; want all of %v", seen, s.PageSize, PageSizes)
+	}
+}
+
+func TestSubscriber_Deactivate(t *testing.T) {
+	s := &Subscriber{}
+	s.Deactivate("blocked")
+	if !s.Inactive || s.Reason != "blocked" {
+		t.Errorf("Deactivate(): got inactive %v, reason %q; want inactive, blocked", s.Inactive, s.Reason)
+	}
+	s.Activate()
+	if s.Inactive || s.Reason != "" {
+		t.Errorf("Activate(): got inactive %v, reason %q; want active without reason", s.Inactive, s.Reason)
+	}
+}
+
+func TestSubscriber_IsAutoPosting(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Subscriber
+		want bool
+	}{
+		{name: "channel", s: Subscriber{Chat: ChatChannel, AutoPost: true}, want: true},
+		{name: "off", s: Subscriber{Chat: ChatChannel}, want: false},
+		{name: "inactive", s: Subscriber{Chat: ChatChannel, AutoPost: true, Inactive: true}, want: false},
+		{name: "group", s: Subscriber{Chat: ChatGroup, AutoPost: true}, want: false},
+		{name: "private", s: Subscriber{AutoPost: true}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.IsAutoPosting(); got != tt.want {
+				t.Errorf("IsAutoPosting(): got %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscriber_Seen(t *testing.T) {
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &Subscriber{}
+	if !s.Seen(now, "en", "John") {
+		t.Errorf("Seen(): got no changes for a new subscriber")
+	}
+	if s.Language != "en" || s.Name != "John" || !s.LastSeen.Equal(now) {
+		t.Errorf("Seen(): got %q, %q, %v; want en, John, %v", s.Language, s.Name, s.LastSeen, now)
+	}
+	if s.Seen(now.Add(time.Minute), "en", "John") {
+		t.Errorf("Seen(): got changes within SeenInterval")
+	}
+	if !s.Seen(now.Add(time.Minute), "", "John Doe") || s.Language != "en" {
+		t.Errorf("Seen(): got language %q; want changed name to keep the language", s.Language)
+	}
+	if !s.Seen(now.Add(SeenInterval), "en", "John Doe") || !s.LastSeen.Equal(now.Add(SeenInterval)) {
+		t.Errorf("Seen(): got LastSeen %v; want it updated after SeenInterval", s.LastSeen)
+	}
+	if !s.Created.Equal(now) {
+		t.Errorf("Seen(): got Created %v; want %v of the first interaction", s.Created, now)
+	}
+}
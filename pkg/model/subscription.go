@@ -1,26 +1,180 @@
 package model
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// Default retention policy of unread updates.
+const (
+	DefaultRetentionMaxAge    = 30 * 24 * time.Hour
+	DefaultRetentionMaxUnread = 500
+)
 
 // Subscription represents a status of subscription for a single Category.
 type Subscription struct {
-	Category   Category // Category is a Category in question.
-	Subscribed bool     // Subscribed shows if a Subscriber is subscribed to current Category.
-	Unread     int      // Unread shows count of unread updates from current Category.
+	Category      Category // Category is a Category in question.
+	Subscribed    bool     // Subscribed shows if a Subscriber is subscribed to current Category.
+	Inherited     bool     // Inherited shows if a Subscriber is subscribed to one of the parents of current Category.
+	Unread        int      // Unread shows count of unread updates from current Category and its subcategories.
+	OwnUnread     int      // OwnUnread shows count of unread updates from current Category itself.
+	Subcategories int      // Subcategories is the number of categories nested directly in current Category.
+}
+
+// Receives tells whether a Subscriber gets the updates from current Category, directly or through a parent.
+func (sub Subscription) Receives() bool {
+	return sub.Subscribed || sub.Inherited
+}
+
+// FeedSubscription represents a status of subscription for a single Feed.
+type FeedSubscription struct {
+	Feed       Feed // Feed is a Feed in question.
+	Subscribed bool // Subscribed shows if a Subscriber is subscribed to current Feed apart from its Category.
+	Inherited  bool // Inherited shows if a Subscriber is subscribed to the Category of current Feed or one of its parents.
+	Muted      bool // Muted shows if a Subscriber does not want the updates of current Feed.
+}
+
+// Receives tells whether a Subscriber gets the updates from current Feed.
+func (sub FeedSubscription) Receives() bool {
+	return !sub.Muted && (sub.Subscribed || sub.Inherited)
+}
+
+// FeedSubscriptions returns the subscription status of the feeds of a Category for the Subscriber,
+// inherited tells whether the Subscriber receives the updates from the Category.
+func FeedSubscriptions(s *Subscriber, feeds []Feed, inherited bool) []FeedSubscription {
+	subs := make([]FeedSubscription, len(feeds))
+	for i, f := range feeds {
+		subs[i] = FeedSubscription{Feed: f, Subscribed: s.HasFeed(f), Inherited: inherited, Muted: s.IsMuted(f)}
+	}
+	return subs
+}
+
+// AggregateSubscriptions fills in Inherited, Unread and Subcategories of the subscriptions
+// from the OwnUnread and Subscribed of their parents and subcategories.
+func AggregateSubscriptions(subs []Subscription) {
+	cats := make([]Category, len(subs))
+	index := make(map[string]int, len(subs))
+	for i := range subs {
+		cats[i] = subs[i].Category
+		index[subs[i].Category.ID] = i
+		subs[i].Inherited = false
+		subs[i].Unread = subs[i].OwnUnread
+		subs[i].Subcategories = 0
+	}
+	for i := range subs {
+		if p, ok := index[subs[i].Category.ParentID]; ok && p != i {
+			subs[p].Subcategories++
+		}
+		for _, parent := range CategoryParents(cats, subs[i].Category) {
+			p := index[parent.ID]
+			subs[p].Unread += subs[i].OwnUnread
+			if subs[p].Subscribed {
+				subs[i].Inherited = true
+			}
+		}
+	}
+}
+
+// FindSubscription returns the subscription to the Category with the ID.
+func FindSubscription(subs []Subscription, id string) (Subscription, bool) {
+	for _, sub := range subs {
+		if sub.Category.ID == id {
+			return sub, true
+		}
+	}
+	return Subscription{}, false
+}
+
+// Subscriptions returns the subscriptions to the categories nested directly in the parent, empty for the top level.
+func Subscriptions(subs []Subscription, parentID string) []Subscription {
+	level := make([]Subscription, 0)
+	for _, sub := range subs {
+		if sub.Category.ParentID == parentID && sub.Category.ID != parentID {
+			level = append(level, sub)
+		}
+	}
+	return level
+}
+
+// ReceivedSubscriptions returns the subscriptions to the categories nested directly in the parent, empty for
+// the top level, the Subscriber receives updates from or that have such categories nested. Categories with
+// unread updates are received too, e.g. through the subscriptions to their feeds.
+func ReceivedSubscriptions(subs []Subscription, parentID string) []Subscription {
+	cats := make([]Category, len(subs))
+	for i := range subs {
+		cats[i] = subs[i].Category
+	}
+	visible := make(map[string]bool)
+	for _, sub := range subs {
+		if !sub.Receives() && sub.OwnUnread == 0 {
+			continue
+		}
+		visible[sub.Category.ID] = true
+		for _, parent := range CategoryParents(cats, sub.Category) {
+			visible[parent.ID] = true
+		}
+	}
+	level := make([]Subscription, 0)
+	for _, sub := range Subscriptions(subs, parentID) {
+		if visible[sub.Category.ID] {
+			level = append(level, sub)
+		}
+	}
+	return level
 }
 
 // SubscriptionModel is a data model for Subscription.
 type SubscriptionModel interface {
-	// Subscribe subsribes the Subscriber to a Category
+	// Subscribe subsribes the Subscriber to a Category, the personal Category can not be subscribed to.
 	Subscribe(ctx context.Context, s *Subscriber, cat Category) error
 	// Unsubscribe unsubscribes the Subscriber from a Category
 	Unsubscribe(ctx context.Context, s *Subscriber, cat Category) error
+	// SubscribeFeed subscribes the Subscriber to a single Feed regardless of the subscription to its Category.
+	SubscribeFeed(ctx context.Context, s *Subscriber, f Feed) error
+	// UnsubscribeFeed unsubscribes the Subscriber from a single Feed.
+	UnsubscribeFeed(ctx context.Context, s *Subscriber, f Feed) error
+	// MuteFeed stops the updates of a Feed from reaching the Subscriber through the subscription to its Category.
+	MuteFeed(ctx context.Context, s *Subscriber, f Feed) error
+	// UnmuteFeed lets the updates of a muted Feed reach the Subscriber again.
+	UnmuteFeed(ctx context.Context, s *Subscriber, f Feed) error
 	// GetCategorySubscription returns a subscription status of a given Category for a given Subscriber.
+	//   Unread includes the updates from the subcategories, the updates hidden by the Subscriber's filters are not counted.
 	GetCategorySubscription(ctx context.Context, s *Subscriber, cat Category) (*Subscription, error)
 	// GetSubscriptionStatus returns a list of all categories and their subscription status for a given Subscriber.
+	//   Unread of each category includes the updates from its subcategories, the hidden updates are not counted.
 	GetSubscriptionStatus(ctx context.Context, s *Subscriber) ([]Subscription, error)
-	// AddUpdate adds and update to each subscriber of a category. Update has to have its Category property set.
+	// SetFilter sets the Subscriber's Filter for a Category. Unread updates are kept, the ones not passing
+	//   the filters of their category or any of its parents are not counted nor shown.
+	SetFilter(ctx context.Context, s *Subscriber, cat Category, filter Filter) error
+	// AddUpdate adds and update to each subscriber of a category or any of its parents and of the Feed
+	//   set as the Source of the update. Update has to have its Category property set. Updates not passing
+	//   the subscriber's Filter for the category or any of its parents and the ones from muted feeds are not added.
 	AddUpdate(ctx context.Context, up Update) error
 	// ShiftUpdate retrieves an Update for selected Category removing it from Subscriber's list of unread updates.
+	//   Updates hidden by the Subscriber's filters are skipped, as they are by all the getters of unread updates.
 	ShiftUpdate(ctx context.Context, s *Subscriber, cat Category) (*Update, error)
+	// GetUnread retrieves all unread updates of the Subscriber in selected Category, the oldest first.
+	GetUnread(ctx context.Context, s *Subscriber, cat Category) ([]Update, error)
+	// GetUnreadPage retrieves up to limit unread updates of the Subscriber in selected Category starting at offset,
+	//   the oldest first, along with the number of all unread updates in the Category.
+	GetUnreadPage(ctx context.Context, s *Subscriber, cat Category, offset, limit int) ([]Update, int, error)
+	// GetUnreadRange retrieves unread updates of the Subscriber in selected Category published from `from` to `to`
+	//   inclusive, the oldest first.
+	GetUnreadRange(ctx context.Context, s *Subscriber, cat Category, from, to time.Time) ([]Update, error)
+	// GetOldestUnread retrieves up to limit unread updates of the Subscriber in all categories, the oldest first.
+	GetOldestUnread(ctx context.Context, s *Subscriber, limit int) ([]Update, error)
+	// GetUnreadCount retrieves the number of unread updates of all Subscribers.
+	GetUnreadCount(ctx context.Context) (int, error)
+	// TakeUpdate retrieves an Update by ID removing it from Subscriber's list of unread updates.
+	//   Of the concurrent calls for the same Update only one gets it, the others get ErrNotFound.
+	TakeUpdate(ctx context.Context, s *Subscriber, id string) (*Update, error)
+	// MarkUpdatesRead removes the updates from Subscriber's list of unread updates.
+	MarkUpdatesRead(ctx context.Context, s *Subscriber, ups []Update) error
+	// Prune drops unread updates of the Subscriber published before the given date and the oldest ones
+	//   beyond the given number in each category. The number of dropped updates is added to Subscriber's Pruned.
+	//   Zero date and maxUnread ≤0 mean no limit.
+	Prune(ctx context.Context, s *Subscriber, before time.Time, maxUnread int) (int, error)
+	// MarkRead removes all unread updates of the Subscriber in selected Category, or in all categories if cat is nil.
+	//   Returns the number of updates marked as read.
+	MarkRead(ctx context.Context, s *Subscriber, cat *Category) (int, error)
 }
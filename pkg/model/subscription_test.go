@@ -0,0 +1,78 @@
+package model
+
+import "testing"
+
+func TestAggregateSubscriptions(t *testing.T) {
+	subs := []Subscription{
+		{Category: Category{ID: "world"}, Subscribed: true, OwnUnread: 1},
+		{Category: Category{ID: "europe", ParentID: "world"}, OwnUnread: 2},
+		{Category: Category{ID: "france", ParentID: "europe"}, Subscribed: true, OwnUnread: 3},
+		{Category: Category{ID: "sport"}, OwnUnread: 4},
+		{Category: Category{ID: "football", ParentID: "sport"}, Subscribed: true},
+	}
+	AggregateSubscriptions(subs)
+
+	want := []struct {
+		unread        int
+		inherited     bool
+		subcategories int
+	}{
+		{unread: 6, inherited: false, subcategories: 1},
+		{unread: 5, inherited: true, subcategories: 1},
+		{unread: 3, inherited: true, subcategories: 0},
+		{unread: 4, inherited: false, subcategories: 1},
+		{unread: 0, inherited: false, subcategories: 0},
+	}
+	for i, w := range want {
+		sub := subs[i]
+		if sub.Unread != w.unread || sub.Inherited != w.inherited || sub.Subcategories != w.subcategories {
+			t.Errorf("AggregateSubscriptions(): %s: got %d, %v, %d; want %d, %v, %d", sub.Category.ID,
+				sub.Unread, sub.Inherited, sub.Subcategories, w.unread, w.inherited, w.subcategories)
+		}
+	}
+
+	t.Run("ReceivedSubscriptions", func(t *testing.T) {
+		subs := append(subs, Subscription{Category: Category{ID: "tech"}})
+		top := ReceivedSubscriptions(subs, "")
+		if len(top) != 2 || top[0].Category.ID != "world" || top[1].Category.ID != "sport" {
+			t.Errorf("ReceivedSubscriptions(top): got %v; want world and sport", top)
+		}
+		if sport := ReceivedSubscriptions(subs, "sport"); len(sport) != 1 {
+			t.Errorf("ReceivedSubscriptions(sport): got %v; want football", sport)
+		}
+		if all := Subscriptions(subs, ""); len(all) != 3 {
+			t.Errorf("Subscriptions(top): got %v; want world, sport and tech", all)
+		}
+		unread := append(subs, Subscription{Category: Category{ID: "press"}, OwnUnread: 1})
+		if top := ReceivedSubscriptions(unread, ""); len(top) != 3 || top[2].Category.ID != "press" {
+			t.Errorf("ReceivedSubscriptions(top): got %v; want world, sport and press", top)
+		}
+	})
+}
+
+func TestFeedSubscriptions(t *testing.T) {
+	f1 := Feed{ID: "feed-1"}
+	f2 := Feed{ID: "feed-2"}
+	f3 := Feed{ID: "feed-3"}
+	s := &Subscriber{Feeds: []string{f1.ID}, MutedFeeds: []string{f2.ID}}
+
+	t.Run("inherited", func(t *testing.T) {
+		subs := FeedSubscriptions(s, []Feed{f1, f2, f3}, true)
+		want := []bool{true, false, true}
+		for i, w := range want {
+			if subs[i].Receives() != w {
+				t.Errorf("Receives(%s): got %v; want %v", subs[i].Feed.ID, !w, w)
+			}
+		}
+	})
+
+	t.Run("not inherited", func(t *testing.T) {
+		subs := FeedSubscriptions(s, []Feed{f1, f2, f3}, false)
+		want := []bool{true, false, false}
+		for i, w := range want {
+			if subs[i].Receives() != w {
+				t.Errorf("Receives(%s): got %v; want %v", subs[i].Feed.ID, !w, w)
+			}
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// MaxPendingSuggestions limits the number of suggestions of a Subscriber waiting for moderation.
+const MaxPendingSuggestions = 5
+
+// Statuses of a Suggestion.
+const (
+	SuggestionPending  = "pending"
+	SuggestionApproved = "approved"
+	SuggestionRejected = "rejected"
+)
+
+// Suggestion represents a feed the Subscriber suggested to add for everyone.
+//
+//	Suggestions wait for an admin to approve them into a category or to reject them.
+type Suggestion struct {
+	ID         string      // ID is an internal ID.
+	Subscriber *Subscriber // Subscriber is the author of the suggestion.
+	Title      string      // Title is the title of the suggested feed.
+	URL        string      // URL is the link to the suggested feed.
+	Status     string      // Status is the state of the moderation.
+	Category   string      // Category is the ID of the category the feed was added to on approval.
+	Moderator  string      // Moderator is the UserID of the admin who approved or rejected the suggestion.
+	Created    time.Time   // Created is the date when the suggestion was made.
+	Moderated  time.Time   // Moderated is the date when the suggestion was approved or rejected.
+}
+
+// IsPending checks if the Suggestion is waiting for moderation.
+func (s Suggestion) IsPending() bool {
+	return s.Status == SuggestionPending || len(s.Status) == 0
+}
+
+// Approve marks the Suggestion approved into the category by the moderator.
+func (s *Suggestion) Approve(moderator string, cat *Category, t time.Time) {
+	s.Status = SuggestionApproved
+	s.Category = cat.ID
+	s.Moderator = moderator
+	s.Moderated = t
+}
+
+// Reject marks the Suggestion rejected by the moderator.
+func (s *Suggestion) Reject(moderator string, t time.Time) {
+	s.Status = SuggestionRejected
+	s.Moderator = moderator
+	s.Moderated = t
+}
+
+// SuggestionModel is a data model for Suggestion.
+type SuggestionModel interface {
+	// Create saves a Suggestion entity into the DB.
+	Create(ctx context.Context, s *Suggestion) (string, error)
+	// Get retrieves a Suggestion entity along with its Subscriber from the DB.
+	Get(ctx context.Context, id string) (*Suggestion, error)
+	// GetPending retrieves Suggestion entities waiting for moderation from the DB, the oldest first.
+	GetPending(ctx context.Context) ([]Suggestion, error)
+	// GetForSubscriber retrieves Suggestion entities of the Subscriber from the DB, the most recent first.
+	GetForSubscriber(ctx context.Context, s *Subscriber) ([]Suggestion, error)
+	// Save updates a Suggestion entity in the DB.
+	Save(ctx context.Context, s *Suggestion) error
+	// Moderate saves the outcome of the moderation of a Suggestion unless it was moderated meanwhile,
+	//   ErrSuggestionModerated is returned then.
+	Moderate(ctx context.Context, s *Suggestion) error
+	// DeleteForSubscriber deletes all Suggestion's of the Subscriber.
+	DeleteForSubscriber(ctx context.Context, s *Subscriber) error
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSuggestion_Approve(t *testing.T) {
+	s := Suggestion{Status: SuggestionPending}
+	if !s.IsPending() {
+		t.Fatalf("IsPending(): got false for a new suggestion")
+	}
+	moderated := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Approve("telegram:1", &Category{ID: "cat"}, moderated)
+	if s.IsPending() {
+		t.Errorf("IsPending(): got true for an approved suggestion")
+	}
+	if s.Status != SuggestionApproved || s.Category != "cat" || s.Moderator != "telegram:1" || !s.Moderated.Equal(moderated) {
+		t.Errorf("Approve(): got %+v", s)
+	}
+}
+
+func TestSuggestion_Reject(t *testing.T) {
+	s := Suggestion{}
+	if !s.IsPending() {
+		t.Fatalf("IsPending(): got false for a suggestion without status")
+	}
+	s.Reject("telegram:1", time.Now())
+	if s.IsPending() || s.Status != SuggestionRejected || len(s.Category) > 0 {
+		t.Errorf("Reject(): got %+v", s)
+	}
+}
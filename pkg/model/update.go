@@ -12,9 +12,12 @@ type Update struct {
 	Subscriber *Subscriber // Subscriber is the receiver of the update.
 	Category   *Category   // Category is the category of the update.
 	FeedID     string      // FeedID is the external feed ID of the update.
+	Source     string      // Source is the ID of the Feed the update was fetched from.
 	Title      string      // Title is the title of the update.
+	Summary    string      // Summary is the short description of the update.
 	Date       time.Time   // Date is the date when the update was published.
 	URL        string      // URL is the HTTP link to the publication.
+	Image      string      // Image is the HTTP link to the illustration of the publication.
 }
 
 func (up Update) FormatMessage() string {
@@ -30,12 +33,38 @@ func (up Update) FormatMessage() string {
 type UpdateModel interface {
 	// Create saves an Update entity into the DB.
 	Create(ctx context.Context, c *Update) (string, error)
+	// Get retrieves an Update of the Subscriber by ID.
+	Get(ctx context.Context, s *Subscriber, id string) (*Update, error)
 	// GetFromCategory retrieves the oldest available update from selected Category for the Subscriber.
 	GetFromCategory(ctx context.Context, s *Subscriber, cat *Category) (*Update, error)
+	// GetAllFromCategory retrieves all updates available in selected Category for the Subscriber.
+	GetAllFromCategory(ctx context.Context, s *Subscriber, cat *Category) ([]Update, error)
+	// GetPageFromCategory retrieves up to limit updates available in selected Category for the Subscriber
+	//   starting at offset, the oldest first.
+	GetPageFromCategory(ctx context.Context, s *Subscriber, cat *Category, offset, limit int) ([]Update, error)
+	// GetRangeFromCategory retrieves the updates available in selected Category for the Subscriber
+	//   published from `from` to `to` inclusive, the oldest first.
+	GetRangeFromCategory(ctx context.Context, s *Subscriber, cat *Category, from, to time.Time) ([]Update, error)
+	// GetOldest retrieves up to limit oldest updates available in all categories for the Subscriber, all if limit ≤0.
+	GetOldest(ctx context.Context, s *Subscriber, limit int) ([]Update, error)
 	// GetCountInCategory retrieves the number of updates available in selected Category for the Subscriber.
 	GetCountInCategory(ctx context.Context, s *Subscriber, cat *Category) (int, error)
+	// GetCount retrieves the number of updates available to all Subscribers.
+	GetCount(ctx context.Context) (int, error)
+	// Take retrieves an Update of the Subscriber by ID and deletes it in a single transaction.
+	Take(ctx context.Context, s *Subscriber, id string) (*Update, error)
 	// Delete deletes an Update entity from the DB.
 	Delete(ctx context.Context, up *Update) error
+	// DeleteAll deletes the Update entities from the DB.
+	DeleteAll(ctx context.Context, ups []Update) error
+	// DeleteFromCategory deletes all updates in selected Category, or in all categories if cat is nil, for the Subscriber.
+	//   Returns the number of deleted updates.
+	DeleteFromCategory(ctx context.Context, s *Subscriber, cat *Category) (int, error)
+	// Prune deletes updates in selected Category, or in all categories if cat is nil, for the Subscriber published
+	//   before the given date and the oldest ones beyond the given number in each category.
+	//   Returns the number of deleted updates.
+	//   Zero date and keep ≤0 mean no limit.
+	Prune(ctx context.Context, s *Subscriber, cat *Category, before time.Time, keep int) (int, error)
 	// DeleteForSubscriber deletes all Update's for the Subscriber.
 	DeleteForSubscriber(ctx context.Context, s *Subscriber) error
 }
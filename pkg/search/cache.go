@@ -0,0 +1,66 @@
+package search
+
+import (
+	"sync"
+	"time"
+)
+
+// Default limits of the Cache.
+const (
+	DefaultCacheTTL        = 5 * time.Minute
+	DefaultCacheMaxEntries = 1000
+)
+
+// Cache keeps the results of recent queries for a short time.
+type Cache struct {
+	TTL        time.Duration // TTL is how long the results are kept.
+	MaxEntries int           // MaxEntries limits the number of cached results, all expired ones are dropped when reached.
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	result  Result
+	expires time.Time
+}
+
+// NewCache initializes an empty Cache.
+func NewCache() *Cache {
+	return &Cache{
+		TTL:        DefaultCacheTTL,
+		MaxEntries: DefaultCacheMaxEntries,
+		entries:    map[string]cacheEntry{},
+		now:        time.Now,
+	}
+}
+
+// Get returns the cached result of the query if it has not expired yet.
+func (c *Cache) Get(key string) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expires) {
+		return Result{}, false
+	}
+	return e.result, true
+}
+
+// Put caches the result of the query.
+func (c *Cache) Put(key string, res Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= c.MaxEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if len(c.entries) >= c.MaxEntries {
+		c.entries = map[string]cacheEntry{}
+	}
+	c.entries[key] = cacheEntry{result: res, expires: now.Add(c.TTL)}
+}
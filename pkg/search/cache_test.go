@@ -0,0 +1,36 @@
+package search
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := NewCache()
+	c.now = func() time.Time { return now }
+
+	if _, ok := c.Get("q"); ok {
+		t.Fatalf("Get(): got result from empty cache")
+	}
+	c.Put("q", Result{Total: 3})
+	if res, ok := c.Get("q"); !ok || res.Total != 3 {
+		t.Errorf("Get(): got %v, %v; want cached result", res, ok)
+	}
+	now = now.Add(DefaultCacheTTL)
+	if _, ok := c.Get("q"); ok {
+		t.Errorf("Get(): got expired result")
+	}
+
+	c.MaxEntries = 2
+	for i := 0; i < 3; i++ {
+		c.Put(fmt.Sprintf("q%d", i), Result{Total: i})
+	}
+	if len(c.entries) > c.MaxEntries {
+		t.Errorf("Put(): got %d entries; want at most %d", len(c.entries), c.MaxEntries)
+	}
+	if _, ok := c.Get("q2"); !ok {
+		t.Errorf("Get(): the latest result was not cached")
+	}
+}
@@ -0,0 +1,317 @@
+package search
+
+import (
+	"context"
+	"encoding/gob"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Default limits of the Index.
+const (
+	DefaultMaxAge   = 7 * 24 * time.Hour
+	DefaultMaxItems = 10000
+)
+
+// maxSummaryLength limits the length of the summary kept for the search results.
+const maxSummaryLength = 300
+
+// pruneInterval is how often the outdated updates are removed from the Index.
+const pruneInterval = time.Minute
+
+// Weights of the terms depending on where they were found.
+const (
+	titleWeight   = 2
+	summaryWeight = 1
+)
+
+// Result is a page of search results.
+type Result struct {
+	Updates []model.Update // Updates are the matching updates on the page.
+	Total   int            // Total is the number of all matching updates.
+}
+
+// Index is an in-memory inverted index over titles and summaries of recent updates.
+//
+//	Updates are identified by their URL, so the same story ingested in several categories is indexed once.
+//	The Index is local to the instance: it only holds the updates fetched by this instance since it started,
+//	or since the file it was loaded from was saved. With several instances serving the bot,
+//	the results would depend on the instance the query reaches, so the app must be served by a single instance.
+type Index struct {
+	MaxAge   time.Duration // MaxAge is how long the updates are kept in the index.
+	MaxItems int           // MaxItems limits the number of indexed updates, the oldest ones are removed first.
+
+	mu       sync.RWMutex
+	docs     map[int]*document
+	postings map[string]map[int]int
+	byKey    map[string]int
+	nextID   int
+	pruned   time.Time
+	now      func() time.Time
+}
+
+type document struct {
+	update model.Update
+	terms  map[string]int
+}
+
+// NewIndex initializes an empty Index.
+func NewIndex() *Index {
+	return &Index{
+		MaxAge:   DefaultMaxAge,
+		MaxItems: DefaultMaxItems,
+		docs:     map[int]*document{},
+		postings: map[string]map[int]int{},
+		byKey:    map[string]int{},
+		now:      time.Now,
+	}
+}
+
+// Len returns the number of indexed updates.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Get returns the indexed update with the URL.
+func (idx *Index) Get(URL string) (model.Update, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	id, ok := idx.byKey[URL]
+	if !ok {
+		return model.Update{}, false
+	}
+	return idx.docs[id].update, true
+}
+
+// Add indexes the update replacing the previously indexed one with the same URL.
+func (idx *Index) Add(up model.Update) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.add(up)
+	if len(idx.docs) > idx.MaxItems || idx.now().Sub(idx.pruned) > pruneInterval {
+		idx.prune()
+	}
+}
+
+// Search returns a page of the updates containing all the terms of the query, the most relevant and recent first.
+func (idx *Index) Search(query string, offset, limit int) Result {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return Result{}
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var scores map[int]int
+	for _, term := range uniqueTerms(terms) {
+		docs := idx.postings[term]
+		next := make(map[int]int, len(docs))
+		for id, w := range docs {
+			if scores == nil {
+				next[id] = w
+			} else if s, ok := scores[id]; ok {
+				next[id] = s + w
+			}
+		}
+		scores = next
+		if len(scores) == 0 {
+			return Result{}
+		}
+	}
+	minDate := idx.now().Add(-idx.MaxAge)
+	ids := make([]int, 0, len(scores))
+	for id := range scores {
+		if idx.docs[id].update.Date.After(minDate) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return idx.docs[ids[i]].update.Date.After(idx.docs[ids[j]].update.Date)
+	})
+	return idx.page(ids, offset, limit)
+}
+
+// page returns the updates of the sorted documents within the page.
+func (idx *Index) page(ids []int, offset, limit int) Result {
+	res := Result{Total: len(ids)}
+	if offset < 0 || offset >= len(ids) {
+		return res
+	}
+	end := offset + limit
+	if limit <= 0 || end > len(ids) {
+		end = len(ids)
+	}
+	for _, id := range ids[offset:end] {
+		res.Updates = append(res.Updates, idx.docs[id].update)
+	}
+	return res
+}
+
+// Latest returns a page of the most recent updates from the category, or from all categories if categoryID is empty.
+func (idx *Index) Latest(categoryID string, offset, limit int) Result {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	minDate := idx.now().Add(-idx.MaxAge)
+	ids := make([]int, 0, len(idx.docs))
+	for id, doc := range idx.docs {
+		if !doc.update.Date.After(minDate) {
+			continue
+		}
+		if len(categoryID) > 0 && (doc.update.Category == nil || doc.update.Category.ID != categoryID) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return idx.docs[ids[i]].update.Date.After(idx.docs[ids[j]].update.Date)
+	})
+	return idx.page(ids, offset, limit)
+}
+
+// OnUpdate indexes updates ingested by the fetcher.
+func (idx *Index) OnUpdate(_ context.Context, up model.Update) {
+	idx.Add(up)
+}
+
+// Save writes the indexed updates, the index is rebuilt on Load.
+func (idx *Index) Save(w io.Writer) error {
+	idx.mu.RLock()
+	ups := make([]model.Update, 0, len(idx.docs))
+	for _, doc := range idx.docs {
+		ups = append(ups, doc.update)
+	}
+	idx.mu.RUnlock()
+	return gob.NewEncoder(w).Encode(ups)
+}
+
+// Load adds updates previously written by Save to the index.
+func (idx *Index) Load(r io.Reader) error {
+	var ups []model.Update
+	if err := gob.NewDecoder(r).Decode(&ups); err != nil {
+		return err
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, up := range ups {
+		idx.add(up)
+	}
+	idx.prune()
+	return nil
+}
+
+func (idx *Index) add(up model.Update) {
+	key := up.URL
+	if len(key) == 0 {
+		key = up.FeedID
+	}
+	if id, ok := idx.byKey[key]; ok {
+		idx.remove(id)
+	}
+	up.Subscriber = nil
+	up.Summary = truncate(stripTags(up.Summary), maxSummaryLength)
+	doc := &document{update: up, terms: map[string]int{}}
+	for _, term := range tokenize(up.Title) {
+		doc.terms[term] += titleWeight
+	}
+	for _, term := range tokenize(up.Summary) {
+		doc.terms[term] += summaryWeight
+	}
+	id := idx.nextID
+	idx.nextID++
+	idx.docs[id] = doc
+	idx.byKey[key] = id
+	for term, w := range doc.terms {
+		if idx.postings[term] == nil {
+			idx.postings[term] = map[int]int{}
+		}
+		idx.postings[term][id] = w
+	}
+}
+
+func (idx *Index) remove(id int) {
+	doc := idx.docs[id]
+	for term := range doc.terms {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	key := doc.update.URL
+	if len(key) == 0 {
+		key = doc.update.FeedID
+	}
+	delete(idx.byKey, key)
+	delete(idx.docs, id)
+}
+
+// prune removes outdated updates and the oldest ones exceeding MaxItems.
+func (idx *Index) prune() {
+	now := idx.now()
+	idx.pruned = now
+	minDate := now.Add(-idx.MaxAge)
+	for id, doc := range idx.docs {
+		if !doc.update.Date.After(minDate) {
+			idx.remove(id)
+		}
+	}
+	if len(idx.docs) <= idx.MaxItems {
+		return
+	}
+	ids := make([]int, 0, len(idx.docs))
+	for id := range idx.docs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return idx.docs[ids[i]].update.Date.Before(idx.docs[ids[j]].update.Date)
+	})
+	for _, id := range ids[:len(ids)-idx.MaxItems] {
+		idx.remove(id)
+	}
+}
+
+// tokenize splits the text into lowercase words.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0]
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
+
+var tagsRegexp = regexp.MustCompile(`<[^>]*>`)
+
+// stripTags removes HTML markup often present in feed summaries.
+func stripTags(s string) string {
+	return strings.Join(strings.Fields(tagsRegexp.ReplaceAllString(s, " ")), " ")
+}
+
+// truncate shortens the string to n characters.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(r[:n-1])) + "…"
+}
@@ -0,0 +1,134 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"testing"
+	"time"
+)
+
+func newTestIndex(now time.Time) *Index {
+	idx := NewIndex()
+	idx.now = func() time.Time { return now }
+	return idx
+}
+
+func TestIndex_Search(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	idx := newTestIndex(now)
+	idx.Add(model.Update{URL: "u1", Title: "Elections in Europe", Summary: "Voters head to the polls", Date: now.Add(-3 * time.Hour)})
+	idx.Add(model.Update{URL: "u2", Title: "Football results", Summary: "<p>Europe cup <b>elections</b> of the captain</p>", Date: now.Add(-2 * time.Hour)})
+	idx.Add(model.Update{URL: "u3", Title: "Markets rally", Summary: "Stocks rise in Europe", Date: now.Add(-time.Hour)})
+	idx.Add(model.Update{URL: "u4", Title: "Old elections", Date: now.Add(-DefaultMaxAge - time.Hour)})
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "empty", query: " ", want: nil},
+		{name: "missing", query: "weather", want: nil},
+		{name: "title ranks higher", query: "Elections", want: []string{"u1", "u2"}},
+		{name: "all terms", query: "europe elections", want: []string{"u1", "u2"}},
+		{name: "newest first", query: "europe", want: []string{"u1", "u3", "u2"}},
+		{name: "markup ignored", query: "captain b", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := idx.Search(tt.query, 0, 10)
+			if res.Total != len(tt.want) || len(res.Updates) != len(tt.want) {
+				t.Fatalf("Search(%q): got %d of %d results; want %v", tt.query, len(res.Updates), res.Total, tt.want)
+			}
+			for i, up := range res.Updates {
+				if up.URL != tt.want[i] {
+					t.Errorf("Search(%q)[%d]: got %q; want %q", tt.query, i, up.URL, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_SearchPages(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	idx := newTestIndex(now)
+	for i := 0; i < 7; i++ {
+		idx.Add(model.Update{URL: fmt.Sprintf("u%d", i), Title: "news", Date: now.Add(-time.Duration(i) * time.Minute)})
+	}
+	res := idx.Search("news", 5, 5)
+	if res.Total != 7 || len(res.Updates) != 2 {
+		t.Fatalf("Search(): got %d of %d results; want 2 of 7", len(res.Updates), res.Total)
+	}
+	if res.Updates[0].URL != "u5" {
+		t.Errorf("Search(): got %q first; want u5", res.Updates[0].URL)
+	}
+	if res := idx.Search("news", 10, 5); len(res.Updates) != 0 {
+		t.Errorf("Search(): got %d results past the end", len(res.Updates))
+	}
+}
+
+func TestIndex_Add(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("replaces same URL", func(t *testing.T) {
+		idx := newTestIndex(now)
+		idx.Add(model.Update{URL: "u1", Title: "Draft title", Date: now})
+		idx.Add(model.Update{URL: "u1", Title: "Final title", Date: now})
+		if idx.Len() != 1 {
+			t.Errorf("Len(): got %d; want 1", idx.Len())
+		}
+		if up, ok := idx.Get("u1"); !ok || up.Title != "Final title" {
+			t.Errorf("Get(u1): got %q, %v; want the final title", up.Title, ok)
+		}
+		if res := idx.Search("draft", 0, 10); res.Total != 0 {
+			t.Errorf("Search(draft): got %d results for replaced title", res.Total)
+		}
+	})
+
+	t.Run("keeps MaxItems newest", func(t *testing.T) {
+		idx := newTestIndex(now)
+		idx.MaxItems = 3
+		for i := 0; i < 5; i++ {
+			idx.Add(model.Update{URL: fmt.Sprintf("u%d", i), Title: "news", Date: now.Add(time.Duration(i-10) * time.Minute)})
+		}
+		res := idx.Search("news", 0, 10)
+		if res.Total != 3 || res.Updates[2].URL != "u2" {
+			t.Errorf("Search(): got %v; want 3 newest", res.Updates)
+		}
+	})
+}
+
+func TestIndex_SaveLoad(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	idx := newTestIndex(now)
+	idx.Add(model.Update{URL: "u1", Title: "Elections in Europe", Category: &model.Category{ID: "c1", Name: "World"}, Date: now})
+	var buf bytes.Buffer
+	if err := idx.Save(&buf); err != nil {
+		t.Fatalf("Save(): %v", err)
+	}
+	loaded := newTestIndex(now)
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load(): %v", err)
+	}
+	res := loaded.Search("europe", 0, 10)
+	if res.Total != 1 || res.Updates[0].Category == nil || res.Updates[0].Category.Name != "World" {
+		t.Errorf("Search(): got %v; want the saved update", res.Updates)
+	}
+}
+
+func TestIndex_Latest(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	idx := newTestIndex(now)
+	world := &model.Category{ID: "world"}
+	sports := &model.Category{ID: "sports"}
+	idx.Add(model.Update{URL: "u1", Category: world, Date: now.Add(-3 * time.Hour)})
+	idx.Add(model.Update{URL: "u2", Category: sports, Date: now.Add(-2 * time.Hour)})
+	idx.Add(model.Update{URL: "u3", Category: world, Date: now.Add(-time.Hour)})
+
+	if res := idx.Latest("world", 0, 10); res.Total != 2 || res.Updates[0].URL != "u3" {
+		t.Errorf("Latest(world): got %v; want u3, u1", res.Updates)
+	}
+	if res := idx.Latest("", 0, 2); res.Total != 3 || len(res.Updates) != 2 || res.Updates[1].URL != "u2" {
+		t.Errorf("Latest(): got %v of %d; want u3, u2 of 3", res.Updates, res.Total)
+	}
+}
@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Scheduler periodically runs a job in the background.
+type Scheduler struct {
+	interval time.Duration
+	job      func(ctx context.Context)
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New initializes new Scheduler running the job every interval.
+func New(interval time.Duration, job func(ctx context.Context)) *Scheduler {
+	return &Scheduler{
+		interval: interval,
+		job:      job,
+	}
+}
+
+// Start runs the job immediately and then on every tick until Stop is called.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.run(ctx, s.done)
+}
+
+// Stop cancels the job being run and waits for it to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+func (s *Scheduler) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.job(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	var runs int32
+	s := New(10*time.Millisecond, func(_ context.Context) {
+		atomic.AddInt32(&runs, 1)
+	})
+	s.Start()
+	time.Sleep(55 * time.Millisecond)
+	s.Stop()
+
+	got := atomic.LoadInt32(&runs)
+	if got < 2 {
+		t.Errorf("got %d runs, want at least 2", got)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if after := atomic.LoadInt32(&runs); after != got {
+		t.Errorf("job was run after Stop()")
+	}
+}
+
+func TestScheduler_StopCancelsJob(t *testing.T) {
+	cancelled := make(chan struct{})
+	s := New(time.Hour, func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	})
+	s.Start()
+	s.Stop()
+	select {
+	case <-cancelled:
+	default:
+		t.Errorf("job context was not cancelled")
+	}
+}
+
+func TestScheduler_StopNotStarted(t *testing.T) {
+	s := New(time.Hour, func(_ context.Context) {})
+	s.Stop()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// loadSearchIndex restores the search index from the configured file.
+func (a *App) loadSearchIndex() {
+	if len(a.Config.SearchIndexPath) == 0 {
+		return
+	}
+	f, err := os.Open(a.Config.SearchIndexPath)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		log.Printf("[search] Failed to open index: %v", err)
+		return
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	if err := a.Search.Load(f); err != nil {
+		log.Printf("[search] Failed to load index: %v", err)
+		return
+	}
+	log.Printf("[search] Loaded %d updates", a.Search.Len())
+}
+
+// saveSearchIndex writes the search index to the configured file replacing it atomically.
+func (a *App) saveSearchIndex() {
+	if len(a.Config.SearchIndexPath) == 0 {
+		return
+	}
+	dir, name := filepath.Split(a.Config.SearchIndexPath)
+	if len(dir) == 0 {
+		dir = "."
+	}
+	f, err := os.CreateTemp(dir, name+".*")
+	if err != nil {
+		log.Printf("[search] Failed to create index file: %v", err)
+		return
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	if err := a.Search.Save(f); err != nil {
+		_ = f.Close()
+		log.Printf("[search] Failed to save index: %v", err)
+		return
+	}
+	if err := f.Close(); err != nil {
+		log.Printf("[search] Failed to save index: %v", err)
+		return
+	}
+	if err := os.Rename(f.Name(), a.Config.SearchIndexPath); err != nil {
+		log.Printf("[search] Failed to replace index: %v", err)
+	}
+}
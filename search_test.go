@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/d-ashesss/news-feed-bot/pkg/model"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSearchIndexPersistence(t *testing.T) {
+	dir, err := os.MkdirTemp("", "search")
+	if err != nil {
+		t.Fatalf("MkdirTemp(): %v", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	config := Config{SearchIndexPath: filepath.Join(dir, "index.gob")}
+
+	at := NewAppTestWithConfig(config)
+	at.app.Search.Add(model.Update{URL: "u1", Title: "Elections in Europe", Date: time.Now()})
+	at.app.saveSearchIndex()
+
+	restarted := NewAppTestWithConfig(config)
+	if res := restarted.app.Search.Search("europe", 0, 10); res.Total != 1 {
+		t.Errorf("Search(): got %d results after restart; want 1", res.Total)
+	}
+}
+
+func TestFormatStoryHTML(t *testing.T) {
+	up := model.Update{
+		Title:    "Q&A <live>",
+		Summary:  "Answers",
+		Category: &model.Category{Name: "World"},
+		Date:     time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+		URL:      "https://example.com/?a=1&b=2",
+	}
+	want := "<b>Q&amp;A &lt;live&gt;</b>\nAnswers\n\n<i>World</i> | Tue, 01 Jun 2021 12:00:00 UTC\nhttps://example.com/?a=1&amp;b=2"
+	if got := formatStoryHTML(up); got != want {
+		t.Errorf("formatStoryHTML() = %q; want %q", got, want)
+	}
+}